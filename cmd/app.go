@@ -7,6 +7,7 @@ import (
 	webscan "github.com/Method-Security/webscan/generated/go"
 	"github.com/Method-Security/webscan/internal/graphql"
 	"github.com/Method-Security/webscan/internal/grpc"
+	"github.com/Method-Security/webscan/internal/reportfile"
 	"github.com/Method-Security/webscan/internal/requests"
 	"github.com/Method-Security/webscan/internal/swagger"
 	"github.com/Method-Security/webscan/internal/vuln"
@@ -25,6 +26,7 @@ func (a *WebScan) InitAppCommand() {
 	a.initFingerprintCommand()
 	a.initEnumerateCommand()
 	a.initRequestsCommand()
+	a.initAuthzCommand()
 }
 
 func (a *WebScan) initFingerprintCommand() {
@@ -236,9 +238,71 @@ func (a *WebScan) initRequestsCommand() {
 		Short: "Perform custom requests against a target route",
 		Long: `Perform custom requests against a target route of an API Application using specified parameters.
 		
-The requests command allows you to send custom HTTP requests to a target URL with specified method, path, and optional parameters including query, path, header, body, form, and multipart form data.`,
+The requests command allows you to send custom HTTP requests to a target URL with specified method, path, and optional parameters including query, path, header, body, form, and multipart form data.
+
+With --from-routes, a request is generated for every route of a routes report written by the enumerate command, using the example, enum, default, format and range constraints of each route's request schema.
+
+With --input or --raw-request, a batch of requests is read from a JSONL file with one request parameters object per line, or from a file of HTTP requests in wire format such as those saved from Burp Suite. The requests are sent concurrently and their reports aggregated.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			defer a.OutputSignal.PanicHandler(cmd.Context())
+			vulnTypes, _ := cmd.Flags().GetStringSlice("vulnType")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+
+			inputFile, _ := cmd.Flags().GetString("input")
+			rawRequestFile, _ := cmd.Flags().GetString("raw-request")
+			if inputFile != "" || rawRequestFile != "" {
+				baseURL, _ := cmd.Flags().GetString("baseUrl")
+				path, _ := cmd.Flags().GetString("path")
+				method, _ := cmd.Flags().GetString("method")
+
+				var batch []webscan.RequestParams
+				var err error
+				target := inputFile
+				if inputFile != "" {
+					batch, err = requests.LoadRequestsFile(inputFile)
+				} else {
+					target = rawRequestFile
+					batch, err = requests.LoadRawRequestFile(rawRequestFile, baseURL)
+				}
+				if err != nil {
+					a.handleError(cmd, err.Error())
+					return
+				}
+
+				// The path and method flags apply to requests of the batch that do not specify their own
+				for i := range batch {
+					if batch[i].Path == nil && path != "" {
+						batch[i].Path = &path
+					}
+					if batch[i].Method == nil && method != "" {
+						batch[i].Method = &method
+					}
+				}
+
+				report := requests.PerformBatchScan(cmd.Context(), target, baseURL, cmd.Flag("headerParams").Value.String(), batch, vulnTypes, concurrency)
+				if len(report.Errors) > 0 {
+					a.OutputSignal.Status = 1
+				}
+				a.OutputSignal.Content = report
+				return
+			}
+
+			fromRoutes, _ := cmd.Flags().GetString("from-routes")
+			if fromRoutes != "" {
+				var routesReport webscan.RoutesReport
+				if err := reportfile.Load(fromRoutes, &routesReport); err != nil {
+					a.handleError(cmd, err.Error())
+					return
+				}
+				baseURL, _ := cmd.Flags().GetString("baseUrl")
+				report := requests.PerformRoutesScan(cmd.Context(), routesReport, baseURL, cmd.Flag("headerParams").Value.String(), vulnTypes, concurrency)
+				if len(report.Errors) > 0 {
+					a.OutputSignal.Status = 1
+				}
+				a.OutputSignal.Content = report
+				return
+			}
+
 			baseURL, err := cmd.Flags().GetString("baseUrl")
 			if err != nil || baseURL == "" {
 				a.handleError(cmd, "baseUrl flag is required")
@@ -266,9 +330,7 @@ The requests command allows you to send custom HTTP requests to a target URL wit
 				MultipartParams: cmd.Flag("multipartParams").Value.String(),
 			}
 
-			vulnTypes, _ := cmd.Flags().GetStringSlice("vulnType")
-
-			report := requests.PerformRequestScan(baseURL, path, method, params, vulnTypes)
+			report := requests.PerformRequestScan(cmd.Context(), baseURL, path, method, params, vulnTypes)
 
 			if len(report.Errors) > 0 {
 				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
@@ -279,7 +341,7 @@ The requests command allows you to send custom HTTP requests to a target URL wit
 		},
 	}
 
-	requestsCmd.Flags().String("baseUrl", "", "Base URL of the target")
+	requestsCmd.Flags().String("baseUrl", "", "Base URL of the target, defaults to the base endpoint URL of the routes report with --from-routes and to the request's Host with --raw-request")
 	requestsCmd.Flags().String("path", "", "Path to append to the base URL")
 	requestsCmd.Flags().String("method", "", "HTTP method to use (GET, POST, etc.)")
 	requestsCmd.Flags().String("pathParams", "", "Path parameters as a JSON string (optional)")
@@ -288,7 +350,11 @@ The requests command allows you to send custom HTTP requests to a target URL wit
 	requestsCmd.Flags().String("bodyParams", "", "Body parameters as a JSON string (optional)")
 	requestsCmd.Flags().String("formParams", "", "Form parameters as a JSON string (optional)")
 	requestsCmd.Flags().String("multipartParams", "", "Multipart form parameters as a JSON string (optional)")
-	requestsCmd.Flags().StringSlice("vulnType", []string{}, "Types of vulnerabilities to check (optional)")
+	requestsCmd.Flags().String("from-routes", "", "Routes report file written by app enumerate; sends a generated request for every route instead of a single request (optional)")
+	requestsCmd.Flags().String("input", "", "JSONL file with one request parameters object per line; sends every request of the file (optional)")
+	requestsCmd.Flags().String("raw-request", "", "File of one or more HTTP requests in wire format or a Burp Suite XML export; sends every request of the file (optional)")
+	requestsCmd.Flags().Int("concurrency", 5, "Number of requests sent concurrently with --from-routes, --input or --raw-request")
+	requestsCmd.Flags().StringSlice("vulnType", []string{}, "Types of vulnerabilities to check, e.g. SQL, XSS, COMMAND, TEMPLATE, NOSQL, AUTH, SENSITIVEERROR, MASSASSIGNMENT (optional)")
 
 	a.AppCmd.AddCommand(requestsCmd)
 }
@@ -297,3 +363,96 @@ func (a *WebScan) handleError(cmd *cobra.Command, errMsg string) {
 	a.OutputSignal.ErrorMessage = &errMsg
 	a.OutputSignal.Status = 1
 }
+
+func (a *WebScan) initAuthzCommand() {
+	authzCmd := &cobra.Command{
+		Use:   "authz",
+		Short: "Test routes for broken object level authorization",
+		Long: `Test routes of an API Application for broken object level authorization (BOLA/IDOR) using two or more identities.
+
+Every object-scoped request is sent as each identity, then replayed as the other identities and with its object IDs swapped for those of another identity or incremented. Responses that return another identity's data are reported.
+
+The identities file lists the headers, cookies, object IDs and data markers of each identity. The requests come from a routes report written by the enumerate command (--from-routes), a route capture report (--from-capture), a JSONL requests file (--input) or a raw HTTP request file (--raw-request).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			defer a.OutputSignal.PanicHandler(cmd.Context())
+
+			identitiesFile, err := cmd.Flags().GetString("identities")
+			if err != nil || identitiesFile == "" {
+				a.handleError(cmd, "identities flag is required")
+				return
+			}
+			var identities webscan.AuthzIdentities
+			if err := reportfile.Load(identitiesFile, &identities); err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+
+			baseURL, _ := cmd.Flags().GetString("baseUrl")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			fromRoutes, _ := cmd.Flags().GetString("from-routes")
+			fromCapture, _ := cmd.Flags().GetString("from-capture")
+			inputFile, _ := cmd.Flags().GetString("input")
+			rawRequestFile, _ := cmd.Flags().GetString("raw-request")
+
+			var batch []webscan.RequestParams
+			var loadErrors []string
+			target := ""
+			switch {
+			case fromRoutes != "":
+				var routesReport webscan.RoutesReport
+				if err := reportfile.Load(fromRoutes, &routesReport); err != nil {
+					a.handleError(cmd, err.Error())
+					return
+				}
+				target = routesReport.Target
+				batch, loadErrors = requests.RoutesReportRequests(routesReport, baseURL)
+			case fromCapture != "":
+				var captureReport webscan.RouteCaptureReport
+				if err := reportfile.Load(fromCapture, &captureReport); err != nil {
+					a.handleError(cmd, err.Error())
+					return
+				}
+				target = captureReport.Target
+				batch, loadErrors = requests.RouteCaptureRequests(captureReport)
+			case inputFile != "":
+				target = inputFile
+				batch, err = requests.LoadRequestsFile(inputFile)
+			case rawRequestFile != "":
+				target = rawRequestFile
+				batch, err = requests.LoadRawRequestFile(rawRequestFile, baseURL)
+			default:
+				a.handleError(cmd, "one of the from-routes, from-capture, input or raw-request flags is required")
+				return
+			}
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+
+			if baseURL != "" {
+				for i := range batch {
+					if batch[i].BaseUrl == nil || *batch[i].BaseUrl == "" || fromCapture != "" {
+						batch[i].BaseUrl = &baseURL
+					}
+				}
+			}
+
+			report := requests.PerformAuthzScan(cmd.Context(), target, batch, identities.Identities, concurrency)
+			report.Errors = append(loadErrors, report.Errors...)
+			if len(report.Errors) > 0 {
+				a.OutputSignal.Status = 1
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	authzCmd.Flags().String("identities", "", "JSON or YAML file listing the identities to test with, at least two")
+	authzCmd.Flags().String("from-routes", "", "Routes report file written by app enumerate")
+	authzCmd.Flags().String("from-capture", "", "Route capture report file written by routecapture")
+	authzCmd.Flags().String("input", "", "JSONL file with one request parameters object per line")
+	authzCmd.Flags().String("raw-request", "", "File of one or more HTTP requests in wire format or a Burp Suite XML export")
+	authzCmd.Flags().String("baseUrl", "", "Base URL of the target, overriding the one recorded in the routes (optional)")
+	authzCmd.Flags().Int("concurrency", 5, "Number of routes tested concurrently")
+
+	a.AppCmd.AddCommand(authzCmd)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/diff"
+	"github.com/spf13/cobra"
+)
+
+// InitDiffCommand initializes the diff command for the webscan CLI. This command compares two reports of the same
+// scan, such as those of two nightly runs, so that only the drift between them is reported.
+func (a *WebScan) InitDiffCommand() {
+	diffCmd := &cobra.Command{
+		Use:   "diff <baseline> <current>",
+		Short: "Compare two reports and emit only their changes",
+		Long: `Compare two report files of the same type, in the signal, json or yaml output formats, and report the items of
+the current report that are new, changed or removed since the baseline report. Routes, route capture, web server,
+fuzz path, vuln and fingerprint reports are supported. Items are matched by stable keys, such as the method and path
+of routes, the ID of vulnerability findings, the module of web server attempts and the subject of certificates.
+Certificates of the current fingerprint report that expire within the expiry window are reported as expiring.`,
+		Args:        cobra.ExactArgs(2),
+		Annotations: map[string]string{noStoreAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			defer a.OutputSignal.PanicHandler(cmd.Context())
+			reportType, err := cmd.Flags().GetString("type")
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			expiryWindow, err := cmd.Flags().GetDuration("expiry-window")
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+
+			options := diff.Options{ExpiryWindow: expiryWindow}
+			if reportType != "" {
+				typeEnum, err := webscan.NewDiffReportTypeFromString(strings.ToUpper(reportType))
+				if err != nil {
+					a.handleError(cmd, err.Error())
+					return
+				}
+				options.Type = &typeEnum
+			}
+
+			report, err := diff.Compare(cmd.Context(), args[0], args[1], options)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+	diffCmd.Flags().String("type", "", "Type of the reports (routes, route_capture, web_server, fuzz_path, vuln, fingerprint), identified from their content by default")
+	diffCmd.Flags().Duration("expiry-window", 30*24*time.Hour, "Time before their expiry that certificates are reported as expiring")
+
+	a.RootCmd.AddCommand(diffCmd)
+}
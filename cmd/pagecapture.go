@@ -163,7 +163,7 @@ func (a *WebScan) InitPagecaptureCommand() {
 
 			timeout, _ := cmd.Flags().GetInt("timeout")
 
-			capturer := capture.NewRequestPageCapturer(insecure, timeout)
+			capturer := capture.NewRequestPageCapturer(cmd.Context(), insecure, timeout)
 			result, err := capturer.Capture(cmd.Context(), target, &capture.Options{})
 			if err != nil {
 				a.OutputSignal.AddError(err)
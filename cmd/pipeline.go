@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/Method-Security/webscan/internal/pipeline"
+	"github.com/spf13/cobra"
+)
+
+// InitPipelineCommand initializes the pipeline command for the webscan CLI. This command runs declarative pipelines
+// that chain webscan scans, feeding the results of each stage to the next.
+func (a *WebScan) InitPipelineCommand() {
+	pipelineCmd := &cobra.Command{
+		Use:   "pipeline",
+		Short: "Run declarative multi-stage scan pipelines",
+		Long:  `Run declarative multi-stage scan pipelines`,
+	}
+
+	runCmd := &cobra.Command{
+		Use:   "run <pipeline.yaml>",
+		Short: "Run the stages of a pipeline definition file",
+		Long: `Run the stages of a YAML or JSON pipeline definition file as a DAG. Each stage runs a scan against the targets
+of the definition and those selected from the reports of other stages, such as the URLs of a web server probe or the
+matched URLs of application fingerprint findings with a tag. Stages run as soon as the stages they select from
+completed, and the report lists the reports of every stage.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			defer a.OutputSignal.PanicHandler(cmd.Context())
+			definition, err := pipeline.Load(args[0])
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			report, err := pipeline.Run(cmd.Context(), definition)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+
+	pipelineCmd.AddCommand(runCmd)
+	a.RootCmd.AddCommand(pipelineCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Method-Security/webscan/internal/htmlreport"
+	"github.com/spf13/cobra"
+)
+
+// InitReportCommand initializes the report command for the webscan CLI. This command works on reports written by other
+// webscan commands, rendering them in formats meant to be read by people rather than by other tools.
+func (a *WebScan) InitReportCommand() {
+	a.ReportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "Work with reports written by other webscan commands",
+		Long:  `Work with reports written by other webscan commands`,
+	}
+
+	renderCmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render a report file as a self-contained HTML page",
+		Long: `Render a report file written by any webscan command, in the signal, json or yaml output formats, as a single
+self-contained HTML page. The page lists findings in sortable tables, the request and response evidence of web server
+attempts, embedded screenshots and route inventories. The page is written to the output file, or to STDOUT when no
+output file is provided.`,
+		Annotations: map[string]string{outputFormatAnnotation: "html", noStoreAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			defer a.OutputSignal.PanicHandler(cmd.Context())
+			input, err := cmd.Flags().GetString("input")
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			if input == "" {
+				a.handleError(cmd, "--input is required")
+				return
+			}
+
+			data, err := os.ReadFile(input)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			report, err := htmlreport.Decode(data)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+	renderCmd.Flags().String("input", "", "Report file to render, in the signal, json or yaml output format")
+
+	a.ReportCmd.AddCommand(renderCmd)
+	a.RootCmd.AddCommand(a.ReportCmd)
+}
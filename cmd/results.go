@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/results"
+	"github.com/spf13/cobra"
+)
+
+// noStoreAnnotation is the command annotation of commands whose runs are not saved to the results store, such as those
+// reading reports or results saved before.
+const noStoreAnnotation = "webscan/no-store"
+
+// InitResultsCommand initializes the results command for the webscan CLI. This command works on the local results
+// store that runs are saved to with --store.
+func (a *WebScan) InitResultsCommand() {
+	resultsCmd := &cobra.Command{
+		Use:   "results",
+		Short: "Query the history of runs saved to the results store",
+		Long:  `Query the history of runs saved to the results store`,
+	}
+
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query the records of the runs saved to the results store",
+		Long: `Query the targets, URLs, routes, findings, certificates and screenshots saved to the results store of --store by
+every run of a webscan command. Records are filtered by target, record type, severity, module, URL and the date of
+their run, and are listed along with their runs, those of the most recent runs first.`,
+		Annotations: map[string]string{noStoreAnnotation: "true"},
+		Run: func(cmd *cobra.Command, args []string) {
+			defer a.OutputSignal.PanicHandler(cmd.Context())
+			if a.RootFlags.Store == "" {
+				a.handleError(cmd, "results query requires --store")
+				return
+			}
+			query, err := resultsQuery(cmd)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+
+			store, err := results.OpenStore(a.RootFlags.Store)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			defer store.Close()
+			report, err := store.Query(query)
+			if err != nil {
+				a.handleError(cmd, err.Error())
+				return
+			}
+			a.OutputSignal.Content = report
+		},
+	}
+	queryCmd.Flags().StringSlice("target", []string{}, "Targets of the records, matched by URL or host")
+	queryCmd.Flags().StringSlice("type", []string{}, "Types of the records (target, url, route, finding, certificate, screenshot)")
+	queryCmd.Flags().StringSlice("severity", []string{}, "Severities of the findings (info, low, medium, high, critical)")
+	queryCmd.Flags().StringSlice("module", []string{}, "Modules of the records, such as nuclei template IDs, web server modules and vulnerability types")
+	queryCmd.Flags().String("url", "", "Text the URLs of the records contain, such as a path")
+	queryCmd.Flags().String("since", "", "Date or RFC 3339 time the runs of the records started at or after")
+	queryCmd.Flags().String("until", "", "Date or RFC 3339 time the runs of the records started at or before")
+	queryCmd.Flags().Int("limit", 0, "Maximum number of records to list, 0 for no limit")
+
+	resultsCmd.AddCommand(queryCmd)
+	a.RootCmd.AddCommand(resultsCmd)
+}
+
+// resultsQuery builds the query of the flags of the query command.
+func resultsQuery(cmd *cobra.Command) (results.Query, error) {
+	query := results.Query{}
+	var err error
+	if query.Targets, err = cmd.Flags().GetStringSlice("target"); err != nil {
+		return query, err
+	}
+	if query.Severities, err = cmd.Flags().GetStringSlice("severity"); err != nil {
+		return query, err
+	}
+	if query.Modules, err = cmd.Flags().GetStringSlice("module"); err != nil {
+		return query, err
+	}
+	if query.URL, err = cmd.Flags().GetString("url"); err != nil {
+		return query, err
+	}
+	if query.Limit, err = cmd.Flags().GetInt("limit"); err != nil {
+		return query, err
+	}
+	types, err := cmd.Flags().GetStringSlice("type")
+	if err != nil {
+		return query, err
+	}
+	for _, t := range types {
+		recordType, err := webscan.NewResultRecordTypeFromString(strings.ToUpper(t))
+		if err != nil {
+			return query, err
+		}
+		query.Types = append(query.Types, recordType)
+	}
+
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return query, err
+	}
+	if query.Since, err = parseDate(since, false); err != nil {
+		return query, err
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return query, err
+	}
+	if query.Until, err = parseDate(until, true); err != nil {
+		return query, err
+	}
+	return query, nil
+}
+
+// parseDate parses an RFC 3339 time or a date, which is the start of the day or its end when endOfDay is set. An empty
+// value is no time.
+func parseDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %s, expected a date such as 2006-01-02 or an RFC 3339 time", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// saveResults saves the run of the command and the records of its report to the results store of --store.
+func (a *WebScan) saveResults(cmd *cobra.Command) error {
+	store, err := results.OpenStore(a.RootFlags.Store)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	run := &webscan.ResultRun{
+		Command:      strings.TrimPrefix(cmd.CommandPath(), a.RootCmd.Name()+" "),
+		StartedAt:    time.Time(a.OutputSignal.StartedAt),
+		Status:       a.OutputSignal.Status,
+		ErrorMessage: a.OutputSignal.ErrorMessage,
+	}
+	if a.OutputSignal.CompletedAt != nil {
+		completedAt := time.Time(*a.OutputSignal.CompletedAt)
+		run.CompletedAt = &completedAt
+	}
+	return store.Save(run, results.Records(a.OutputSignal.Content))
+}
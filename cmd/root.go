@@ -4,18 +4,38 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	ossignal "os/signal"
+	"reflect"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/Method-Security/pkg/signal"
 	"github.com/Method-Security/pkg/writer"
+	"github.com/Method-Security/webscan/internal/auth"
+	"github.com/Method-Security/webscan/internal/checkpoint"
 	"github.com/Method-Security/webscan/internal/config"
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/htmlreport"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/sarif"
+	"github.com/Method-Security/webscan/internal/scope"
+	"github.com/Method-Security/webscan/internal/stream"
 	"github.com/palantir/pkg/datetime"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 	"github.com/spf13/cobra"
 )
 
+// outputFormatAnnotation is the command annotation that forces the output format of a command regardless of the output
+// flag, e.g. for commands whose only purpose is to produce an HTML page.
+const outputFormatAnnotation = "webscan/output-format"
+
 // WebScan is the main struct for the webscan CLI. It contains both the root command and all subcommands that can be
 // invoked during the execution of the CLI. It also is responsible for managing the output configuration as well as the
 // output signal itself, which will be written after the execution of the invoked command's Run function.
@@ -31,6 +51,7 @@ type WebScan struct {
 	SpiderCmd    *cobra.Command
 	VulnCmd      *cobra.Command
 	AppCmd       *cobra.Command
+	ReportCmd    *cobra.Command
 }
 
 // NewWebScan creates a new WebScan struct with the provided version string. The Webscan struct is used throughout the
@@ -57,6 +78,11 @@ func NewWebScan(version string) *WebScan {
 func (a *WebScan) InitRootCommand() {
 	var outputFormat string
 	var outputFile string
+	var emitter *stream.Emitter
+	var streamFile *os.File
+	var enforcedScope *scope.Scope
+	var recorder *har.Recorder
+	var stopSignals context.CancelFunc
 	a.RootCmd = &cobra.Command{
 		Use:   "webscan",
 		Short: "Perform a web scan against a target",
@@ -73,12 +99,110 @@ func (a *WebScan) InitRootCommand() {
 				outputFilePointer = nil
 			}
 			a.OutputConfig = writer.NewOutputConfig(outputFilePointer, format)
+			a.OutputSignal.StartedAt = datetime.DateTime(time.Now())
 			cmd.SetContext(svc1log.WithLogger(cmd.Context(), config.InitializeLogging(cmd, &a.RootFlags)))
+			factory, err := httpclient.NewFactory(a.RootFlags.HTTPClient)
+			if err != nil {
+				return err
+			}
+			if a.RootFlags.AuthProfileFile != "" {
+				profile, err := auth.LoadProfile(a.RootFlags.AuthProfileFile, a.RootFlags.AuthProfile)
+				if err != nil {
+					return err
+				}
+				authenticator, err := auth.NewAuthenticator(cmd.Context(), profile, factory)
+				if err != nil {
+					return err
+				}
+				factory = factory.WithAuthenticator(authenticator)
+			} else if a.RootFlags.AuthProfile != "" {
+				return fmt.Errorf("--auth-profile requires --auth-profile-file")
+			}
+			if a.RootFlags.ScopeFile != "" {
+				enforcedScope, err = scope.Load(a.RootFlags.ScopeFile)
+				if err != nil {
+					return err
+				}
+				factory = factory.WithScope(enforcedScope)
+			}
+			if a.RootFlags.HAROut != "" {
+				// Requests are recorded as sent by the command unless a module of the command names itself
+				recorder = har.NewRecorder(a.Version, strings.TrimPrefix(cmd.CommandPath(), a.RootCmd.Name()+" "))
+				// The headers of the authentication profile and those of --header may hold credentials
+				if authenticator, ok := factory.Authenticator().(*auth.Authenticator); ok {
+					recorder.RedactHeaders(authenticator.HeaderNames()...)
+				}
+				for name := range factory.Headers() {
+					if name != "User-Agent" {
+						recorder.RedactHeaders(name)
+					}
+				}
+				factory = factory.WithRecorder(recorder)
+			}
+			if a.RootFlags.Replay != "" {
+				replayer, err := har.LoadReplayer(a.RootFlags.Replay)
+				if err != nil {
+					return err
+				}
+				factory = factory.WithReplayer(replayer)
+			}
+			cmd.SetContext(httpclient.WithFactory(cmd.Context(), factory))
+			if a.RootFlags.StateDir != "" {
+				store, err := checkpoint.NewStore(a.RootFlags.StateDir, a.RootFlags.Resume)
+				if err != nil {
+					return err
+				}
+				// Interrupted scans return their partial results, which are checkpointed and reported. A second
+				// interrupt stops the command right away.
+				ctx, stop := ossignal.NotifyContext(checkpoint.WithStore(cmd.Context(), store), syscall.SIGINT, syscall.SIGTERM)
+				context.AfterFunc(ctx, stop)
+				stopSignals = stop
+				cmd.SetContext(ctx)
+			} else if a.RootFlags.Resume {
+				return fmt.Errorf("--resume requires --state-dir")
+			}
+			if strings.ToLower(outputFormat) == "jsonl" {
+				// Results are streamed as they arrive, so the output file is opened before the command runs
+				var streamWriter io.Writer = cmd.OutOrStdout()
+				if outputFile != "" {
+					streamFile, err = os.Create(outputFile)
+					if err != nil {
+						return fmt.Errorf("failed to create output file: %v", err)
+					}
+					streamWriter = streamFile
+				}
+				emitter = stream.NewEmitter(streamWriter)
+				cmd.SetContext(stream.WithEmitter(cmd.Context(), emitter))
+			}
 			return nil
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, _ []string) error {
+			if stopSignals != nil {
+				stopSignals()
+			}
 			completedAt := datetime.DateTime(time.Now())
 			a.OutputSignal.CompletedAt = &completedAt
+			a.OutputSignal.Content = withErrors(a.OutputSignal.Content, enforcedScope.Blocked())
+			if err := recorder.Write(a.RootFlags.HAROut); err != nil {
+				return err
+			}
+			if _, skip := cmd.Annotations[noStoreAnnotation]; a.RootFlags.Store != "" && a.OutputSignal.Content != nil && !skip {
+				if err := a.saveResults(cmd); err != nil {
+					return err
+				}
+			}
+			format := strings.ToLower(outputFormat)
+			if forced, ok := cmd.Annotations[outputFormatAnnotation]; ok {
+				format = forced
+			}
+			switch format {
+			case "html":
+				return a.writeHTML(cmd, outputFile)
+			case "sarif":
+				return a.writeSARIF(cmd, outputFile)
+			case "jsonl":
+				return a.closeStream(emitter, streamFile)
+			}
 			return writer.Write(
 				a.OutputSignal.Content,
 				a.OutputConfig,
@@ -93,7 +217,30 @@ func (a *WebScan) InitRootCommand() {
 	a.RootCmd.PersistentFlags().BoolVarP(&a.RootFlags.Quiet, "quiet", "q", false, "Suppress output")
 	a.RootCmd.PersistentFlags().BoolVarP(&a.RootFlags.Verbose, "verbose", "v", false, "Verbose output")
 	a.RootCmd.PersistentFlags().StringVarP(&outputFile, "output-file", "f", "", "Path to output file. If blank, will output to STDOUT")
-	a.RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "signal", "Output format (signal, json, yaml). Default value is signal")
+	a.RootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "signal", "Output format (signal, json, yaml, sarif, jsonl, html). Default value is signal")
+
+	httpFlags := &a.RootFlags.HTTPClient
+	a.RootCmd.PersistentFlags().StringVar(&httpFlags.Proxy, "proxy", "", "Proxy URL to send all requests through (http, https or socks5)")
+	a.RootCmd.PersistentFlags().StringVar(&httpFlags.CACertFile, "ca-cert", "", "PEM file of CA certificates to trust in addition to the system roots")
+	a.RootCmd.PersistentFlags().StringVar(&httpFlags.ClientCertFile, "client-cert", "", "PEM file of the client certificate to present for mTLS")
+	a.RootCmd.PersistentFlags().StringVar(&httpFlags.ClientKeyFile, "client-key", "", "PEM file of the private key of the client certificate")
+	a.RootCmd.PersistentFlags().BoolVar(&httpFlags.InsecureSkipVerify, "skip-tls-verify", false, "Skip the verification of the TLS certificates of targets")
+	a.RootCmd.PersistentFlags().StringArrayVar(&httpFlags.Headers, "header", []string{}, "Header to send with every request as 'Name: value', can be repeated")
+	a.RootCmd.PersistentFlags().StringVar(&httpFlags.UserAgent, "user-agent", "", "User agent to send with every request")
+	a.RootCmd.PersistentFlags().IntVar(&httpFlags.Retries, "retries", 0, "Number of times to retry requests after network errors and 429, 502, 503 or 504 responses")
+	a.RootCmd.PersistentFlags().DurationVar(&httpFlags.Timeout, "http-timeout", 30*time.Second, "Timeout of requests for commands without a timeout flag of their own")
+	a.RootCmd.PersistentFlags().Float64Var(&httpFlags.RateLimit, "rate-limit", 0, "Maximum number of requests per second across all hosts, 0 for no limit")
+	a.RootCmd.PersistentFlags().IntVar(&httpFlags.MaxConcurrencyPerHost, "max-concurrency-per-host", 0, "Maximum number of concurrent requests to a single host, 0 for no limit")
+	a.RootCmd.PersistentFlags().DurationVar(&httpFlags.Delay, "delay", 0, "Delay between consecutive requests to a single host")
+	a.RootCmd.PersistentFlags().DurationVar(&httpFlags.Jitter, "jitter", 0, "Maximum random delay added to --delay between consecutive requests to a single host")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.AuthProfileFile, "auth-profile-file", "", "YAML or JSON file of authentication profiles")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.AuthProfile, "auth-profile", "", "Name of the authentication profile to send requests with, optional when the file has a single profile")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.ScopeFile, "scope-file", "", "YAML or JSON scope definition file; requests that are not in scope are blocked")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.HAROut, "har-out", "", "Path to a HAR file to record every HTTP request and response of the command to")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.Replay, "replay", "", "HAR file or directory of HAR fixtures to answer requests from instead of the network")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.StateDir, "state-dir", "", "Directory to checkpoint the progress of fuzz path, spider, vuln and webserver scans to")
+	a.RootCmd.PersistentFlags().BoolVar(&a.RootFlags.Resume, "resume", false, "Continue interrupted scans from their checkpoints in --state-dir")
+	a.RootCmd.PersistentFlags().StringVar(&a.RootFlags.Store, "store", "", "Results database to save the run and the results of the command to, and to query with 'results query'")
 
 	a.VersionCmd = &cobra.Command{
 		Use:   "version",
@@ -120,8 +267,130 @@ func validateOutputFormat(output string) (writer.Format, error) {
 		format = writer.YAML
 	case "signal":
 		format = writer.SIGNAL
+	case "sarif", "jsonl", "html":
+		// SARIF logs, JSON Lines streams and HTML pages are written by writeSARIF, closeStream and writeHTML, the format
+		// only configures the writer
+		format = writer.JSON
 	default:
-		return writer.Format{}, errors.New("invalid output format. Valid formats are: json, yaml, signal")
+		return writer.Format{}, errors.New("invalid output format. Valid formats are: json, yaml, signal, sarif, jsonl, html")
 	}
 	return writer.NewFormat(format), nil
 }
+
+// withErrors adds the errors to the Errors field of the report, skipping those the report already contains, e.g. as
+// part of the error of a failed request. Reports without an Errors field are returned unchanged.
+func withErrors(report interface{}, errs []string) interface{} {
+	if report == nil || len(errs) == 0 {
+		return report
+	}
+	value := reflect.ValueOf(report)
+	isPointer := value.Kind() == reflect.Ptr
+	if isPointer {
+		if value.IsNil() {
+			return report
+		}
+		value = value.Elem()
+	} else {
+		// Reports stored by value are copied so that their fields can be set
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(value)
+		value = copied
+	}
+	if value.Kind() != reflect.Struct {
+		return report
+	}
+	field := value.FieldByName("Errors")
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf([]string{}) {
+		return report
+	}
+
+	existing := field.Interface().([]string)
+	merged := append([]string{}, existing...)
+	for _, err := range errs {
+		reported := false
+		for _, e := range existing {
+			if strings.Contains(e, err) {
+				reported = true
+				break
+			}
+		}
+		if !reported {
+			merged = append(merged, err)
+		}
+	}
+	field.Set(reflect.ValueOf(merged))
+	if isPointer {
+		return report
+	}
+	return value.Interface()
+}
+
+// writeSARIF writes the findings of the command's report as a SARIF log, to the output file when one is provided and to
+// STDOUT otherwise.
+func (a *WebScan) writeSARIF(cmd *cobra.Command, outputFile string) error {
+	log, err := sarif.Convert(a.OutputSignal.Content, a.Version, a.OutputSignal.Status, a.OutputSignal.ErrorMessage)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode SARIF log: %v", err)
+	}
+	if outputFile != "" {
+		return os.WriteFile(outputFile, append(data, '\n'), 0644)
+	}
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return err
+}
+
+// closeStream writes the summary record that ends the JSON Lines stream of the command and closes the output file.
+func (a *WebScan) closeStream(emitter *stream.Emitter, streamFile *os.File) error {
+	if emitter == nil {
+		return nil
+	}
+	summary := stream.Summary{
+		Status:       a.OutputSignal.Status,
+		ErrorMessage: a.OutputSignal.ErrorMessage,
+		StartedAt:    time.Time(a.OutputSignal.StartedAt),
+	}
+	if a.OutputSignal.CompletedAt != nil {
+		summary.CompletedAt = time.Time(*a.OutputSignal.CompletedAt)
+	}
+	err := emitter.Close(summary, a.OutputSignal.Content)
+	if streamFile != nil {
+		if closeErr := streamFile.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// writeHTML writes the command's report as a self-contained HTML page, to the output file when one is provided and to
+// STDOUT otherwise.
+func (a *WebScan) writeHTML(cmd *cobra.Command, outputFile string) error {
+	metadata := htmlreport.Metadata{
+		Version:      a.Version,
+		StartedAt:    time.Time(a.OutputSignal.StartedAt),
+		Status:       a.OutputSignal.Status,
+		ErrorMessage: a.OutputSignal.ErrorMessage,
+	}
+	if _, ok := cmd.Annotations[outputFormatAnnotation]; !ok {
+		metadata.Command = cmd.CommandPath()
+	}
+	if a.OutputSignal.CompletedAt != nil {
+		metadata.CompletedAt = time.Time(*a.OutputSignal.CompletedAt)
+	}
+
+	if outputFile == "" {
+		return htmlreport.Render(cmd.OutOrStdout(), a.OutputSignal.Content, metadata)
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %v", err)
+	}
+	err = htmlreport.Render(file, a.OutputSignal.Content, metadata)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os/signal"
+	"syscall"
+
+	"github.com/Method-Security/webscan/internal/auth"
+	"github.com/Method-Security/webscan/internal/config"
+	"github.com/Method-Security/webscan/internal/scope"
+	"github.com/Method-Security/webscan/internal/server"
+	lib "github.com/Method-Security/webscan/pkg/webscan"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+	"github.com/spf13/cobra"
+)
+
+// InitServeCommand initializes the serve command for the webscan CLI. This command runs webscan as a long-running HTTP
+// API server, which runs the scans submitted to it as jobs of a persistent queue.
+func (a *WebScan) InitServeCommand() {
+	serveCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the webscan scanners as an HTTP API with a scan job queue",
+		Long: `Serve the webscan scanners as an HTTP API. Scans are submitted as jobs, which are queued in a persistent job
+database and run by a bounded pool of workers. The API submits, lists, follows and cancels jobs, and streams the
+results of running jobs as JSON Lines. Every job runs with the configuration of the global flags, such as the proxy,
+the authentication profile and the scope. Jobs interrupted by a shutdown are run again when the server restarts.`,
+		// The server runs until it is stopped and writes no report, so the root command's output handling is skipped
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			cmd.SetContext(svc1log.WithLogger(cmd.Context(), config.InitializeLogging(cmd, &a.RootFlags)))
+			return nil
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, _ []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			listen, err := cmd.Flags().GetString("listen")
+			if err != nil {
+				return err
+			}
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			workers, err := cmd.Flags().GetInt("workers")
+			if err != nil {
+				return err
+			}
+			maxQueued, err := cmd.Flags().GetInt("max-queued")
+			if err != nil {
+				return err
+			}
+			if workers < 1 || maxQueued < 1 {
+				return errors.New("--workers and --max-queued must be at least 1")
+			}
+
+			options, err := a.libraryOptions()
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer stop()
+			scanner, err := lib.New(ctx, options)
+			if err != nil {
+				return err
+			}
+			store, err := server.OpenStore(dbPath)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			listener, err := net.Listen("tcp", listen)
+			if err != nil {
+				return fmt.Errorf("failed to listen on %s: %v", listen, err)
+			}
+			return server.New(scanner, store, server.Options{Workers: workers, MaxQueued: maxQueued}).Run(ctx, listener)
+		},
+	}
+
+	serveCmd.Flags().String("listen", "127.0.0.1:8080", "Address to serve the API on")
+	serveCmd.Flags().String("db", "webscan-jobs.db", "Path to the job database, created when it does not exist")
+	serveCmd.Flags().Int("workers", 2, "Number of jobs run concurrently")
+	serveCmd.Flags().Int("max-queued", 100, "Maximum number of jobs waiting for a worker, beyond which submissions are rejected")
+
+	a.RootCmd.AddCommand(serveCmd)
+}
+
+// libraryOptions returns the configuration of the global flags as the options of a library Scanner.
+func (a *WebScan) libraryOptions() (lib.Options, error) {
+	options := lib.Options{
+		HTTP:   lib.HTTPOptions(a.RootFlags.HTTPClient),
+		Replay: a.RootFlags.Replay,
+	}
+	if a.RootFlags.HAROut != "" {
+		return options, errors.New("--har-out is not supported by serve, which runs many scans concurrently")
+	}
+	if a.RootFlags.AuthProfileFile != "" {
+		profile, err := auth.LoadProfile(a.RootFlags.AuthProfileFile, a.RootFlags.AuthProfile)
+		if err != nil {
+			return options, err
+		}
+		options.AuthProfile = profile
+	} else if a.RootFlags.AuthProfile != "" {
+		return options, fmt.Errorf("--auth-profile requires --auth-profile-file")
+	}
+	if a.RootFlags.ScopeFile != "" {
+		definition, err := scope.LoadDefinition(a.RootFlags.ScopeFile)
+		if err != nil {
+			return options, err
+		}
+		options.Scope = definition
+	}
+	return options, nil
+}
@@ -4,6 +4,7 @@ package client
 
 import (
 	core "github.com/Method-Security/webscan/generated/go/core"
+	jobs "github.com/Method-Security/webscan/generated/go/jobs"
 	option "github.com/Method-Security/webscan/generated/go/option"
 	http "net/http"
 )
@@ -12,6 +13,8 @@ type Client struct {
 	baseURL string
 	caller  *core.Caller
 	header  http.Header
+
+	Jobs *jobs.Client
 }
 
 func NewClient(opts ...option.RequestOption) *Client {
@@ -25,5 +28,6 @@ func NewClient(opts ...option.RequestOption) *Client {
 			},
 		),
 		header: options.ToHeader(),
+		Jobs:   jobs.NewClient(opts...),
 	}
 }
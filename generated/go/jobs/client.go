@@ -0,0 +1,181 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package jobs
+
+import (
+	context "context"
+	generatedgo "github.com/Method-Security/webscan/generated/go"
+	core "github.com/Method-Security/webscan/generated/go/core"
+	option "github.com/Method-Security/webscan/generated/go/option"
+	http "net/http"
+)
+
+type Client struct {
+	baseURL string
+	caller  *core.Caller
+	header  http.Header
+}
+
+func NewClient(opts ...option.RequestOption) *Client {
+	options := core.NewRequestOptions(opts...)
+	return &Client{
+		baseURL: options.BaseURL,
+		caller: core.NewCaller(
+			&core.CallerParams{
+				Client:      options.HTTPClient,
+				MaxAttempts: options.MaxAttempts,
+			},
+		),
+		header: options.ToHeader(),
+	}
+}
+
+// Queues a scan job
+func (c *Client) Submit(
+	ctx context.Context,
+	request *generatedgo.ScanJobRequest,
+	opts ...option.RequestOption,
+) (*generatedgo.ScanJob, error) {
+	options := core.NewRequestOptions(opts...)
+
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	if options.BaseURL != "" {
+		baseURL = options.BaseURL
+	}
+	endpointURL := baseURL + "/api/v1/jobs"
+
+	headers := core.MergeHeaders(c.header.Clone(), options.ToHeader())
+
+	var response *generatedgo.ScanJob
+	if err := c.caller.Call(
+		ctx,
+		&core.CallParams{
+			URL:         endpointURL,
+			Method:      http.MethodPost,
+			MaxAttempts: options.MaxAttempts,
+			Headers:     headers,
+			Client:      options.HTTPClient,
+			Request:     request,
+			Response:    &response,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Lists the scan jobs, oldest first
+func (c *Client) List(
+	ctx context.Context,
+	opts ...option.RequestOption,
+) ([]*generatedgo.ScanJob, error) {
+	options := core.NewRequestOptions(opts...)
+
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	if options.BaseURL != "" {
+		baseURL = options.BaseURL
+	}
+	endpointURL := baseURL + "/api/v1/jobs"
+
+	headers := core.MergeHeaders(c.header.Clone(), options.ToHeader())
+
+	var response []*generatedgo.ScanJob
+	if err := c.caller.Call(
+		ctx,
+		&core.CallParams{
+			URL:         endpointURL,
+			Method:      http.MethodGet,
+			MaxAttempts: options.MaxAttempts,
+			Headers:     headers,
+			Client:      options.HTTPClient,
+			Response:    &response,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Returns a scan job, with its result once it completed
+func (c *Client) Get(
+	ctx context.Context,
+	jobId string,
+	opts ...option.RequestOption,
+) (*generatedgo.ScanJob, error) {
+	options := core.NewRequestOptions(opts...)
+
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	if options.BaseURL != "" {
+		baseURL = options.BaseURL
+	}
+	endpointURL := core.EncodeURL(
+		baseURL+"/api/v1/jobs/%v",
+		jobId,
+	)
+
+	headers := core.MergeHeaders(c.header.Clone(), options.ToHeader())
+
+	var response *generatedgo.ScanJob
+	if err := c.caller.Call(
+		ctx,
+		&core.CallParams{
+			URL:         endpointURL,
+			Method:      http.MethodGet,
+			MaxAttempts: options.MaxAttempts,
+			Headers:     headers,
+			Client:      options.HTTPClient,
+			Response:    &response,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Cancels a queued or running scan job
+func (c *Client) Cancel(
+	ctx context.Context,
+	jobId string,
+	opts ...option.RequestOption,
+) (*generatedgo.ScanJob, error) {
+	options := core.NewRequestOptions(opts...)
+
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	if options.BaseURL != "" {
+		baseURL = options.BaseURL
+	}
+	endpointURL := core.EncodeURL(
+		baseURL+"/api/v1/jobs/%v/cancel",
+		jobId,
+	)
+
+	headers := core.MergeHeaders(c.header.Clone(), options.ToHeader())
+
+	var response *generatedgo.ScanJob
+	if err := c.caller.Call(
+		ctx,
+		&core.CallParams{
+			URL:         endpointURL,
+			Method:      http.MethodPost,
+			MaxAttempts: options.MaxAttempts,
+			Headers:     headers,
+			Client:      options.HTTPClient,
+			Response:    &response,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
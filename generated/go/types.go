@@ -9,6 +9,364 @@ import (
 	time "time"
 )
 
+type AuthProfile struct {
+	Name         string                `json:"name" url:"name"`
+	Type         AuthProfileType       `json:"type" url:"type"`
+	Token        *string               `json:"token,omitempty" url:"token,omitempty"`
+	Username     *string               `json:"username,omitempty" url:"username,omitempty"`
+	Password     *string               `json:"password,omitempty" url:"password,omitempty"`
+	Cookies      map[string]string     `json:"cookies,omitempty" url:"cookies,omitempty"`
+	CookieFile   *string               `json:"cookieFile,omitempty" url:"cookieFile,omitempty"`
+	Headers      map[string]string     `json:"headers,omitempty" url:"headers,omitempty"`
+	GrantType    *AuthProfileGrantType `json:"grantType,omitempty" url:"grantType,omitempty"`
+	TokenUrl     *string               `json:"tokenUrl,omitempty" url:"tokenUrl,omitempty"`
+	ClientId     *string               `json:"clientId,omitempty" url:"clientId,omitempty"`
+	ClientSecret *string               `json:"clientSecret,omitempty" url:"clientSecret,omitempty"`
+	Scopes       []string              `json:"scopes,omitempty" url:"scopes,omitempty"`
+	Audience     *string               `json:"audience,omitempty" url:"audience,omitempty"`
+	RefreshToken *string               `json:"refreshToken,omitempty" url:"refreshToken,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthProfile) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthProfile) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthProfile
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthProfile(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthProfile) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthProfileGrantType string
+
+const (
+	AuthProfileGrantTypeClientCredentials AuthProfileGrantType = "CLIENT_CREDENTIALS"
+	AuthProfileGrantTypeRefreshToken      AuthProfileGrantType = "REFRESH_TOKEN"
+)
+
+func NewAuthProfileGrantTypeFromString(s string) (AuthProfileGrantType, error) {
+	switch s {
+	case "CLIENT_CREDENTIALS":
+		return AuthProfileGrantTypeClientCredentials, nil
+	case "REFRESH_TOKEN":
+		return AuthProfileGrantTypeRefreshToken, nil
+	}
+	var t AuthProfileGrantType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (a AuthProfileGrantType) Ptr() *AuthProfileGrantType {
+	return &a
+}
+
+type AuthProfileType string
+
+const (
+	AuthProfileTypeBearer AuthProfileType = "BEARER"
+	AuthProfileTypeBasic  AuthProfileType = "BASIC"
+	AuthProfileTypeCookie AuthProfileType = "COOKIE"
+	AuthProfileTypeHeader AuthProfileType = "HEADER"
+	AuthProfileTypeOauth2 AuthProfileType = "OAUTH2"
+)
+
+func NewAuthProfileTypeFromString(s string) (AuthProfileType, error) {
+	switch s {
+	case "BEARER":
+		return AuthProfileTypeBearer, nil
+	case "BASIC":
+		return AuthProfileTypeBasic, nil
+	case "COOKIE":
+		return AuthProfileTypeCookie, nil
+	case "HEADER":
+		return AuthProfileTypeHeader, nil
+	case "OAUTH2":
+		return AuthProfileTypeOauth2, nil
+	}
+	var t AuthProfileType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (a AuthProfileType) Ptr() *AuthProfileType {
+	return &a
+}
+
+type AuthProfiles struct {
+	Profiles []*AuthProfile `json:"profiles" url:"profiles"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthProfiles) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthProfiles) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthProfiles
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthProfiles(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthProfiles) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthzFinding struct {
+	Route         string         `json:"route" url:"route"`
+	Technique     AuthzTechnique `json:"technique" url:"technique"`
+	Owner         string         `json:"owner" url:"owner"`
+	Attacker      string         `json:"attacker" url:"attacker"`
+	Location      *ParamLocation `json:"location,omitempty" url:"location,omitempty"`
+	Parameter     *string        `json:"parameter,omitempty" url:"parameter,omitempty"`
+	OriginalValue *string        `json:"originalValue,omitempty" url:"originalValue,omitempty"`
+	TestedValue   *string        `json:"testedValue,omitempty" url:"testedValue,omitempty"`
+	Evidence      string         `json:"evidence" url:"evidence"`
+	Confidence    Confidence     `json:"confidence" url:"confidence"`
+	StatusCode    int            `json:"statusCode" url:"statusCode"`
+	ResponseHash  *string        `json:"responseHash,omitempty" url:"responseHash,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthzFinding) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthzFinding) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthzFinding
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthzFinding(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthzFinding) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthzIdentities struct {
+	Identities []*AuthzIdentity `json:"identities" url:"identities"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthzIdentities) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthzIdentities) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthzIdentities
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthzIdentities(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthzIdentities) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthzIdentity struct {
+	Name      string            `json:"name" url:"name"`
+	Headers   map[string]string `json:"headers,omitempty" url:"headers,omitempty"`
+	Cookies   map[string]string `json:"cookies,omitempty" url:"cookies,omitempty"`
+	ObjectIds map[string]string `json:"objectIds,omitempty" url:"objectIds,omitempty"`
+	Markers   []string          `json:"markers,omitempty" url:"markers,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthzIdentity) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthzIdentity) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthzIdentity
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthzIdentity(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthzIdentity) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthzReport struct {
+	Target       string          `json:"target" url:"target"`
+	BaseUrl      string          `json:"baseUrl" url:"baseUrl"`
+	Identities   []string        `json:"identities,omitempty" url:"identities,omitempty"`
+	RoutesTested int             `json:"routesTested" url:"routesTested"`
+	Findings     []*AuthzFinding `json:"findings,omitempty" url:"findings,omitempty"`
+	Errors       []string        `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (a *AuthzReport) GetExtraProperties() map[string]interface{} {
+	return a.extraProperties
+}
+
+func (a *AuthzReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler AuthzReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = AuthzReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *a)
+	if err != nil {
+		return err
+	}
+	a.extraProperties = extraProperties
+
+	a._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (a *AuthzReport) String() string {
+	if len(a._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(a._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(a); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", a)
+}
+
+type AuthzTechnique string
+
+const (
+	AuthzTechniqueCrossIdentity AuthzTechnique = "CROSS_IDENTITY"
+	AuthzTechniqueIdSwap        AuthzTechnique = "ID_SWAP"
+	AuthzTechniqueIdIncrement   AuthzTechnique = "ID_INCREMENT"
+)
+
+func NewAuthzTechniqueFromString(s string) (AuthzTechnique, error) {
+	switch s {
+	case "CROSS_IDENTITY":
+		return AuthzTechniqueCrossIdentity, nil
+	case "ID_SWAP":
+		return AuthzTechniqueIdSwap, nil
+	case "ID_INCREMENT":
+		return AuthzTechniqueIdIncrement, nil
+	}
+	var t AuthzTechnique
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (a AuthzTechnique) Ptr() *AuthzTechnique {
+	return &a
+}
+
 type HttpMethod string
 
 const (
@@ -84,813 +442,2031 @@ func NewTlsVersionFromString(s string) (TlsVersion, error) {
 	case "UNKNOWN":
 		return TlsVersionUnknown, nil
 	}
-	var t TlsVersion
-	return "", fmt.Errorf("%s is not a valid %T", s, t)
+	var t TlsVersion
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (t TlsVersion) Ptr() *TlsVersion {
+	return &t
+}
+
+type DiffChange struct {
+	Change DiffChangeType `json:"change" url:"change"`
+	Item   DiffItemType   `json:"item" url:"item"`
+	Target *string        `json:"target,omitempty" url:"target,omitempty"`
+	Key    string         `json:"key" url:"key"`
+	Fields []string       `json:"fields,omitempty" url:"fields,omitempty"`
+	Before interface{}    `json:"before,omitempty" url:"before,omitempty"`
+	After  interface{}    `json:"after,omitempty" url:"after,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DiffChange) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DiffChange) UnmarshalJSON(data []byte) error {
+	type unmarshaler DiffChange
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DiffChange(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DiffChange) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DiffChangeType string
+
+const (
+	DiffChangeTypeNew      DiffChangeType = "NEW"
+	DiffChangeTypeRemoved  DiffChangeType = "REMOVED"
+	DiffChangeTypeChanged  DiffChangeType = "CHANGED"
+	DiffChangeTypeExpiring DiffChangeType = "EXPIRING"
+)
+
+func NewDiffChangeTypeFromString(s string) (DiffChangeType, error) {
+	switch s {
+	case "NEW":
+		return DiffChangeTypeNew, nil
+	case "REMOVED":
+		return DiffChangeTypeRemoved, nil
+	case "CHANGED":
+		return DiffChangeTypeChanged, nil
+	case "EXPIRING":
+		return DiffChangeTypeExpiring, nil
+	}
+	var t DiffChangeType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DiffChangeType) Ptr() *DiffChangeType {
+	return &d
+}
+
+type DiffItemType string
+
+const (
+	DiffItemTypeRoute       DiffItemType = "ROUTE"
+	DiffItemTypeUrl         DiffItemType = "URL"
+	DiffItemTypeAttempt     DiffItemType = "ATTEMPT"
+	DiffItemTypePath        DiffItemType = "PATH"
+	DiffItemTypeFinding     DiffItemType = "FINDING"
+	DiffItemTypeCertificate DiffItemType = "CERTIFICATE"
+	DiffItemTypeTls         DiffItemType = "TLS"
+	DiffItemTypeHeader      DiffItemType = "HEADER"
+	DiffItemTypeRedirect    DiffItemType = "REDIRECT"
+)
+
+func NewDiffItemTypeFromString(s string) (DiffItemType, error) {
+	switch s {
+	case "ROUTE":
+		return DiffItemTypeRoute, nil
+	case "URL":
+		return DiffItemTypeUrl, nil
+	case "ATTEMPT":
+		return DiffItemTypeAttempt, nil
+	case "PATH":
+		return DiffItemTypePath, nil
+	case "FINDING":
+		return DiffItemTypeFinding, nil
+	case "CERTIFICATE":
+		return DiffItemTypeCertificate, nil
+	case "TLS":
+		return DiffItemTypeTls, nil
+	case "HEADER":
+		return DiffItemTypeHeader, nil
+	case "REDIRECT":
+		return DiffItemTypeRedirect, nil
+	}
+	var t DiffItemType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DiffItemType) Ptr() *DiffItemType {
+	return &d
+}
+
+type DiffReport struct {
+	Type     DiffReportType `json:"type" url:"type"`
+	Baseline string         `json:"baseline" url:"baseline"`
+	Current  string         `json:"current" url:"current"`
+	New      int            `json:"new" url:"new"`
+	Removed  int            `json:"removed" url:"removed"`
+	Changed  int            `json:"changed" url:"changed"`
+	Expiring int            `json:"expiring" url:"expiring"`
+	Changes  []*DiffChange  `json:"changes,omitempty" url:"changes,omitempty"`
+	Errors   []string       `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (d *DiffReport) GetExtraProperties() map[string]interface{} {
+	return d.extraProperties
+}
+
+func (d *DiffReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler DiffReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*d = DiffReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *d)
+	if err != nil {
+		return err
+	}
+	d.extraProperties = extraProperties
+
+	d._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (d *DiffReport) String() string {
+	if len(d._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(d._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(d); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", d)
+}
+
+type DiffReportType string
+
+const (
+	DiffReportTypeRoutes       DiffReportType = "ROUTES"
+	DiffReportTypeRouteCapture DiffReportType = "ROUTE_CAPTURE"
+	DiffReportTypeWebServer    DiffReportType = "WEB_SERVER"
+	DiffReportTypeFuzzPath     DiffReportType = "FUZZ_PATH"
+	DiffReportTypeVuln         DiffReportType = "VULN"
+	DiffReportTypeFingerprint  DiffReportType = "FINGERPRINT"
+)
+
+func NewDiffReportTypeFromString(s string) (DiffReportType, error) {
+	switch s {
+	case "ROUTES":
+		return DiffReportTypeRoutes, nil
+	case "ROUTE_CAPTURE":
+		return DiffReportTypeRouteCapture, nil
+	case "WEB_SERVER":
+		return DiffReportTypeWebServer, nil
+	case "FUZZ_PATH":
+		return DiffReportTypeFuzzPath, nil
+	case "VULN":
+		return DiffReportTypeVuln, nil
+	case "FINGERPRINT":
+		return DiffReportTypeFingerprint, nil
+	}
+	var t DiffReportType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (d DiffReportType) Ptr() *DiffReportType {
+	return &d
+}
+
+type Certificate struct {
+	SubjectCommonName  *string             `json:"subjectCommonName,omitempty" url:"subjectCommonName,omitempty"`
+	IssuerCommonName   *string             `json:"issuerCommonName,omitempty" url:"issuerCommonName,omitempty"`
+	ValidFrom          *time.Time          `json:"validFrom,omitempty" url:"validFrom,omitempty"`
+	ValidTo            *time.Time          `json:"validTo,omitempty" url:"validTo,omitempty"`
+	Version            *int                `json:"version,omitempty" url:"version,omitempty"`
+	SerialNumber       *string             `json:"serialNumber,omitempty" url:"serialNumber,omitempty"`
+	Certificate        *string             `json:"certificate,omitempty" url:"certificate,omitempty"`
+	Signature          *string             `json:"signature,omitempty" url:"signature,omitempty"`
+	SignatureAlgorithm *SignatureAlgorithm `json:"signatureAlgorithm,omitempty" url:"signatureAlgorithm,omitempty"`
+	PublicKeyAlgorithm *PublicKeyAlgorithm `json:"publicKeyAlgorithm,omitempty" url:"publicKeyAlgorithm,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (c *Certificate) GetExtraProperties() map[string]interface{} {
+	return c.extraProperties
+}
+
+func (c *Certificate) UnmarshalJSON(data []byte) error {
+	type embed Certificate
+	var unmarshaler = struct {
+		embed
+		ValidFrom *core.DateTime `json:"validFrom,omitempty"`
+		ValidTo   *core.DateTime `json:"validTo,omitempty"`
+	}{
+		embed: embed(*c),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*c = Certificate(unmarshaler.embed)
+	c.ValidFrom = unmarshaler.ValidFrom.TimePtr()
+	c.ValidTo = unmarshaler.ValidTo.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *c)
+	if err != nil {
+		return err
+	}
+	c.extraProperties = extraProperties
+
+	c._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (c *Certificate) MarshalJSON() ([]byte, error) {
+	type embed Certificate
+	var marshaler = struct {
+		embed
+		ValidFrom *core.DateTime `json:"validFrom,omitempty"`
+		ValidTo   *core.DateTime `json:"validTo,omitempty"`
+	}{
+		embed:     embed(*c),
+		ValidFrom: core.NewOptionalDateTime(c.ValidFrom),
+		ValidTo:   core.NewOptionalDateTime(c.ValidTo),
+	}
+	return json.Marshal(marshaler)
+}
+
+func (c *Certificate) String() string {
+	if len(c._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(c._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(c); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", c)
+}
+
+type FingerprintReport struct {
+	Target              string       `json:"target" url:"target"`
+	HttpHeaders         *HttpHeaders `json:"httpHeaders,omitempty" url:"httpHeaders,omitempty"`
+	TlsInfo             *TlsInfo     `json:"tlsInfo,omitempty" url:"tlsInfo,omitempty"`
+	RedirectUrl         *string      `json:"redirectUrl,omitempty" url:"redirectUrl,omitempty"`
+	RedirectHttpHeaders *HttpHeaders `json:"redirectHttpHeaders,omitempty" url:"redirectHttpHeaders,omitempty"`
+	RedirectTlsInfo     *TlsInfo     `json:"redirectTlsInfo,omitempty" url:"redirectTlsInfo,omitempty"`
+	Errors              []string     `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (f *FingerprintReport) GetExtraProperties() map[string]interface{} {
+	return f.extraProperties
+}
+
+func (f *FingerprintReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler FingerprintReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*f = FingerprintReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	if err != nil {
+		return err
+	}
+	f.extraProperties = extraProperties
+
+	f._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (f *FingerprintReport) String() string {
+	if len(f._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(f); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", f)
+}
+
+type HttpHeaders struct {
+	Location                  *string `json:"location,omitempty" url:"location,omitempty"`
+	Server                    *string `json:"server,omitempty" url:"server,omitempty"`
+	XPoweredBy                *string `json:"xPoweredBy,omitempty" url:"xPoweredBy,omitempty"`
+	XFrameOptions             *string `json:"xFrameOptions,omitempty" url:"xFrameOptions,omitempty"`
+	XClusterName              *string `json:"xClusterName,omitempty" url:"xClusterName,omitempty"`
+	CrossOriginResourcePolicy *string `json:"crossOriginResourcePolicy,omitempty" url:"crossOriginResourcePolicy,omitempty"`
+	AccessControlAllowOrigin  *string `json:"accessControlAllowOrigin,omitempty" url:"accessControlAllowOrigin,omitempty"`
+	XAspNetVersion            *string `json:"xAspNetVersion,omitempty" url:"xAspNetVersion,omitempty"`
+	AllowedHttpMethods        *string `json:"allowedHttpMethods,omitempty" url:"allowedHttpMethods,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (h *HttpHeaders) GetExtraProperties() map[string]interface{} {
+	return h.extraProperties
+}
+
+func (h *HttpHeaders) UnmarshalJSON(data []byte) error {
+	type unmarshaler HttpHeaders
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*h = HttpHeaders(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *h)
+	if err != nil {
+		return err
+	}
+	h.extraProperties = extraProperties
+
+	h._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (h *HttpHeaders) String() string {
+	if len(h._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(h._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(h); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", h)
+}
+
+type PublicKeyAlgorithm string
+
+const (
+	PublicKeyAlgorithmRsa     PublicKeyAlgorithm = "RSA"
+	PublicKeyAlgorithmDsa     PublicKeyAlgorithm = "DSA"
+	PublicKeyAlgorithmEcdsa   PublicKeyAlgorithm = "ECDSA"
+	PublicKeyAlgorithmEd25519 PublicKeyAlgorithm = "Ed25519"
+	PublicKeyAlgorithmUnknown PublicKeyAlgorithm = "Unknown"
+)
+
+func NewPublicKeyAlgorithmFromString(s string) (PublicKeyAlgorithm, error) {
+	switch s {
+	case "RSA":
+		return PublicKeyAlgorithmRsa, nil
+	case "DSA":
+		return PublicKeyAlgorithmDsa, nil
+	case "ECDSA":
+		return PublicKeyAlgorithmEcdsa, nil
+	case "Ed25519":
+		return PublicKeyAlgorithmEd25519, nil
+	case "Unknown":
+		return PublicKeyAlgorithmUnknown, nil
+	}
+	var t PublicKeyAlgorithm
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (p PublicKeyAlgorithm) Ptr() *PublicKeyAlgorithm {
+	return &p
+}
+
+type SignatureAlgorithm string
+
+const (
+	SignatureAlgorithmMd2Rsa       SignatureAlgorithm = "MD2RSA"
+	SignatureAlgorithmMd5Rsa       SignatureAlgorithm = "MD5RSA"
+	SignatureAlgorithmSha1Rsa      SignatureAlgorithm = "SHA1RSA"
+	SignatureAlgorithmSha256Rsa    SignatureAlgorithm = "SHA256RSA"
+	SignatureAlgorithmSha384Rsa    SignatureAlgorithm = "SHA384RSA"
+	SignatureAlgorithmSha512Rsa    SignatureAlgorithm = "SHA512RSA"
+	SignatureAlgorithmDsasha1      SignatureAlgorithm = "DSASHA1"
+	SignatureAlgorithmDsasha256    SignatureAlgorithm = "DSASHA256"
+	SignatureAlgorithmEcdsasha1    SignatureAlgorithm = "ECDSASHA1"
+	SignatureAlgorithmEcdsasha256  SignatureAlgorithm = "ECDSASHA256"
+	SignatureAlgorithmEcdsasha384  SignatureAlgorithm = "ECDSASHA384"
+	SignatureAlgorithmEcdsasha512  SignatureAlgorithm = "ECDSASHA512"
+	SignatureAlgorithmSha256Rsapss SignatureAlgorithm = "SHA256RSAPSS"
+	SignatureAlgorithmSha384Rsapss SignatureAlgorithm = "SHA384RSAPSS"
+	SignatureAlgorithmSha512Rsapss SignatureAlgorithm = "SHA512RSAPSS"
+	SignatureAlgorithmEd25519      SignatureAlgorithm = "Ed25519"
+)
+
+func NewSignatureAlgorithmFromString(s string) (SignatureAlgorithm, error) {
+	switch s {
+	case "MD2RSA":
+		return SignatureAlgorithmMd2Rsa, nil
+	case "MD5RSA":
+		return SignatureAlgorithmMd5Rsa, nil
+	case "SHA1RSA":
+		return SignatureAlgorithmSha1Rsa, nil
+	case "SHA256RSA":
+		return SignatureAlgorithmSha256Rsa, nil
+	case "SHA384RSA":
+		return SignatureAlgorithmSha384Rsa, nil
+	case "SHA512RSA":
+		return SignatureAlgorithmSha512Rsa, nil
+	case "DSASHA1":
+		return SignatureAlgorithmDsasha1, nil
+	case "DSASHA256":
+		return SignatureAlgorithmDsasha256, nil
+	case "ECDSASHA1":
+		return SignatureAlgorithmEcdsasha1, nil
+	case "ECDSASHA256":
+		return SignatureAlgorithmEcdsasha256, nil
+	case "ECDSASHA384":
+		return SignatureAlgorithmEcdsasha384, nil
+	case "ECDSASHA512":
+		return SignatureAlgorithmEcdsasha512, nil
+	case "SHA256RSAPSS":
+		return SignatureAlgorithmSha256Rsapss, nil
+	case "SHA384RSAPSS":
+		return SignatureAlgorithmSha384Rsapss, nil
+	case "SHA512RSAPSS":
+		return SignatureAlgorithmSha512Rsapss, nil
+	case "Ed25519":
+		return SignatureAlgorithmEd25519, nil
+	}
+	var t SignatureAlgorithm
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s SignatureAlgorithm) Ptr() *SignatureAlgorithm {
+	return &s
+}
+
+type TlsInfo struct {
+	Version      *TlsVersion    `json:"version,omitempty" url:"version,omitempty"`
+	CipherSuite  *string        `json:"cipherSuite,omitempty" url:"cipherSuite,omitempty"`
+	Certificates []*Certificate `json:"certificates,omitempty" url:"certificates,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (t *TlsInfo) GetExtraProperties() map[string]interface{} {
+	return t.extraProperties
+}
+
+func (t *TlsInfo) UnmarshalJSON(data []byte) error {
+	type unmarshaler TlsInfo
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*t = TlsInfo(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *t)
+	if err != nil {
+		return err
+	}
+	t.extraProperties = extraProperties
+
+	t._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (t *TlsInfo) String() string {
+	if len(t._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(t._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(t); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", t)
+}
+
+type FuzzPathReport struct {
+	Target                   string        `json:"target" url:"target"`
+	Urls                     []*UrlDetails `json:"urls,omitempty" url:"urls,omitempty"`
+	UrlsSkippedFromBaseMatch []*UrlDetails `json:"urlsSkippedFromBaseMatch,omitempty" url:"urlsSkippedFromBaseMatch,omitempty"`
+	Errors                   []string      `json:"errors,omitempty" url:"errors,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (f *FuzzPathReport) GetExtraProperties() map[string]interface{} {
+	return f.extraProperties
+}
+
+func (f *FuzzPathReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler FuzzPathReport
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*f = FuzzPathReport(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	if err != nil {
+		return err
+	}
+	f.extraProperties = extraProperties
+
+	f._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (f *FuzzPathReport) String() string {
+	if len(f._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(f); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", f)
+}
+
+type UrlDetails struct {
+	Url    string `json:"url" url:"url"`
+	Status string `json:"status" url:"status"`
+	Size   int    `json:"size" url:"size"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (u *UrlDetails) GetExtraProperties() map[string]interface{} {
+	return u.extraProperties
+}
+
+func (u *UrlDetails) UnmarshalJSON(data []byte) error {
+	type unmarshaler UrlDetails
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*u = UrlDetails(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *u)
+	if err != nil {
+		return err
+	}
+	u.extraProperties = extraProperties
+
+	u._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (u *UrlDetails) String() string {
+	if len(u._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(u._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(u); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", u)
+}
+
+type GraphQlData struct {
+	Schema *GraphQlSchemaData `json:"__schema,omitempty" url:"__schema,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlData) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlData) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlData) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type GraphQlField struct {
+	Name string `json:"name" url:"name"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlField) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlField) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlField
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlField(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlField) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type GraphQlQuery struct {
+	Type   string   `json:"type" url:"type"`
+	Fields []string `json:"fields,omitempty" url:"fields,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlQuery) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlQuery) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlQuery
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlQuery(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlQuery) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type GraphQlSchema struct {
+	Data *GraphQlData `json:"data,omitempty" url:"data,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlSchema) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlSchema) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlSchema
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlSchema(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlSchema) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type GraphQlSchemaData struct {
+	Types []*GraphQlType `json:"types,omitempty" url:"types,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlSchemaData) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlSchemaData) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlSchemaData
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlSchemaData(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlSchemaData) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type GraphQlType struct {
+	Name        string          `json:"name" url:"name"`
+	Kind        string          `json:"kind" url:"kind"`
+	Description *string         `json:"description,omitempty" url:"description,omitempty"`
+	Fields      []*GraphQlField `json:"fields,omitempty" url:"fields,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (g *GraphQlType) GetExtraProperties() map[string]interface{} {
+	return g.extraProperties
+}
+
+func (g *GraphQlType) UnmarshalJSON(data []byte) error {
+	type unmarshaler GraphQlType
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*g = GraphQlType(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	if err != nil {
+		return err
+	}
+	g.extraProperties = extraProperties
+
+	g._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (g *GraphQlType) String() string {
+	if len(g._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(g); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", g)
+}
+
+type ScanJob struct {
+	Id          string          `json:"id" url:"id"`
+	Request     *ScanJobRequest `json:"request" url:"request"`
+	Status      ScanJobStatus   `json:"status" url:"status"`
+	CreatedAt   time.Time       `json:"createdAt" url:"createdAt"`
+	StartedAt   *time.Time      `json:"startedAt,omitempty" url:"startedAt,omitempty"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty" url:"completedAt,omitempty"`
+	Errors      []string        `json:"errors,omitempty" url:"errors,omitempty"`
+	Result      *ScanJobResult  `json:"result,omitempty" url:"result,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *ScanJob) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *ScanJob) UnmarshalJSON(data []byte) error {
+	type embed ScanJob
+	var unmarshaler = struct {
+		embed
+		CreatedAt   *core.DateTime `json:"createdAt"`
+		StartedAt   *core.DateTime `json:"startedAt,omitempty"`
+		CompletedAt *core.DateTime `json:"completedAt,omitempty"`
+	}{
+		embed: embed(*s),
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	*s = ScanJob(unmarshaler.embed)
+	s.CreatedAt = unmarshaler.CreatedAt.Time()
+	s.StartedAt = unmarshaler.StartedAt.TimePtr()
+	s.CompletedAt = unmarshaler.CompletedAt.TimePtr()
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *ScanJob) MarshalJSON() ([]byte, error) {
+	type embed ScanJob
+	var marshaler = struct {
+		embed
+		CreatedAt   *core.DateTime `json:"createdAt"`
+		StartedAt   *core.DateTime `json:"startedAt,omitempty"`
+		CompletedAt *core.DateTime `json:"completedAt,omitempty"`
+	}{
+		embed:       embed(*s),
+		CreatedAt:   core.NewDateTime(s.CreatedAt),
+		StartedAt:   core.NewOptionalDateTime(s.StartedAt),
+		CompletedAt: core.NewOptionalDateTime(s.CompletedAt),
+	}
+	return json.Marshal(marshaler)
 }
 
-func (t TlsVersion) Ptr() *TlsVersion {
-	return &t
+func (s *ScanJob) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
 }
 
-type Certificate struct {
-	SubjectCommonName  *string             `json:"subjectCommonName,omitempty" url:"subjectCommonName,omitempty"`
-	IssuerCommonName   *string             `json:"issuerCommonName,omitempty" url:"issuerCommonName,omitempty"`
-	ValidFrom          *time.Time          `json:"validFrom,omitempty" url:"validFrom,omitempty"`
-	ValidTo            *time.Time          `json:"validTo,omitempty" url:"validTo,omitempty"`
-	Version            *int                `json:"version,omitempty" url:"version,omitempty"`
-	SerialNumber       *string             `json:"serialNumber,omitempty" url:"serialNumber,omitempty"`
-	Certificate        *string             `json:"certificate,omitempty" url:"certificate,omitempty"`
-	Signature          *string             `json:"signature,omitempty" url:"signature,omitempty"`
-	SignatureAlgorithm *SignatureAlgorithm `json:"signatureAlgorithm,omitempty" url:"signatureAlgorithm,omitempty"`
-	PublicKeyAlgorithm *PublicKeyAlgorithm `json:"publicKeyAlgorithm,omitempty" url:"publicKeyAlgorithm,omitempty"`
+type ScanJobEvent struct {
+	Type      string      `json:"type" url:"type"`
+	Timestamp time.Time   `json:"timestamp" url:"timestamp"`
+	Data      interface{} `json:"data,omitempty" url:"data,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (c *Certificate) GetExtraProperties() map[string]interface{} {
-	return c.extraProperties
+func (s *ScanJobEvent) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
 }
 
-func (c *Certificate) UnmarshalJSON(data []byte) error {
-	type embed Certificate
+func (s *ScanJobEvent) UnmarshalJSON(data []byte) error {
+	type embed ScanJobEvent
 	var unmarshaler = struct {
 		embed
-		ValidFrom *core.DateTime `json:"validFrom,omitempty"`
-		ValidTo   *core.DateTime `json:"validTo,omitempty"`
+		Timestamp *core.DateTime `json:"timestamp"`
 	}{
-		embed: embed(*c),
+		embed: embed(*s),
 	}
 	if err := json.Unmarshal(data, &unmarshaler); err != nil {
 		return err
 	}
-	*c = Certificate(unmarshaler.embed)
-	c.ValidFrom = unmarshaler.ValidFrom.TimePtr()
-	c.ValidTo = unmarshaler.ValidTo.TimePtr()
+	*s = ScanJobEvent(unmarshaler.embed)
+	s.Timestamp = unmarshaler.Timestamp.Time()
 
-	extraProperties, err := core.ExtractExtraProperties(data, *c)
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
 	if err != nil {
 		return err
 	}
-	c.extraProperties = extraProperties
+	s.extraProperties = extraProperties
 
-	c._rawJSON = json.RawMessage(data)
+	s._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (c *Certificate) MarshalJSON() ([]byte, error) {
-	type embed Certificate
+func (s *ScanJobEvent) MarshalJSON() ([]byte, error) {
+	type embed ScanJobEvent
 	var marshaler = struct {
 		embed
-		ValidFrom *core.DateTime `json:"validFrom,omitempty"`
-		ValidTo   *core.DateTime `json:"validTo,omitempty"`
+		Timestamp *core.DateTime `json:"timestamp"`
 	}{
-		embed:     embed(*c),
-		ValidFrom: core.NewOptionalDateTime(c.ValidFrom),
-		ValidTo:   core.NewOptionalDateTime(c.ValidTo),
+		embed:     embed(*s),
+		Timestamp: core.NewDateTime(s.Timestamp),
 	}
 	return json.Marshal(marshaler)
 }
 
-func (c *Certificate) String() string {
-	if len(c._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(c._rawJSON); err == nil {
+func (s *ScanJobEvent) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(c); err == nil {
+	if value, err := core.StringifyJSON(s); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", c)
+	return fmt.Sprintf("%#v", s)
 }
 
-type FingerprintReport struct {
-	Target              string       `json:"target" url:"target"`
-	HttpHeaders         *HttpHeaders `json:"httpHeaders,omitempty" url:"httpHeaders,omitempty"`
-	TlsInfo             *TlsInfo     `json:"tlsInfo,omitempty" url:"tlsInfo,omitempty"`
-	RedirectUrl         *string      `json:"redirectUrl,omitempty" url:"redirectUrl,omitempty"`
-	RedirectHttpHeaders *HttpHeaders `json:"redirectHttpHeaders,omitempty" url:"redirectHttpHeaders,omitempty"`
-	RedirectTlsInfo     *TlsInfo     `json:"redirectTlsInfo,omitempty" url:"redirectTlsInfo,omitempty"`
-	Errors              []string     `json:"errors,omitempty" url:"errors,omitempty"`
+type ScanJobRequest struct {
+	Type           ScanJobType        `json:"type" url:"type"`
+	Targets        []string           `json:"targets" url:"targets"`
+	Server         *ServerType        `json:"server,omitempty" url:"server,omitempty"`
+	Probe          *ProbeType         `json:"probe,omitempty" url:"probe,omitempty"`
+	Modules        []ModuleName       `json:"modules,omitempty" url:"modules,omitempty"`
+	SuccessfulOnly *bool              `json:"successfulOnly,omitempty" url:"successfulOnly,omitempty"`
+	Paths          []string           `json:"paths,omitempty" url:"paths,omitempty"`
+	ResponseCodes  *string            `json:"responseCodes,omitempty" url:"responseCodes,omitempty"`
+	CaptureMethod  *PageCaptureMethod `json:"captureMethod,omitempty" url:"captureMethod,omitempty"`
+	Requests       []*RequestParams   `json:"requests,omitempty" url:"requests,omitempty"`
+	VulnTypes      []string           `json:"vulnTypes,omitempty" url:"vulnTypes,omitempty"`
+	TimeoutSeconds *int               `json:"timeoutSeconds,omitempty" url:"timeoutSeconds,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (f *FingerprintReport) GetExtraProperties() map[string]interface{} {
-	return f.extraProperties
+func (s *ScanJobRequest) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
 }
 
-func (f *FingerprintReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler FingerprintReport
+func (s *ScanJobRequest) UnmarshalJSON(data []byte) error {
+	type unmarshaler ScanJobRequest
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*f = FingerprintReport(value)
+	*s = ScanJobRequest(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
 	if err != nil {
 		return err
 	}
-	f.extraProperties = extraProperties
+	s.extraProperties = extraProperties
 
-	f._rawJSON = json.RawMessage(data)
+	s._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (f *FingerprintReport) String() string {
-	if len(f._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+func (s *ScanJobRequest) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(f); err == nil {
+	if value, err := core.StringifyJSON(s); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", f)
+	return fmt.Sprintf("%#v", s)
 }
 
-type HttpHeaders struct {
-	Location                  *string `json:"location,omitempty" url:"location,omitempty"`
-	Server                    *string `json:"server,omitempty" url:"server,omitempty"`
-	XPoweredBy                *string `json:"xPoweredBy,omitempty" url:"xPoweredBy,omitempty"`
-	XFrameOptions             *string `json:"xFrameOptions,omitempty" url:"xFrameOptions,omitempty"`
-	XClusterName              *string `json:"xClusterName,omitempty" url:"xClusterName,omitempty"`
-	CrossOriginResourcePolicy *string `json:"crossOriginResourcePolicy,omitempty" url:"crossOriginResourcePolicy,omitempty"`
-	AccessControlAllowOrigin  *string `json:"accessControlAllowOrigin,omitempty" url:"accessControlAllowOrigin,omitempty"`
-	XAspNetVersion            *string `json:"xAspNetVersion,omitempty" url:"xAspNetVersion,omitempty"`
-	AllowedHttpMethods        *string `json:"allowedHttpMethods,omitempty" url:"allowedHttpMethods,omitempty"`
+type ScanJobResult struct {
+	RoutesReport       *RoutesReport       `json:"routesReport,omitempty" url:"routesReport,omitempty"`
+	FingerprintReport  *FingerprintReport  `json:"fingerprintReport,omitempty" url:"fingerprintReport,omitempty"`
+	WebServerReport    *WebServerReport    `json:"webServerReport,omitempty" url:"webServerReport,omitempty"`
+	FuzzPathReport     *FuzzPathReport     `json:"fuzzPathReport,omitempty" url:"fuzzPathReport,omitempty"`
+	RouteCaptureReport *RouteCaptureReport `json:"routeCaptureReport,omitempty" url:"routeCaptureReport,omitempty"`
+	RequestBatchReport *RequestBatchReport `json:"requestBatchReport,omitempty" url:"requestBatchReport,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (h *HttpHeaders) GetExtraProperties() map[string]interface{} {
-	return h.extraProperties
+func (s *ScanJobResult) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
 }
 
-func (h *HttpHeaders) UnmarshalJSON(data []byte) error {
-	type unmarshaler HttpHeaders
+func (s *ScanJobResult) UnmarshalJSON(data []byte) error {
+	type unmarshaler ScanJobResult
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*h = HttpHeaders(value)
+	*s = ScanJobResult(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *h)
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
 	if err != nil {
 		return err
 	}
-	h.extraProperties = extraProperties
+	s.extraProperties = extraProperties
 
-	h._rawJSON = json.RawMessage(data)
+	s._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (h *HttpHeaders) String() string {
-	if len(h._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(h._rawJSON); err == nil {
+func (s *ScanJobResult) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(h); err == nil {
+	if value, err := core.StringifyJSON(s); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", h)
+	return fmt.Sprintf("%#v", s)
 }
 
-type PublicKeyAlgorithm string
+type ScanJobStatus string
 
 const (
-	PublicKeyAlgorithmRsa     PublicKeyAlgorithm = "RSA"
-	PublicKeyAlgorithmDsa     PublicKeyAlgorithm = "DSA"
-	PublicKeyAlgorithmEcdsa   PublicKeyAlgorithm = "ECDSA"
-	PublicKeyAlgorithmEd25519 PublicKeyAlgorithm = "Ed25519"
-	PublicKeyAlgorithmUnknown PublicKeyAlgorithm = "Unknown"
+	ScanJobStatusQueued    ScanJobStatus = "QUEUED"
+	ScanJobStatusRunning   ScanJobStatus = "RUNNING"
+	ScanJobStatusSucceeded ScanJobStatus = "SUCCEEDED"
+	ScanJobStatusFailed    ScanJobStatus = "FAILED"
+	ScanJobStatusCanceled  ScanJobStatus = "CANCELED"
 )
 
-func NewPublicKeyAlgorithmFromString(s string) (PublicKeyAlgorithm, error) {
+func NewScanJobStatusFromString(s string) (ScanJobStatus, error) {
 	switch s {
-	case "RSA":
-		return PublicKeyAlgorithmRsa, nil
-	case "DSA":
-		return PublicKeyAlgorithmDsa, nil
-	case "ECDSA":
-		return PublicKeyAlgorithmEcdsa, nil
-	case "Ed25519":
-		return PublicKeyAlgorithmEd25519, nil
-	case "Unknown":
-		return PublicKeyAlgorithmUnknown, nil
-	}
-	var t PublicKeyAlgorithm
+	case "QUEUED":
+		return ScanJobStatusQueued, nil
+	case "RUNNING":
+		return ScanJobStatusRunning, nil
+	case "SUCCEEDED":
+		return ScanJobStatusSucceeded, nil
+	case "FAILED":
+		return ScanJobStatusFailed, nil
+	case "CANCELED":
+		return ScanJobStatusCanceled, nil
+	}
+	var t ScanJobStatus
 	return "", fmt.Errorf("%s is not a valid %T", s, t)
 }
 
-func (p PublicKeyAlgorithm) Ptr() *PublicKeyAlgorithm {
-	return &p
+func (s ScanJobStatus) Ptr() *ScanJobStatus {
+	return &s
 }
 
-type SignatureAlgorithm string
+type ScanJobType string
 
 const (
-	SignatureAlgorithmMd2Rsa       SignatureAlgorithm = "MD2RSA"
-	SignatureAlgorithmMd5Rsa       SignatureAlgorithm = "MD5RSA"
-	SignatureAlgorithmSha1Rsa      SignatureAlgorithm = "SHA1RSA"
-	SignatureAlgorithmSha256Rsa    SignatureAlgorithm = "SHA256RSA"
-	SignatureAlgorithmSha384Rsa    SignatureAlgorithm = "SHA384RSA"
-	SignatureAlgorithmSha512Rsa    SignatureAlgorithm = "SHA512RSA"
-	SignatureAlgorithmDsasha1      SignatureAlgorithm = "DSASHA1"
-	SignatureAlgorithmDsasha256    SignatureAlgorithm = "DSASHA256"
-	SignatureAlgorithmEcdsasha1    SignatureAlgorithm = "ECDSASHA1"
-	SignatureAlgorithmEcdsasha256  SignatureAlgorithm = "ECDSASHA256"
-	SignatureAlgorithmEcdsasha384  SignatureAlgorithm = "ECDSASHA384"
-	SignatureAlgorithmEcdsasha512  SignatureAlgorithm = "ECDSASHA512"
-	SignatureAlgorithmSha256Rsapss SignatureAlgorithm = "SHA256RSAPSS"
-	SignatureAlgorithmSha384Rsapss SignatureAlgorithm = "SHA384RSAPSS"
-	SignatureAlgorithmSha512Rsapss SignatureAlgorithm = "SHA512RSAPSS"
-	SignatureAlgorithmEd25519      SignatureAlgorithm = "Ed25519"
+	ScanJobTypeSwagger      ScanJobType = "SWAGGER"
+	ScanJobTypeGraphql      ScanJobType = "GRAPHQL"
+	ScanJobTypeGrpc         ScanJobType = "GRPC"
+	ScanJobTypeFingerprint  ScanJobType = "FINGERPRINT"
+	ScanJobTypeWebserver    ScanJobType = "WEBSERVER"
+	ScanJobTypeFuzzPath     ScanJobType = "FUZZ_PATH"
+	ScanJobTypeRouteCapture ScanJobType = "ROUTE_CAPTURE"
+	ScanJobTypeRequests     ScanJobType = "REQUESTS"
 )
 
-func NewSignatureAlgorithmFromString(s string) (SignatureAlgorithm, error) {
+func NewScanJobTypeFromString(s string) (ScanJobType, error) {
 	switch s {
-	case "MD2RSA":
-		return SignatureAlgorithmMd2Rsa, nil
-	case "MD5RSA":
-		return SignatureAlgorithmMd5Rsa, nil
-	case "SHA1RSA":
-		return SignatureAlgorithmSha1Rsa, nil
-	case "SHA256RSA":
-		return SignatureAlgorithmSha256Rsa, nil
-	case "SHA384RSA":
-		return SignatureAlgorithmSha384Rsa, nil
-	case "SHA512RSA":
-		return SignatureAlgorithmSha512Rsa, nil
-	case "DSASHA1":
-		return SignatureAlgorithmDsasha1, nil
-	case "DSASHA256":
-		return SignatureAlgorithmDsasha256, nil
-	case "ECDSASHA1":
-		return SignatureAlgorithmEcdsasha1, nil
-	case "ECDSASHA256":
-		return SignatureAlgorithmEcdsasha256, nil
-	case "ECDSASHA384":
-		return SignatureAlgorithmEcdsasha384, nil
-	case "ECDSASHA512":
-		return SignatureAlgorithmEcdsasha512, nil
-	case "SHA256RSAPSS":
-		return SignatureAlgorithmSha256Rsapss, nil
-	case "SHA384RSAPSS":
-		return SignatureAlgorithmSha384Rsapss, nil
-	case "SHA512RSAPSS":
-		return SignatureAlgorithmSha512Rsapss, nil
-	case "Ed25519":
-		return SignatureAlgorithmEd25519, nil
-	}
-	var t SignatureAlgorithm
+	case "SWAGGER":
+		return ScanJobTypeSwagger, nil
+	case "GRAPHQL":
+		return ScanJobTypeGraphql, nil
+	case "GRPC":
+		return ScanJobTypeGrpc, nil
+	case "FINGERPRINT":
+		return ScanJobTypeFingerprint, nil
+	case "WEBSERVER":
+		return ScanJobTypeWebserver, nil
+	case "FUZZ_PATH":
+		return ScanJobTypeFuzzPath, nil
+	case "ROUTE_CAPTURE":
+		return ScanJobTypeRouteCapture, nil
+	case "REQUESTS":
+		return ScanJobTypeRequests, nil
+	}
+	var t ScanJobType
 	return "", fmt.Errorf("%s is not a valid %T", s, t)
 }
 
-func (s SignatureAlgorithm) Ptr() *SignatureAlgorithm {
+func (s ScanJobType) Ptr() *ScanJobType {
 	return &s
 }
 
-type TlsInfo struct {
-	Version      *TlsVersion    `json:"version,omitempty" url:"version,omitempty"`
-	CipherSuite  *string        `json:"cipherSuite,omitempty" url:"cipherSuite,omitempty"`
-	Certificates []*Certificate `json:"certificates,omitempty" url:"certificates,omitempty"`
+type PageCaptureReport struct {
+	Target      string   `json:"target" url:"target"`
+	HtmlEncoded *string  `json:"html_encoded,omitempty" url:"html_encoded,omitempty"`
+	Errors      []string `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (t *TlsInfo) GetExtraProperties() map[string]interface{} {
-	return t.extraProperties
+func (p *PageCaptureReport) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (t *TlsInfo) UnmarshalJSON(data []byte) error {
-	type unmarshaler TlsInfo
+func (p *PageCaptureReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler PageCaptureReport
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*t = TlsInfo(value)
+	*p = PageCaptureReport(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *t)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	t.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	t._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (t *TlsInfo) String() string {
-	if len(t._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(t._rawJSON); err == nil {
+func (p *PageCaptureReport) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(t); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", t)
+	return fmt.Sprintf("%#v", p)
 }
 
-type FuzzPathReport struct {
-	Target                   string        `json:"target" url:"target"`
-	Urls                     []*UrlDetails `json:"urls,omitempty" url:"urls,omitempty"`
-	UrlsSkippedFromBaseMatch []*UrlDetails `json:"urlsSkippedFromBaseMatch,omitempty" url:"urlsSkippedFromBaseMatch,omitempty"`
-	Errors                   []string      `json:"errors,omitempty" url:"errors,omitempty"`
+type PageScreenshotReport struct {
+	Target      string   `json:"target" url:"target"`
+	HtmlEncoded *string  `json:"html_encoded,omitempty" url:"html_encoded,omitempty"`
+	Screenshot  *[]byte  `json:"screenshot,omitempty" url:"screenshot,omitempty"`
+	Errors      []string `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (f *FuzzPathReport) GetExtraProperties() map[string]interface{} {
-	return f.extraProperties
+func (p *PageScreenshotReport) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (f *FuzzPathReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler FuzzPathReport
+func (p *PageScreenshotReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler PageScreenshotReport
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*f = FuzzPathReport(value)
+	*p = PageScreenshotReport(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *f)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	f.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	f._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (f *FuzzPathReport) String() string {
-	if len(f._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(f._rawJSON); err == nil {
+func (p *PageScreenshotReport) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(f); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", f)
+	return fmt.Sprintf("%#v", p)
 }
 
-type UrlDetails struct {
-	Url    string `json:"url" url:"url"`
-	Status string `json:"status" url:"status"`
-	Size   int    `json:"size" url:"size"`
+type PipelineDefinition struct {
+	Name        *string          `json:"name,omitempty" url:"name,omitempty"`
+	Concurrency *int             `json:"concurrency,omitempty" url:"concurrency,omitempty"`
+	Stages      []*PipelineStage `json:"stages" url:"stages"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (u *UrlDetails) GetExtraProperties() map[string]interface{} {
-	return u.extraProperties
+func (p *PipelineDefinition) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (u *UrlDetails) UnmarshalJSON(data []byte) error {
-	type unmarshaler UrlDetails
+func (p *PipelineDefinition) UnmarshalJSON(data []byte) error {
+	type unmarshaler PipelineDefinition
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*u = UrlDetails(value)
+	*p = PipelineDefinition(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *u)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	u.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	u._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (u *UrlDetails) String() string {
-	if len(u._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(u._rawJSON); err == nil {
+func (p *PipelineDefinition) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(u); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", u)
+	return fmt.Sprintf("%#v", p)
 }
 
-type GraphQlData struct {
-	Schema *GraphQlSchemaData `json:"__schema,omitempty" url:"__schema,omitempty"`
+type PipelineScanType string
+
+const (
+	PipelineScanTypeWebserverProbe PipelineScanType = "WEBSERVER_PROBE"
+	PipelineScanTypeFingerprint    PipelineScanType = "FINGERPRINT"
+	PipelineScanTypeSpider         PipelineScanType = "SPIDER"
+	PipelineScanTypeRouteCapture   PipelineScanType = "ROUTE_CAPTURE"
+	PipelineScanTypeAppFingerprint PipelineScanType = "APP_FINGERPRINT"
+	PipelineScanTypeSwagger        PipelineScanType = "SWAGGER"
+	PipelineScanTypeGraphql        PipelineScanType = "GRAPHQL"
+	PipelineScanTypeFuzzPath       PipelineScanType = "FUZZ_PATH"
+	PipelineScanTypeVuln           PipelineScanType = "VULN"
+)
+
+func NewPipelineScanTypeFromString(s string) (PipelineScanType, error) {
+	switch s {
+	case "WEBSERVER_PROBE":
+		return PipelineScanTypeWebserverProbe, nil
+	case "FINGERPRINT":
+		return PipelineScanTypeFingerprint, nil
+	case "SPIDER":
+		return PipelineScanTypeSpider, nil
+	case "ROUTE_CAPTURE":
+		return PipelineScanTypeRouteCapture, nil
+	case "APP_FINGERPRINT":
+		return PipelineScanTypeAppFingerprint, nil
+	case "SWAGGER":
+		return PipelineScanTypeSwagger, nil
+	case "GRAPHQL":
+		return PipelineScanTypeGraphql, nil
+	case "FUZZ_PATH":
+		return PipelineScanTypeFuzzPath, nil
+	case "VULN":
+		return PipelineScanTypeVuln, nil
+	}
+	var t PipelineScanType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (p PipelineScanType) Ptr() *PipelineScanType {
+	return &p
+}
+
+type PipelineSelector struct {
+	Stage     string               `json:"stage" url:"stage"`
+	Select    PipelineSelectorType `json:"select" url:"select"`
+	Tags      []string             `json:"tags,omitempty" url:"tags,omitempty"`
+	Templates []string             `json:"templates,omitempty" url:"templates,omitempty"`
+	Match     *string              `json:"match,omitempty" url:"match,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlData) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (p *PipelineSelector) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (g *GraphQlData) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlData
+func (p *PipelineSelector) UnmarshalJSON(data []byte) error {
+	type unmarshaler PipelineSelector
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlData(value)
+	*p = PipelineSelector(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlData) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (p *PipelineSelector) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", p)
 }
 
-type GraphQlField struct {
-	Name string `json:"name" url:"name"`
+type PipelineSelectorType string
+
+const (
+	PipelineSelectorTypeTargets  PipelineSelectorType = "TARGETS"
+	PipelineSelectorTypeUrls     PipelineSelectorType = "URLS"
+	PipelineSelectorTypeLinks    PipelineSelectorType = "LINKS"
+	PipelineSelectorTypeRoutes   PipelineSelectorType = "ROUTES"
+	PipelineSelectorTypeFindings PipelineSelectorType = "FINDINGS"
+)
+
+func NewPipelineSelectorTypeFromString(s string) (PipelineSelectorType, error) {
+	switch s {
+	case "TARGETS":
+		return PipelineSelectorTypeTargets, nil
+	case "URLS":
+		return PipelineSelectorTypeUrls, nil
+	case "LINKS":
+		return PipelineSelectorTypeLinks, nil
+	case "ROUTES":
+		return PipelineSelectorTypeRoutes, nil
+	case "FINDINGS":
+		return PipelineSelectorTypeFindings, nil
+	}
+	var t PipelineSelectorType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (p PipelineSelectorType) Ptr() *PipelineSelectorType {
+	return &p
+}
+
+type PipelineStage struct {
+	Name           string              `json:"name" url:"name"`
+	Scan           PipelineScanType    `json:"scan" url:"scan"`
+	Targets        []string            `json:"targets,omitempty" url:"targets,omitempty"`
+	From           []*PipelineSelector `json:"from,omitempty" url:"from,omitempty"`
+	Tags           []string            `json:"tags,omitempty" url:"tags,omitempty"`
+	Severities     []string            `json:"severities,omitempty" url:"severities,omitempty"`
+	Pathlist       *string             `json:"pathlist,omitempty" url:"pathlist,omitempty"`
+	ResponseCodes  *string             `json:"responseCodes,omitempty" url:"responseCodes,omitempty"`
+	CaptureMethod  *PageCaptureMethod  `json:"captureMethod,omitempty" url:"captureMethod,omitempty"`
+	TimeoutSeconds *int                `json:"timeoutSeconds,omitempty" url:"timeoutSeconds,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlField) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (p *PipelineStage) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (g *GraphQlField) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlField
+func (p *PipelineStage) UnmarshalJSON(data []byte) error {
+	type unmarshaler PipelineStage
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlField(value)
+	*p = PipelineStage(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlField) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (p *PipelineStage) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", p)
 }
 
-type GraphQlQuery struct {
-	Type   string   `json:"type" url:"type"`
-	Fields []string `json:"fields,omitempty" url:"fields,omitempty"`
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "HIGH"
+	ConfidenceMedium Confidence = "MEDIUM"
+	ConfidenceLow    Confidence = "LOW"
+)
+
+func NewConfidenceFromString(s string) (Confidence, error) {
+	switch s {
+	case "HIGH":
+		return ConfidenceHigh, nil
+	case "MEDIUM":
+		return ConfidenceMedium, nil
+	case "LOW":
+		return ConfidenceLow, nil
+	}
+	var t Confidence
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (c Confidence) Ptr() *Confidence {
+	return &c
+}
+
+type ParamLocation string
+
+const (
+	ParamLocationPath      ParamLocation = "PATH"
+	ParamLocationQuery     ParamLocation = "QUERY"
+	ParamLocationHeader    ParamLocation = "HEADER"
+	ParamLocationBody      ParamLocation = "BODY"
+	ParamLocationForm      ParamLocation = "FORM"
+	ParamLocationMultipart ParamLocation = "MULTIPART"
+)
+
+func NewParamLocationFromString(s string) (ParamLocation, error) {
+	switch s {
+	case "PATH":
+		return ParamLocationPath, nil
+	case "QUERY":
+		return ParamLocationQuery, nil
+	case "HEADER":
+		return ParamLocationHeader, nil
+	case "BODY":
+		return ParamLocationBody, nil
+	case "FORM":
+		return ParamLocationForm, nil
+	case "MULTIPART":
+		return ParamLocationMultipart, nil
+	}
+	var t ParamLocation
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (p ParamLocation) Ptr() *ParamLocation {
+	return &p
+}
+
+type ParsedParams struct {
+	PathParams      map[string]string `json:"pathParams,omitempty" url:"pathParams,omitempty"`
+	QueryParams     map[string]string `json:"queryParams,omitempty" url:"queryParams,omitempty"`
+	HeaderParams    map[string]string `json:"headerParams,omitempty" url:"headerParams,omitempty"`
+	BodyParams      string            `json:"bodyParams" url:"bodyParams"`
+	FormParams      map[string]string `json:"formParams,omitempty" url:"formParams,omitempty"`
+	MultipartParams map[string]string `json:"multipartParams,omitempty" url:"multipartParams,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlQuery) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (p *ParsedParams) GetExtraProperties() map[string]interface{} {
+	return p.extraProperties
 }
 
-func (g *GraphQlQuery) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlQuery
+func (p *ParsedParams) UnmarshalJSON(data []byte) error {
+	type unmarshaler ParsedParams
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlQuery(value)
+	*p = ParsedParams(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *p)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	p.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	p._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlQuery) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (p *ParsedParams) String() string {
+	if len(p._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(p); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", p)
 }
 
-type GraphQlSchema struct {
-	Data *GraphQlData `json:"data,omitempty" url:"data,omitempty"`
+type ReflectionContext string
+
+const (
+	ReflectionContextHtmlBody      ReflectionContext = "HTML_BODY"
+	ReflectionContextHtmlAttribute ReflectionContext = "HTML_ATTRIBUTE"
+	ReflectionContextHtmlComment   ReflectionContext = "HTML_COMMENT"
+	ReflectionContextScript        ReflectionContext = "SCRIPT"
+	ReflectionContextUrl           ReflectionContext = "URL"
+)
+
+func NewReflectionContextFromString(s string) (ReflectionContext, error) {
+	switch s {
+	case "HTML_BODY":
+		return ReflectionContextHtmlBody, nil
+	case "HTML_ATTRIBUTE":
+		return ReflectionContextHtmlAttribute, nil
+	case "HTML_COMMENT":
+		return ReflectionContextHtmlComment, nil
+	case "SCRIPT":
+		return ReflectionContextScript, nil
+	case "URL":
+		return ReflectionContextUrl, nil
+	}
+	var t ReflectionContext
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (r ReflectionContext) Ptr() *ReflectionContext {
+	return &r
+}
+
+type RequestBatchReport struct {
+	Target          string           `json:"target" url:"target"`
+	BaseUrl         string           `json:"baseUrl" url:"baseUrl"`
+	Results         []*RequestResult `json:"results,omitempty" url:"results,omitempty"`
+	Reachable       []string         `json:"reachable,omitempty" url:"reachable,omitempty"`
+	Erroring        []string         `json:"erroring,omitempty" url:"erroring,omitempty"`
+	Unauthenticated []string         `json:"unauthenticated,omitempty" url:"unauthenticated,omitempty"`
+	Errors          []string         `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlSchema) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (r *RequestBatchReport) GetExtraProperties() map[string]interface{} {
+	return r.extraProperties
 }
 
-func (g *GraphQlSchema) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlSchema
+func (r *RequestBatchReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler RequestBatchReport
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlSchema(value)
+	*r = RequestBatchReport(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	r.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	r._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlSchema) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (r *RequestBatchReport) String() string {
+	if len(r._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(r); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", r)
 }
 
-type GraphQlSchemaData struct {
-	Types []*GraphQlType `json:"types,omitempty" url:"types,omitempty"`
+type RequestOutcome string
+
+const (
+	RequestOutcomeReachable    RequestOutcome = "REACHABLE"
+	RequestOutcomeUnauthorized RequestOutcome = "UNAUTHORIZED"
+	RequestOutcomeClientError  RequestOutcome = "CLIENT_ERROR"
+	RequestOutcomeServerError  RequestOutcome = "SERVER_ERROR"
+	RequestOutcomeFailed       RequestOutcome = "FAILED"
+)
+
+func NewRequestOutcomeFromString(s string) (RequestOutcome, error) {
+	switch s {
+	case "REACHABLE":
+		return RequestOutcomeReachable, nil
+	case "UNAUTHORIZED":
+		return RequestOutcomeUnauthorized, nil
+	case "CLIENT_ERROR":
+		return RequestOutcomeClientError, nil
+	case "SERVER_ERROR":
+		return RequestOutcomeServerError, nil
+	case "FAILED":
+		return RequestOutcomeFailed, nil
+	}
+	var t RequestOutcome
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (r RequestOutcome) Ptr() *RequestOutcome {
+	return &r
+}
+
+type RequestParams struct {
+	BaseUrl         *string `json:"baseUrl,omitempty" url:"baseUrl,omitempty"`
+	Path            *string `json:"path,omitempty" url:"path,omitempty"`
+	Method          *string `json:"method,omitempty" url:"method,omitempty"`
+	PathParams      string  `json:"pathParams" url:"pathParams"`
+	QueryParams     string  `json:"queryParams" url:"queryParams"`
+	HeaderParams    string  `json:"headerParams" url:"headerParams"`
+	BodyParams      string  `json:"bodyParams" url:"bodyParams"`
+	FormParams      string  `json:"formParams" url:"formParams"`
+	MultipartParams string  `json:"multipartParams" url:"multipartParams"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlSchemaData) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (r *RequestParams) GetExtraProperties() map[string]interface{} {
+	return r.extraProperties
 }
 
-func (g *GraphQlSchemaData) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlSchemaData
+func (r *RequestParams) UnmarshalJSON(data []byte) error {
+	type unmarshaler RequestParams
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlSchemaData(value)
+	*r = RequestParams(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	r.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	r._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlSchemaData) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (r *RequestParams) String() string {
+	if len(r._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(r); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", r)
 }
 
-type GraphQlType struct {
-	Name        string          `json:"name" url:"name"`
-	Kind        string          `json:"kind" url:"kind"`
-	Description *string         `json:"description,omitempty" url:"description,omitempty"`
-	Fields      []*GraphQlField `json:"fields,omitempty" url:"fields,omitempty"`
+type RequestReport struct {
+	BaseUrl         string            `json:"baseUrl" url:"baseUrl"`
+	Path            string            `json:"path" url:"path"`
+	Method          HttpMethod        `json:"method" url:"method"`
+	PathParams      map[string]string `json:"pathParams,omitempty" url:"pathParams,omitempty"`
+	QueryParams     map[string]string `json:"queryParams,omitempty" url:"queryParams,omitempty"`
+	HeaderParams    map[string]string `json:"headerParams,omitempty" url:"headerParams,omitempty"`
+	BodyParams      *string           `json:"bodyParams,omitempty" url:"bodyParams,omitempty"`
+	FormParams      map[string]string `json:"formParams,omitempty" url:"formParams,omitempty"`
+	MultipartParams map[string]string `json:"multipartParams,omitempty" url:"multipartParams,omitempty"`
+	VulnTypes       []VulnType        `json:"vulnTypes,omitempty" url:"vulnTypes,omitempty"`
+	StatusCode      int               `json:"statusCode" url:"statusCode"`
+	ResponseBody    string            `json:"responseBody" url:"responseBody"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty" url:"responseHeaders,omitempty"`
+	Findings        []*VulnFinding    `json:"findings,omitempty" url:"findings,omitempty"`
+	Errors          []string          `json:"errors,omitempty" url:"errors,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (g *GraphQlType) GetExtraProperties() map[string]interface{} {
-	return g.extraProperties
+func (r *RequestReport) GetExtraProperties() map[string]interface{} {
+	return r.extraProperties
 }
 
-func (g *GraphQlType) UnmarshalJSON(data []byte) error {
-	type unmarshaler GraphQlType
+func (r *RequestReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler RequestReport
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*g = GraphQlType(value)
+	*r = RequestReport(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *g)
+	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
 		return err
 	}
-	g.extraProperties = extraProperties
+	r.extraProperties = extraProperties
 
-	g._rawJSON = json.RawMessage(data)
+	r._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (g *GraphQlType) String() string {
-	if len(g._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(g._rawJSON); err == nil {
+func (r *RequestReport) String() string {
+	if len(r._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(g); err == nil {
+	if value, err := core.StringifyJSON(r); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", g)
+	return fmt.Sprintf("%#v", r)
 }
 
-type PageCaptureReport struct {
-	Target      string   `json:"target" url:"target"`
-	HtmlEncoded *string  `json:"html_encoded,omitempty" url:"html_encoded,omitempty"`
-	Errors      []string `json:"errors,omitempty" url:"errors,omitempty"`
+type RequestResult struct {
+	Route            string         `json:"route" url:"route"`
+	Outcome          RequestOutcome `json:"outcome" url:"outcome"`
+	SecurityRequired *bool          `json:"securityRequired,omitempty" url:"securityRequired,omitempty"`
+	CredentialsSent  bool           `json:"credentialsSent" url:"credentialsSent"`
+	Report           *RequestReport `json:"report" url:"report"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (p *PageCaptureReport) GetExtraProperties() map[string]interface{} {
-	return p.extraProperties
+func (r *RequestResult) GetExtraProperties() map[string]interface{} {
+	return r.extraProperties
 }
 
-func (p *PageCaptureReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler PageCaptureReport
+func (r *RequestResult) UnmarshalJSON(data []byte) error {
+	type unmarshaler RequestResult
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*p = PageCaptureReport(value)
+	*r = RequestResult(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *p)
+	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
 		return err
 	}
-	p.extraProperties = extraProperties
+	r.extraProperties = extraProperties
 
-	p._rawJSON = json.RawMessage(data)
+	r._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (p *PageCaptureReport) String() string {
-	if len(p._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
+func (r *RequestResult) String() string {
+	if len(r._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(p); err == nil {
+	if value, err := core.StringifyJSON(r); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", p)
+	return fmt.Sprintf("%#v", r)
 }
 
-type PageScreenshotReport struct {
-	Target      string   `json:"target" url:"target"`
-	HtmlEncoded *string  `json:"html_encoded,omitempty" url:"html_encoded,omitempty"`
-	Screenshot  *[]byte  `json:"screenshot,omitempty" url:"screenshot,omitempty"`
-	Errors      []string `json:"errors,omitempty" url:"errors,omitempty"`
+type VulnFinding struct {
+	VulnType     VulnType           `json:"vulnType" url:"vulnType"`
+	Technique    string             `json:"technique" url:"technique"`
+	Location     ParamLocation      `json:"location" url:"location"`
+	Parameter    string             `json:"parameter" url:"parameter"`
+	Payload      string             `json:"payload" url:"payload"`
+	Evidence     string             `json:"evidence" url:"evidence"`
+	Confidence   Confidence         `json:"confidence" url:"confidence"`
+	Engine       *string            `json:"engine,omitempty" url:"engine,omitempty"`
+	Context      *ReflectionContext `json:"context,omitempty" url:"context,omitempty"`
+	StatusCode   *int               `json:"statusCode,omitempty" url:"statusCode,omitempty"`
+	ResponseTime *int               `json:"responseTime,omitempty" url:"responseTime,omitempty"`
+	ResponseSize *int               `json:"responseSize,omitempty" url:"responseSize,omitempty"`
+	ResponseHash *string            `json:"responseHash,omitempty" url:"responseHash,omitempty"`
+	Framework    *string            `json:"framework,omitempty" url:"framework,omitempty"`
+	Snippet      *string            `json:"snippet,omitempty" url:"snippet,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (p *PageScreenshotReport) GetExtraProperties() map[string]interface{} {
-	return p.extraProperties
+func (v *VulnFinding) GetExtraProperties() map[string]interface{} {
+	return v.extraProperties
 }
 
-func (p *PageScreenshotReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler PageScreenshotReport
+func (v *VulnFinding) UnmarshalJSON(data []byte) error {
+	type unmarshaler VulnFinding
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*p = PageScreenshotReport(value)
+	*v = VulnFinding(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *p)
+	extraProperties, err := core.ExtractExtraProperties(data, *v)
 	if err != nil {
 		return err
 	}
-	p.extraProperties = extraProperties
+	v.extraProperties = extraProperties
 
-	p._rawJSON = json.RawMessage(data)
+	v._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (p *PageScreenshotReport) String() string {
-	if len(p._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
+func (v *VulnFinding) String() string {
+	if len(v._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(v._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(p); err == nil {
+	if value, err := core.StringifyJSON(v); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", p)
+	return fmt.Sprintf("%#v", v)
 }
 
-type ParsedParams struct {
-	PathParams      map[string]string `json:"pathParams,omitempty" url:"pathParams,omitempty"`
-	QueryParams     map[string]string `json:"queryParams,omitempty" url:"queryParams,omitempty"`
-	HeaderParams    map[string]string `json:"headerParams,omitempty" url:"headerParams,omitempty"`
-	BodyParams      string            `json:"bodyParams" url:"bodyParams"`
-	FormParams      map[string]string `json:"formParams,omitempty" url:"formParams,omitempty"`
-	MultipartParams map[string]string `json:"multipartParams,omitempty" url:"multipartParams,omitempty"`
+type VulnType string
+
+const (
+	VulnTypeCommand        VulnType = "COMMAND"
+	VulnTypeSql            VulnType = "SQL"
+	VulnTypeXss            VulnType = "XSS"
+	VulnTypeAuth           VulnType = "AUTH"
+	VulnTypeSensitiveerror VulnType = "SENSITIVEERROR"
+	VulnTypeSqlinjection   VulnType = "SQLINJECTION"
+	VulnTypeTemplate       VulnType = "TEMPLATE"
+	VulnTypeNosql          VulnType = "NOSQL"
+	VulnTypeMassassignment VulnType = "MASSASSIGNMENT"
+)
+
+func NewVulnTypeFromString(s string) (VulnType, error) {
+	switch s {
+	case "COMMAND":
+		return VulnTypeCommand, nil
+	case "SQL":
+		return VulnTypeSql, nil
+	case "XSS":
+		return VulnTypeXss, nil
+	case "AUTH":
+		return VulnTypeAuth, nil
+	case "SENSITIVEERROR":
+		return VulnTypeSensitiveerror, nil
+	case "SQLINJECTION":
+		return VulnTypeSqlinjection, nil
+	case "TEMPLATE":
+		return VulnTypeTemplate, nil
+	case "NOSQL":
+		return VulnTypeNosql, nil
+	case "MASSASSIGNMENT":
+		return VulnTypeMassassignment, nil
+	}
+	var t VulnType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (v VulnType) Ptr() *VulnType {
+	return &v
+}
+
+type ResultRecord struct {
+	Run       string           `json:"run" url:"run"`
+	Command   string           `json:"command" url:"command"`
+	Timestamp time.Time        `json:"timestamp" url:"timestamp"`
+	Type      ResultRecordType `json:"type" url:"type"`
+	Target    string           `json:"target" url:"target"`
+	Module    *string          `json:"module,omitempty" url:"module,omitempty"`
+	Severity  *string          `json:"severity,omitempty" url:"severity,omitempty"`
+	Url       *string          `json:"url,omitempty" url:"url,omitempty"`
+	Method    *string          `json:"method,omitempty" url:"method,omitempty"`
+	Name      *string          `json:"name,omitempty" url:"name,omitempty"`
+	Data      interface{}      `json:"data,omitempty" url:"data,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (p *ParsedParams) GetExtraProperties() map[string]interface{} {
-	return p.extraProperties
+func (r *ResultRecord) GetExtraProperties() map[string]interface{} {
+	return r.extraProperties
 }
 
-func (p *ParsedParams) UnmarshalJSON(data []byte) error {
-	type unmarshaler ParsedParams
+func (r *ResultRecord) UnmarshalJSON(data []byte) error {
+	type unmarshaler ResultRecord
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*p = ParsedParams(value)
+	*r = ResultRecord(value)
 
-	extraProperties, err := core.ExtractExtraProperties(data, *p)
+	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
 		return err
 	}
-	p.extraProperties = extraProperties
+	r.extraProperties = extraProperties
 
-	p._rawJSON = json.RawMessage(data)
+	r._rawJSON = json.RawMessage(data)
 	return nil
 }
 
-func (p *ParsedParams) String() string {
-	if len(p._rawJSON) > 0 {
-		if value, err := core.StringifyJSON(p._rawJSON); err == nil {
+func (r *ResultRecord) String() string {
+	if len(r._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
 		}
 	}
-	if value, err := core.StringifyJSON(p); err == nil {
+	if value, err := core.StringifyJSON(r); err == nil {
 		return value
 	}
-	return fmt.Sprintf("%#v", p)
+	return fmt.Sprintf("%#v", r)
 }
 
-type RequestParams struct {
-	PathParams      string `json:"pathParams" url:"pathParams"`
-	QueryParams     string `json:"queryParams" url:"queryParams"`
-	HeaderParams    string `json:"headerParams" url:"headerParams"`
-	BodyParams      string `json:"bodyParams" url:"bodyParams"`
-	FormParams      string `json:"formParams" url:"formParams"`
-	MultipartParams string `json:"multipartParams" url:"multipartParams"`
+type ResultRecordType string
+
+const (
+	ResultRecordTypeTarget      ResultRecordType = "TARGET"
+	ResultRecordTypeUrl         ResultRecordType = "URL"
+	ResultRecordTypeRoute       ResultRecordType = "ROUTE"
+	ResultRecordTypeFinding     ResultRecordType = "FINDING"
+	ResultRecordTypeCertificate ResultRecordType = "CERTIFICATE"
+	ResultRecordTypeScreenshot  ResultRecordType = "SCREENSHOT"
+)
+
+func NewResultRecordTypeFromString(s string) (ResultRecordType, error) {
+	switch s {
+	case "TARGET":
+		return ResultRecordTypeTarget, nil
+	case "URL":
+		return ResultRecordTypeUrl, nil
+	case "ROUTE":
+		return ResultRecordTypeRoute, nil
+	case "FINDING":
+		return ResultRecordTypeFinding, nil
+	case "CERTIFICATE":
+		return ResultRecordTypeCertificate, nil
+	case "SCREENSHOT":
+		return ResultRecordTypeScreenshot, nil
+	}
+	var t ResultRecordType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (r ResultRecordType) Ptr() *ResultRecordType {
+	return &r
+}
+
+type ResultRun struct {
+	Id           string     `json:"id" url:"id"`
+	Command      string     `json:"command" url:"command"`
+	StartedAt    time.Time  `json:"startedAt" url:"startedAt"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty" url:"completedAt,omitempty"`
+	Status       int        `json:"status" url:"status"`
+	ErrorMessage *string    `json:"errorMessage,omitempty" url:"errorMessage,omitempty"`
+	Records      int        `json:"records" url:"records"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (r *RequestParams) GetExtraProperties() map[string]interface{} {
+func (r *ResultRun) GetExtraProperties() map[string]interface{} {
 	return r.extraProperties
 }
 
-func (r *RequestParams) UnmarshalJSON(data []byte) error {
-	type unmarshaler RequestParams
+func (r *ResultRun) UnmarshalJSON(data []byte) error {
+	type unmarshaler ResultRun
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*r = RequestParams(value)
+	*r = ResultRun(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
@@ -902,7 +2478,7 @@ func (r *RequestParams) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (r *RequestParams) String() string {
+func (r *ResultRun) String() string {
 	if len(r._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
@@ -914,37 +2490,26 @@ func (r *RequestParams) String() string {
 	return fmt.Sprintf("%#v", r)
 }
 
-type RequestReport struct {
-	BaseUrl         string            `json:"baseUrl" url:"baseUrl"`
-	Path            string            `json:"path" url:"path"`
-	Method          HttpMethod        `json:"method" url:"method"`
-	PathParams      map[string]string `json:"pathParams,omitempty" url:"pathParams,omitempty"`
-	QueryParams     map[string]string `json:"queryParams,omitempty" url:"queryParams,omitempty"`
-	HeaderParams    map[string]string `json:"headerParams,omitempty" url:"headerParams,omitempty"`
-	BodyParams      *string           `json:"bodyParams,omitempty" url:"bodyParams,omitempty"`
-	FormParams      map[string]string `json:"formParams,omitempty" url:"formParams,omitempty"`
-	MultipartParams map[string]string `json:"multipartParams,omitempty" url:"multipartParams,omitempty"`
-	VulnTypes       []VulnType        `json:"vulnTypes,omitempty" url:"vulnTypes,omitempty"`
-	StatusCode      int               `json:"statusCode" url:"statusCode"`
-	ResponseBody    string            `json:"responseBody" url:"responseBody"`
-	ResponseHeaders map[string]string `json:"responseHeaders,omitempty" url:"responseHeaders,omitempty"`
-	Errors          []string          `json:"errors,omitempty" url:"errors,omitempty"`
+type ResultsQueryReport struct {
+	Total   int             `json:"total" url:"total"`
+	Records []*ResultRecord `json:"records,omitempty" url:"records,omitempty"`
+	Runs    []*ResultRun    `json:"runs,omitempty" url:"runs,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
 }
 
-func (r *RequestReport) GetExtraProperties() map[string]interface{} {
+func (r *ResultsQueryReport) GetExtraProperties() map[string]interface{} {
 	return r.extraProperties
 }
 
-func (r *RequestReport) UnmarshalJSON(data []byte) error {
-	type unmarshaler RequestReport
+func (r *ResultsQueryReport) UnmarshalJSON(data []byte) error {
+	type unmarshaler ResultsQueryReport
 	var value unmarshaler
 	if err := json.Unmarshal(data, &value); err != nil {
 		return err
 	}
-	*r = RequestReport(value)
+	*r = ResultsQueryReport(value)
 
 	extraProperties, err := core.ExtractExtraProperties(data, *r)
 	if err != nil {
@@ -956,7 +2521,7 @@ func (r *RequestReport) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (r *RequestReport) String() string {
+func (r *ResultsQueryReport) String() string {
 	if len(r._rawJSON) > 0 {
 		if value, err := core.StringifyJSON(r._rawJSON); err == nil {
 			return value
@@ -968,46 +2533,6 @@ func (r *RequestReport) String() string {
 	return fmt.Sprintf("%#v", r)
 }
 
-type VulnType string
-
-const (
-	VulnTypeCommand        VulnType = "COMMAND"
-	VulnTypeSql            VulnType = "SQL"
-	VulnTypeXss            VulnType = "XSS"
-	VulnTypeAuth           VulnType = "AUTH"
-	VulnTypeSensitiveerror VulnType = "SENSITIVEERROR"
-	VulnTypeSqlinjection   VulnType = "SQLINJECTION"
-	VulnTypeTemplate       VulnType = "TEMPLATE"
-	VulnTypeNosql          VulnType = "NOSQL"
-)
-
-func NewVulnTypeFromString(s string) (VulnType, error) {
-	switch s {
-	case "COMMAND":
-		return VulnTypeCommand, nil
-	case "SQL":
-		return VulnTypeSql, nil
-	case "XSS":
-		return VulnTypeXss, nil
-	case "AUTH":
-		return VulnTypeAuth, nil
-	case "SENSITIVEERROR":
-		return VulnTypeSensitiveerror, nil
-	case "SQLINJECTION":
-		return VulnTypeSqlinjection, nil
-	case "TEMPLATE":
-		return VulnTypeTemplate, nil
-	case "NOSQL":
-		return VulnTypeNosql, nil
-	}
-	var t VulnType
-	return "", fmt.Errorf("%s is not a valid %T", s, t)
-}
-
-func (v VulnType) Ptr() *VulnType {
-	return &v
-}
-
 type BodyParams struct {
 	Name          string   `json:"name" url:"name"`
 	ExampleValues []string `json:"exampleValues,omitempty" url:"exampleValues,omitempty"`
@@ -1499,6 +3024,11 @@ type SchemaProperty struct {
 	AdditionalProperties *RequestSchema    `json:"additionalProperties,omitempty" url:"additionalProperties,omitempty"`
 	Enum                 []string          `json:"enum,omitempty" url:"enum,omitempty"`
 	Example              *string           `json:"example,omitempty" url:"example,omitempty"`
+	Default              *string           `json:"default,omitempty" url:"default,omitempty"`
+	Minimum              *float64          `json:"minimum,omitempty" url:"minimum,omitempty"`
+	Maximum              *float64          `json:"maximum,omitempty" url:"maximum,omitempty"`
+	MinLength            *int              `json:"minLength,omitempty" url:"minLength,omitempty"`
+	MaxLength            *int              `json:"maxLength,omitempty" url:"maxLength,omitempty"`
 
 	extraProperties map[string]interface{}
 	_rawJSON        json.RawMessage
@@ -1667,6 +3197,53 @@ func (s SecuritySchemeType) Ptr() *SecuritySchemeType {
 	return &s
 }
 
+type ScopeDefinition struct {
+	Hosts         []string `json:"hosts,omitempty" url:"hosts,omitempty"`
+	Cidrs         []string `json:"cidrs,omitempty" url:"cidrs,omitempty"`
+	Ports         []int    `json:"ports,omitempty" url:"ports,omitempty"`
+	PathPrefixes  []string `json:"pathPrefixes,omitempty" url:"pathPrefixes,omitempty"`
+	ExcludedHosts []string `json:"excludedHosts,omitempty" url:"excludedHosts,omitempty"`
+	ExcludedCidrs []string `json:"excludedCidrs,omitempty" url:"excludedCidrs,omitempty"`
+	ExcludedPaths []string `json:"excludedPaths,omitempty" url:"excludedPaths,omitempty"`
+
+	extraProperties map[string]interface{}
+	_rawJSON        json.RawMessage
+}
+
+func (s *ScopeDefinition) GetExtraProperties() map[string]interface{} {
+	return s.extraProperties
+}
+
+func (s *ScopeDefinition) UnmarshalJSON(data []byte) error {
+	type unmarshaler ScopeDefinition
+	var value unmarshaler
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*s = ScopeDefinition(value)
+
+	extraProperties, err := core.ExtractExtraProperties(data, *s)
+	if err != nil {
+		return err
+	}
+	s.extraProperties = extraProperties
+
+	s._rawJSON = json.RawMessage(data)
+	return nil
+}
+
+func (s *ScopeDefinition) String() string {
+	if len(s._rawJSON) > 0 {
+		if value, err := core.StringifyJSON(s._rawJSON); err == nil {
+			return value
+		}
+	}
+	if value, err := core.StringifyJSON(s); err == nil {
+		return value
+	}
+	return fmt.Sprintf("%#v", s)
+}
+
 type Attempt struct {
 	Name        ModuleName        `json:"name" url:"name"`
 	Timestamp   time.Time         `json:"timestamp" url:"timestamp"`
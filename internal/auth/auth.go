@@ -0,0 +1,263 @@
+// Package auth implements the authentication profiles of webscan. A profile file lists named profiles, each providing
+// the credentials of one way to authenticate against the targets: bearer tokens, basic auth, cookies, custom headers
+// or OAuth2 tokens obtained with the client credentials or refresh token grants. The selected profile becomes the
+// Authenticator of the HTTP client Factory, so that it applies to every command.
+package auth
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/reportfile"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// LoadProfile loads the profile file at path and returns the profile with the provided name. The name may be empty
+// when the file has a single profile. Values of the profile can reference environment variables as ${NAME} so that
+// secrets do not have to be stored in the file.
+func LoadProfile(path string, name string) (*webscan.AuthProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file %s: %v", path, err)
+	}
+	content, err := reportfile.Content(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile file %s: %v", path, err)
+	}
+	profiles := webscan.AuthProfiles{}
+	if err := json.Unmarshal(content, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to decode profile file %s: %v", path, err)
+	}
+	if name == "" {
+		if len(profiles.Profiles) != 1 || profiles.Profiles[0] == nil {
+			return nil, fmt.Errorf("profile file %s has %d profiles, select one with --auth-profile", path, len(profiles.Profiles))
+		}
+		return expandProfile(profiles.Profiles[0]), nil
+	}
+	names := []string{}
+	for _, profile := range profiles.Profiles {
+		if profile == nil {
+			continue
+		}
+		if profile.Name == name {
+			return expandProfile(profile), nil
+		}
+		names = append(names, profile.Name)
+	}
+	return nil, fmt.Errorf("profile %q not found in %s, available profiles: %s", name, path, strings.Join(names, ", "))
+}
+
+// expandProfile replaces the references to environment variables in the values of the profile.
+func expandProfile(profile *webscan.AuthProfile) *webscan.AuthProfile {
+	expanded := *profile
+	for _, value := range []**string{&expanded.Token, &expanded.Username, &expanded.Password, &expanded.CookieFile,
+		&expanded.TokenUrl, &expanded.ClientId, &expanded.ClientSecret, &expanded.Audience, &expanded.RefreshToken} {
+		if *value != nil {
+			v := os.ExpandEnv(**value)
+			*value = &v
+		}
+	}
+	expanded.Headers = expandMap(profile.Headers)
+	expanded.Cookies = expandMap(profile.Cookies)
+	return &expanded
+}
+
+func expandMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(values))
+	for key, value := range values {
+		expanded[key] = os.ExpandEnv(value)
+	}
+	return expanded
+}
+
+// Authenticator provides the headers of an authentication profile. OAuth2 tokens are fetched on first use and
+// refreshed once they expire.
+type Authenticator struct {
+	profile *webscan.AuthProfile
+	static  http.Header
+
+	mu     sync.Mutex
+	tokens oauth2.TokenSource
+}
+
+// NewAuthenticator validates the profile and creates its Authenticator. Tokens are fetched with the clients of the
+// factory so that they go through the same proxy and trust the same CA certificates as the scan.
+func NewAuthenticator(ctx context.Context, profile *webscan.AuthProfile, factory *httpclient.Factory) (*Authenticator, error) {
+	a := &Authenticator{profile: profile, static: http.Header{}}
+	for name, value := range profile.Headers {
+		a.static.Set(name, value)
+	}
+
+	switch profile.Type {
+	case webscan.AuthProfileTypeBearer:
+		if stringValue(profile.Token) == "" {
+			return nil, fmt.Errorf("profile %s: BEARER profiles require a token", profile.Name)
+		}
+		a.static.Set("Authorization", "Bearer "+*profile.Token)
+	case webscan.AuthProfileTypeBasic:
+		if stringValue(profile.Username) == "" {
+			return nil, fmt.Errorf("profile %s: BASIC profiles require a username", profile.Name)
+		}
+		credentials := stringValue(profile.Username) + ":" + stringValue(profile.Password)
+		a.static.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	case webscan.AuthProfileTypeCookie:
+		cookies := map[string]string{}
+		if profile.CookieFile != nil {
+			jar, err := loadCookieFile(*profile.CookieFile)
+			if err != nil {
+				return nil, fmt.Errorf("profile %s: %v", profile.Name, err)
+			}
+			cookies = jar
+		}
+		for name, value := range profile.Cookies {
+			cookies[name] = value
+		}
+		if len(cookies) == 0 {
+			return nil, fmt.Errorf("profile %s: COOKIE profiles require cookies or a cookieFile", profile.Name)
+		}
+		a.static.Set("Cookie", cookieHeader(cookies))
+	case webscan.AuthProfileTypeHeader:
+		if len(profile.Headers) == 0 {
+			return nil, fmt.Errorf("profile %s: HEADER profiles require headers", profile.Name)
+		}
+	case webscan.AuthProfileTypeOauth2:
+		tokens, err := tokenSource(ctx, profile, factory)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %v", profile.Name, err)
+		}
+		a.tokens = tokens
+	default:
+		return nil, fmt.Errorf("profile %s: unsupported profile type %q", profile.Name, profile.Type)
+	}
+	return a, nil
+}
+
+// Headers returns the headers that authenticate a request.
+func (a *Authenticator) Headers() (http.Header, error) {
+	headers := a.static.Clone()
+	if a.tokens != nil {
+		// oauth2 token sources are safe for concurrent use, the lock only avoids fetching several tokens at once
+		a.mu.Lock()
+		token, err := a.tokens.Token()
+		a.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch OAuth2 token for profile %s: %v", a.profile.Name, err)
+		}
+		headers.Set("Authorization", token.Type()+" "+token.AccessToken)
+	}
+	return headers, nil
+}
+
+// HeaderNames returns the names of the headers that authenticate a request, without fetching tokens.
+func (a *Authenticator) HeaderNames() []string {
+	names := []string{}
+	for name := range a.static {
+		names = append(names, name)
+	}
+	if a.tokens != nil {
+		names = append(names, "Authorization")
+	}
+	return names
+}
+
+func tokenSource(ctx context.Context, profile *webscan.AuthProfile, factory *httpclient.Factory) (oauth2.TokenSource, error) {
+	if stringValue(profile.TokenUrl) == "" {
+		return nil, fmt.Errorf("OAUTH2 profiles require a tokenUrl")
+	}
+	// The token endpoint is not a scan target, so it is not sent the profile's own credentials
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, factory.Client())
+
+	grantType := webscan.AuthProfileGrantTypeClientCredentials
+	if profile.GrantType != nil {
+		grantType = *profile.GrantType
+	}
+	switch grantType {
+	case webscan.AuthProfileGrantTypeClientCredentials:
+		if stringValue(profile.ClientId) == "" {
+			return nil, fmt.Errorf("the CLIENT_CREDENTIALS grant requires a clientId")
+		}
+		config := clientcredentials.Config{
+			ClientID:     stringValue(profile.ClientId),
+			ClientSecret: stringValue(profile.ClientSecret),
+			TokenURL:     *profile.TokenUrl,
+			Scopes:       profile.Scopes,
+		}
+		if profile.Audience != nil {
+			config.EndpointParams = map[string][]string{"audience": {*profile.Audience}}
+		}
+		return config.TokenSource(ctx), nil
+	case webscan.AuthProfileGrantTypeRefreshToken:
+		if stringValue(profile.RefreshToken) == "" {
+			return nil, fmt.Errorf("the REFRESH_TOKEN grant requires a refreshToken")
+		}
+		config := oauth2.Config{
+			ClientID:     stringValue(profile.ClientId),
+			ClientSecret: stringValue(profile.ClientSecret),
+			Endpoint:     oauth2.Endpoint{TokenURL: *profile.TokenUrl},
+			Scopes:       profile.Scopes,
+		}
+		return config.TokenSource(ctx, &oauth2.Token{RefreshToken: *profile.RefreshToken}), nil
+	}
+	return nil, fmt.Errorf("unsupported grant type %q", grantType)
+}
+
+// loadCookieFile reads the cookies of a Netscape cookie jar file, as exported by browsers and written by curl.
+func loadCookieFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cookie file: %v", err)
+	}
+	defer file.Close()
+
+	cookies := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "#HttpOnly_"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("invalid cookie file line %q, expected 7 tab separated fields", line)
+		}
+		cookies[fields[5]] = fields[6]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read cookie file: %v", err)
+	}
+	return cookies, nil
+}
+
+func cookieHeader(cookies map[string]string) string {
+	names := make([]string, 0, len(cookies))
+	for name := range cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+cookies[name])
+	}
+	return strings.Join(pairs, "; ")
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
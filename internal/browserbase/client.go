@@ -7,10 +7,13 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/Method-Security/webscan/internal/httpclient"
 	"github.com/palantir/pkg/safejson"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 )
 
+const apiKeyHeader = "X-BB-API-Key"
+
 func NewBrowserbaseClient(apiKey string, projectID string, options *Options) *Client {
 	return &Client{
 		APIKey:        apiKey,
@@ -86,10 +89,10 @@ func (b *Client) CloseSession(ctx context.Context, sessionID string) error {
 	}
 
 	request, _ := http.NewRequest("POST", b.URL+"/v1/sessions/"+sessionID, bytes.NewBuffer(payloadBytes))
-	request.Header.Add("X-BB-API-Key", b.APIKey)
+	request.Header.Add(apiKeyHeader, b.APIKey)
 	request.Header.Add("Content-Type", "application/json")
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := b.do(ctx, request)
 	if err != nil {
 		log.Error(fmt.Sprintf("Session request failed: %s", err.Error()))
 		return err
@@ -136,6 +139,15 @@ func (b *Client) CloseAllSessions(ctx context.Context) error {
 	return nil
 }
 
+// do sends a request to the Browserbase API. Browserbase is a third party service rather than a target, so its requests
+// are not checked against the scope nor authenticated with the target's credentials, and the API key is redacted from
+// the HAR archive.
+func (b *Client) do(ctx context.Context, request *http.Request) (*http.Response, error) {
+	factory := httpclient.FromContext(ctx)
+	factory.Recorder().RedactHeaders(apiKeyHeader)
+	return factory.WithScope(nil).Client().Do(request.WithContext(httpclient.WithoutAuthentication(ctx)))
+}
+
 func (b *Client) createSessionRequest() CreateSessionRequest {
 	return CreateSessionRequest{
 		ProjectID: b.ProjectID,
@@ -165,9 +177,9 @@ func (b *Client) createSession(ctx context.Context, createSessionRequest *Create
 
 	request, _ := http.NewRequest("POST", b.URL+"/v1/sessions", bytes.NewBuffer(payloadBytes))
 	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("X-BB-API-Key", b.APIKey)
+	request.Header.Add(apiKeyHeader, b.APIKey)
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := b.do(ctx, request)
 	if err != nil {
 		log.Error(fmt.Sprintf("Session request failed: %s", err.Error()))
 		return nil, err
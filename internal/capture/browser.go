@@ -2,8 +2,12 @@ package capture
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/Method-Security/webscan/internal/httpclient"
+
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/cdp"
 	"github.com/go-rod/rod/lib/launcher"
@@ -48,10 +52,7 @@ func (b *BrowserPageCapturer) Capture(ctx context.Context, url string, options *
 	pageCtx, cancel := context.WithTimeout(ctx, time.Duration(b.TimeoutSeconds)*time.Second)
 	defer cancel()
 
-	var page *rod.Page
-	err := rod.Try(func() {
-		page = b.Browser.MustPage(url).Context(pageCtx)
-	})
+	page, err := OpenPage(pageCtx, b.Browser, url)
 	if err != nil {
 		log.Error("Failed to create page", svc1log.SafeParam("url", url), svc1log.SafeParam("error", err))
 		result.Errors = append(result.Errors, err.Error())
@@ -82,6 +83,77 @@ func (b *BrowserPageCapturer) Capture(ctx context.Context, url string, options *
 	return result, nil
 }
 
+// OpenPage opens a page of the browser at url, bound to ctx. The headers of the authentication profile are set on the
+// page, the requests that are not in the scope are blocked, the others throttled by the limiter and the network events
+// recorded in the HAR recorder before it navigates, so that they apply to every request the page sends. When replaying,
+// every request of the page is answered by the Factory's client instead.
+func OpenPage(ctx context.Context, browser *rod.Browser, url string) (*rod.Page, error) {
+	factory := httpclient.FromContext(ctx)
+	authHeaders, err := factory.AuthHeaders()
+	if err != nil {
+		return nil, err
+	}
+	s := factory.Scope()
+	if err := s.CheckURL(ctx, url); err != nil {
+		return nil, err
+	}
+	limiter := factory.Limiter()
+	recorder := factory.Recorder()
+	var replayClient *http.Client
+	if factory.Replaying() {
+		// The browser follows redirects itself, and the client enforces the scope, limits and recording
+		replayClient = factory.Client(httpclient.WithoutRedirects())
+	}
+
+	var page *rod.Page
+	err = rod.Try(func() {
+		if len(authHeaders) == 0 && s == nil && !limiter.Limited() && recorder == nil && replayClient == nil {
+			page = browser.MustPage(url).Context(ctx)
+			return
+		}
+		page = browser.MustPage().Context(ctx)
+		if len(authHeaders) > 0 {
+			dict := []string{}
+			for name, values := range authHeaders {
+				dict = append(dict, name, strings.Join(values, ", "))
+			}
+			page.MustSetExtraHeaders(dict...)
+		}
+		if replayClient != nil {
+			router := page.HijackRequests()
+			router.MustAdd("*", func(hijack *rod.Hijack) {
+				if err := hijack.LoadResponse(replayClient, true); err != nil {
+					hijack.Response.Fail(proto.NetworkErrorReasonFailed)
+				}
+			})
+			go router.Run()
+		} else if s != nil || limiter.Limited() {
+			// The router stops with the page's context
+			router := page.HijackRequests()
+			router.MustAdd("*", func(hijack *rod.Hijack) {
+				if err := s.Check(ctx, hijack.Request.URL()); err != nil {
+					hijack.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+					return
+				}
+				// The browser does not tell when its requests are done, so only their start is throttled
+				release, err := limiter.Wait(ctx, hijack.Request.URL().Host)
+				if err != nil {
+					hijack.Response.Fail(proto.NetworkErrorReasonAborted)
+					return
+				}
+				release()
+				hijack.ContinueRequest(&proto.FetchContinueRequest{})
+			})
+			go router.Run()
+		}
+		if recorder != nil && replayClient == nil {
+			recordNetwork(ctx, page, recorder)
+		}
+		page.MustNavigate(url)
+	})
+	return page, err
+}
+
 func (b *BrowserPageCapturer) InitializeBrowser() {
 	var browserURL string
 	if b.PathToBrowser != nil && *b.PathToBrowser != "" {
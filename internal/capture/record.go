@@ -0,0 +1,75 @@
+package capture
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// recordNetwork records the requests of the page in the HAR recorder from its network events, until the page's context
+// is done. It must be called before the page navigates.
+func recordNetwork(ctx context.Context, page *rod.Page, recorder *har.Recorder) {
+	pageRecorder := recorder.Page(ctx)
+	wait := page.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			pageRecorder.RequestSent(string(e.RequestID), e.WallTime.Time(), monotonic(e.Timestamp), e.Request.Method,
+				e.Request.URL+e.Request.URLFragment, networkHeaders(e.Request.Headers), []byte(e.Request.PostData),
+				pageResponse(e.RedirectResponse))
+		},
+		func(e *proto.NetworkResponseReceived) {
+			pageRecorder.ResponseReceived(string(e.RequestID), monotonic(e.Timestamp), *pageResponse(e.Response))
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			pageRecorder.Finished(string(e.RequestID), monotonic(e.Timestamp), func() []byte {
+				result, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(page)
+				if err != nil {
+					return nil
+				}
+				if result.Base64Encoded {
+					body, _ := base64.StdEncoding.DecodeString(result.Body)
+					return body
+				}
+				return []byte(result.Body)
+			})
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			pageRecorder.Failed(string(e.RequestID), monotonic(e.Timestamp), e.ErrorText)
+		},
+	)
+	go wait()
+}
+
+func pageResponse(response *proto.NetworkResponse) *har.PageResponse {
+	if response == nil {
+		return nil
+	}
+	return &har.PageResponse{
+		Status:          response.Status,
+		StatusText:      response.StatusText,
+		Protocol:        response.Protocol,
+		Headers:         networkHeaders(response.Headers),
+		RequestHeaders:  networkHeaders(response.RequestHeaders),
+		RemoteIPAddress: response.RemoteIPAddress,
+	}
+}
+
+func networkHeaders(headers proto.NetworkHeaders) http.Header {
+	converted := http.Header{}
+	for name, value := range headers {
+		// Headers with several values are reported joined by newlines
+		for _, v := range strings.Split(value.Str(), "\n") {
+			converted.Add(name, v)
+		}
+	}
+	return converted
+}
+
+func monotonic(t proto.MonotonicTime) time.Time {
+	return time.Unix(0, 0).Add(t.Duration())
+}
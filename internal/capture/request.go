@@ -2,26 +2,24 @@ package capture
 
 import (
 	"context"
-	"crypto/tls"
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type RequestPageCapturer struct {
 	Client http.Client
 }
 
-func NewRequestPageCapturer(insecure bool, timeout int) *RequestPageCapturer {
+func NewRequestPageCapturer(ctx context.Context, insecure bool, timeout int) *RequestPageCapturer {
+	options := []httpclient.ClientOption{httpclient.WithTimeout(time.Duration(timeout) * time.Second)}
+	if insecure {
+		options = append(options, httpclient.WithoutTLSVerification())
+	}
 	return &RequestPageCapturer{
-		Client: http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					InsecureSkipVerify: insecure,
-				},
-			},
-			Timeout: time.Duration(timeout) * time.Second,
-		},
+		Client: *httpclient.FromContext(ctx).Client(options...),
 	}
 }
 
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
-	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 	"github.com/ysmood/gson"
@@ -44,10 +43,7 @@ func (b *BrowserPageCapturer) CaptureScreenshot(ctx context.Context, url string,
 	pageCtx, cancel := context.WithTimeout(ctx, time.Duration(b.TimeoutSeconds)*time.Second)
 	defer cancel()
 
-	var page *rod.Page
-	err = rod.Try(func() {
-		page = b.Browser.MustPage(url).Context(pageCtx)
-	})
+	page, err := OpenPage(pageCtx, b.Browser, url)
 	if err != nil {
 		log.Error("Failed to create page", svc1log.SafeParam("url", url), svc1log.SafeParam("error", err))
 		report.Errors = append(report.Errors, err.Error())
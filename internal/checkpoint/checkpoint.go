@@ -0,0 +1,183 @@
+// Package checkpoint persists the progress of long running scans to a state directory, so that a scan interrupted by a
+// crash or a signal can be resumed where it stopped instead of starting over. Every scan keeps its progress in a
+// Checkpoint, identified by the name of the scan and its parameters, which is saved to a JSON file of the directory as
+// the scan progresses.
+package checkpoint
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// saveInterval is the minimum time between two saves of a checkpoint while its scan runs.
+const saveInterval = 5 * time.Second
+
+// Store is the state directory the checkpoints of scans are saved to. When it resumes scans, the checkpoints of
+// earlier runs of a scan with the same parameters are loaded to continue from them.
+type Store struct {
+	dir    string
+	resume bool
+}
+
+// NewStore returns the Store of the state directory, creating it when it does not exist.
+func NewStore(dir string, resume bool) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create state directory %s: %v", dir, err)
+	}
+	return &Store{dir: dir, resume: resume}, nil
+}
+
+type contextKey struct{}
+
+// WithStore returns a copy of the context carrying the Store the scans checkpoint their progress to.
+func WithStore(ctx context.Context, store *Store) context.Context {
+	return context.WithValue(ctx, contextKey{}, store)
+}
+
+// FromContext returns the Store of the context, or nil when scans are not checkpointed.
+func FromContext(ctx context.Context) *Store {
+	store, _ := ctx.Value(contextKey{}).(*Store)
+	return store
+}
+
+// file is the JSON document a checkpoint is saved as.
+type file struct {
+	Scan      string          `json:"scan"`
+	Params    interface{}     `json:"params"`
+	Completed bool            `json:"completed"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+	State     json.RawMessage `json:"state"`
+}
+
+// Checkpoint holds the progress of a scan, a state the scan updates as it progresses. The state is saved to the state
+// directory of the Store of the scan's context, if any, at most every saveInterval and once the scan finishes.
+type Checkpoint struct {
+	mu        sync.Mutex
+	log       svc1log.Logger
+	path      string
+	scan      string
+	params    interface{}
+	state     interface{}
+	resumed   bool
+	completed bool
+	savedAt   time.Time
+}
+
+// Open returns the checkpoint of the scan with the parameters, whose state is the value state points to. When the
+// Store of the context resumes scans and a checkpoint of the scan was saved before, it is decoded into state. Without
+// a Store, the checkpoint only guards the state and is never saved.
+func Open(ctx context.Context, scan string, params interface{}, state interface{}) (*Checkpoint, error) {
+	c := &Checkpoint{log: svc1log.FromContext(ctx), scan: scan, params: params, state: state}
+	store := FromContext(ctx)
+	if store == nil {
+		return c, nil
+	}
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the parameters of the %s checkpoint: %v", scan, err)
+	}
+	digest := sha256.Sum256(append([]byte(scan+"\n"), encodedParams...))
+	c.path = filepath.Join(store.dir, fmt.Sprintf("%s-%s.json", scan, hex.EncodeToString(digest[:8])))
+	if !store.resume {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		c.log.Info(fmt.Sprintf("No %s checkpoint to resume, starting the scan", scan), svc1log.SafeParam("path", c.path))
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %s: %v", c.path, err)
+	}
+	saved := file{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %s: %v", c.path, err)
+	}
+	if err := json.Unmarshal(saved.State, state); err != nil {
+		return nil, fmt.Errorf("failed to decode the state of checkpoint %s: %v", c.path, err)
+	}
+	c.resumed, c.completed = true, saved.Completed
+	c.log.Info(fmt.Sprintf("Resuming the %s scan from its checkpoint", scan), svc1log.SafeParam("path", c.path))
+	return c, nil
+}
+
+// Resumed reports whether the state was loaded from the checkpoint of an earlier run.
+func (c *Checkpoint) Resumed() bool {
+	return c.resumed
+}
+
+// Completed reports whether the state was loaded from the checkpoint of a run that completed, whose state holds the
+// results of the whole scan.
+func (c *Checkpoint) Completed() bool {
+	return c.completed
+}
+
+// Update calls update, which changes the state, and saves the checkpoint when it was not saved for saveInterval.
+// Updates are serialized, so that update can be called by the concurrent workers of a scan.
+func (c *Checkpoint) Update(update func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	update()
+	if c.path != "" && time.Since(c.savedAt) >= saveInterval {
+		if err := c.save(); err != nil {
+			c.log.Warn("Failed to save checkpoint", svc1log.SafeParam("error", err.Error()))
+		}
+	}
+}
+
+// Finish saves the checkpoint once the scan returns, as completed when the scan scanned everything. The error of an
+// incomplete scan tells how to resume it.
+func (c *Checkpoint) Finish(completed bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" {
+		return nil
+	}
+	c.completed = completed
+	if err := c.save(); err != nil {
+		return err
+	}
+	if !completed {
+		return fmt.Errorf("the %s scan was interrupted, its progress is saved to %s and continues with --resume", c.scan, c.path)
+	}
+	return nil
+}
+
+// save writes the checkpoint to a temporary file that replaces its file, so that a crash never leaves a partial
+// checkpoint behind.
+func (c *Checkpoint) save() error {
+	state, err := json.Marshal(c.state)
+	if err != nil {
+		return fmt.Errorf("failed to encode the state of checkpoint %s: %v", c.path, err)
+	}
+	data, err := json.MarshalIndent(file{
+		Scan:      c.scan,
+		Params:    c.params,
+		Completed: c.completed,
+		UpdatedAt: time.Now().UTC(),
+		State:     state,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %s: %v", c.path, err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %v", c.path, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %v", c.path, err)
+	}
+	c.savedAt = time.Now()
+	return nil
+}
@@ -1,7 +1,26 @@
 // Package config contains common configuration values that are used by the various commands and subcommands in the CLI.
 package config
 
+import "github.com/Method-Security/webscan/internal/httpclient"
+
 type RootFlags struct {
-	Quiet   bool
-	Verbose bool
+	Quiet      bool
+	Verbose    bool
+	HTTPClient httpclient.Options
+	// AuthProfileFile is the file of authentication profiles, AuthProfile the name of the profile requests are
+	// authenticated with.
+	AuthProfileFile string
+	AuthProfile     string
+	// ScopeFile is the scope definition file enforced on every request.
+	ScopeFile string
+	// HAROut is the HAR file the traffic of the command is recorded to.
+	HAROut string
+	// Replay is the HAR file or fixture directory requests are answered from instead of the network.
+	Replay string
+	// StateDir is the directory the progress of scans is checkpointed to, Resume whether interrupted scans continue from
+	// their checkpoints.
+	StateDir string
+	Resume   bool
+	// Store is the results database runs are saved to and queried from.
+	Store string
 }
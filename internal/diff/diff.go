@@ -0,0 +1,240 @@
+// Package diff compares two reports of the same type written by webscan, such as the reports of two nightly runs of a
+// scan, and reports the items that are new, removed or changed in the current report. Items are matched across the
+// reports by stable keys, like the method and path of routes or the ID of vulnerability findings, so that only drift
+// is reported.
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/reportfile"
+	"github.com/Method-Security/webscan/internal/stream"
+)
+
+// Options configures the comparison of two reports.
+type Options struct {
+	// Type is the type of the reports, identified from their content when nil
+	Type *webscan.DiffReportType
+	// ExpiryWindow is the time before their expiry that certificates of the current report are reported as expiring
+	ExpiryWindow time.Duration
+}
+
+// item is an item of a report, matched with the items of the other report by its type, target and key.
+type item struct {
+	itemType webscan.DiffItemType
+	target   string
+	key      string
+	value    interface{}
+	// compared is the part of the value whose changes are reported, the whole value when nil
+	compared interface{}
+}
+
+func (i item) id() string {
+	return string(i.itemType) + "\x00" + i.target + "\x00" + i.key
+}
+
+// Compare compares the reports of the baseline and current report files, which must be of the same type, and returns
+// the changes of the current report. Every change is also emitted as a record of the JSON Lines output.
+func Compare(ctx context.Context, baselinePath string, currentPath string, options Options) (*webscan.DiffReport, error) {
+	baselineContent, err := load(baselinePath)
+	if err != nil {
+		return nil, err
+	}
+	currentContent, err := load(currentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reportType, err := resolveType(options.Type, baselinePath, baselineContent, currentPath, currentContent)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := decode(reportType, baselinePath, baselineContent)
+	if err != nil {
+		return nil, err
+	}
+	current, err := decode(reportType, currentPath, currentContent)
+	if err != nil {
+		return nil, err
+	}
+
+	report := webscan.DiffReport{Type: reportType, Baseline: baselinePath, Current: currentPath, Errors: []string{}}
+	report.Errors = append(report.Errors, mismatches(baseline, current)...)
+	report.Changes = compareItems(items(baseline), items(current))
+	if fingerprint, ok := current.(*webscan.FingerprintReport); ok {
+		report.Changes = append(report.Changes, expiringCertificates(fingerprint, time.Now(), options.ExpiryWindow)...)
+	}
+
+	for _, change := range report.Changes {
+		switch change.Change {
+		case webscan.DiffChangeTypeNew:
+			report.New++
+		case webscan.DiffChangeTypeRemoved:
+			report.Removed++
+		case webscan.DiffChangeTypeChanged:
+			report.Changed++
+		case webscan.DiffChangeTypeExpiring:
+			report.Expiring++
+		}
+		stream.Emit(ctx, stream.TypeChange, change)
+	}
+	return &report, nil
+}
+
+// load reads the JSON encoded report of a report file.
+func load(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report file %s: %v", path, err)
+	}
+	content, err := reportfile.Content(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report file %s: %v", path, err)
+	}
+	return content, nil
+}
+
+// resolveType returns the type of the reports, the configured one or the one identified from the content of the
+// reports. A report whose content fits no type, such as a report without any item, takes the type of the other.
+func resolveType(configured *webscan.DiffReportType, baselinePath string, baseline []byte, currentPath string, current []byte) (webscan.DiffReportType, error) {
+	if configured != nil {
+		return *configured, nil
+	}
+	baselineType, currentType := identify(baseline), identify(current)
+	switch {
+	case baselineType == "" && currentType == "":
+		return "", fmt.Errorf("failed to identify the type of reports %s and %s, set it with --type", baselinePath, currentPath)
+	case baselineType == "":
+		return currentType, nil
+	case currentType == "" || currentType == baselineType:
+		return baselineType, nil
+	}
+	return "", fmt.Errorf("report %s is a %s report and report %s a %s report, only reports of the same type can be compared", baselinePath, baselineType, currentPath, currentType)
+}
+
+// identify returns the type of a report from the fields of its content, or an empty type when they fit no type.
+func identify(content []byte) webscan.DiffReportType {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return ""
+	}
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := fields[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case has("webServers"), has("server", "probe"):
+		return webscan.DiffReportTypeWebServer
+	case has("appType", "baseEndpointUrl"):
+		return webscan.DiffReportTypeRoutes
+	case has("target", "report"):
+		return webscan.DiffReportTypeVuln
+	case has("routes"), has("urls") && json.Unmarshal(fields["urls"], &[]string{}) == nil:
+		return webscan.DiffReportTypeRouteCapture
+	case has("urls"), has("urlsSkippedFromBaseMatch"):
+		return webscan.DiffReportTypeFuzzPath
+	case has("httpHeaders"), has("tlsInfo"), has("redirectUrl"):
+		return webscan.DiffReportTypeFingerprint
+	}
+	return ""
+}
+
+// compareItems returns the changes of the current items from the baseline items: the current items that are new or
+// changed, in the order of the current report, followed by the baseline items that were removed.
+func compareItems(baseline []item, current []item) []*webscan.DiffChange {
+	changes := []*webscan.DiffChange{}
+	baselineItems := map[string]item{}
+	for _, i := range baseline {
+		if _, ok := baselineItems[i.id()]; !ok {
+			baselineItems[i.id()] = i
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, i := range current {
+		if seen[i.id()] {
+			continue
+		}
+		seen[i.id()] = true
+		before, ok := baselineItems[i.id()]
+		if !ok {
+			changes = append(changes, newChange(webscan.DiffChangeTypeNew, i, nil, i.value))
+			continue
+		}
+		if fields, changed := changedFields(before.comparedValue(), i.comparedValue()); changed {
+			change := newChange(webscan.DiffChangeTypeChanged, i, before.value, i.value)
+			change.Fields = fields
+			changes = append(changes, change)
+		}
+	}
+
+	removed := map[string]bool{}
+	for _, i := range baseline {
+		if !seen[i.id()] && !removed[i.id()] {
+			removed[i.id()] = true
+			changes = append(changes, newChange(webscan.DiffChangeTypeRemoved, i, i.value, nil))
+		}
+	}
+	return changes
+}
+
+func (i item) comparedValue() interface{} {
+	if i.compared != nil {
+		return i.compared
+	}
+	return i.value
+}
+
+func newChange(changeType webscan.DiffChangeType, i item, before interface{}, after interface{}) *webscan.DiffChange {
+	change := &webscan.DiffChange{Change: changeType, Item: i.itemType, Key: i.key, Before: before, After: after}
+	if i.target != "" {
+		target := i.target
+		change.Target = &target
+	}
+	return change
+}
+
+// changedFields reports whether two values differ, along with the names of the fields that differ when the values are
+// JSON objects.
+func changedFields(before interface{}, after interface{}) ([]string, bool) {
+	beforeJSON, beforeErr := json.Marshal(before)
+	afterJSON, afterErr := json.Marshal(after)
+	if beforeErr != nil || afterErr != nil {
+		return nil, beforeErr != afterErr
+	}
+	if bytes.Equal(beforeJSON, afterJSON) {
+		return nil, false
+	}
+
+	var beforeFields, afterFields map[string]json.RawMessage
+	if json.Unmarshal(beforeJSON, &beforeFields) != nil || json.Unmarshal(afterJSON, &afterFields) != nil {
+		return nil, true
+	}
+	names := map[string]bool{}
+	for name := range beforeFields {
+		names[name] = true
+	}
+	for name := range afterFields {
+		names[name] = true
+	}
+	fields := []string{}
+	for name := range names {
+		if !bytes.Equal(beforeFields[name], afterFields[name]) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields, true
+}
@@ -0,0 +1,189 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/vuln"
+)
+
+// decode decodes the content of a report file into the report of its type.
+func decode(reportType webscan.DiffReportType, path string, content []byte) (interface{}, error) {
+	var report interface{}
+	switch reportType {
+	case webscan.DiffReportTypeRoutes:
+		report = &webscan.RoutesReport{}
+	case webscan.DiffReportTypeRouteCapture:
+		report = &webscan.RouteCaptureReport{}
+	case webscan.DiffReportTypeWebServer:
+		report = &webscan.WebServerReport{}
+	case webscan.DiffReportTypeFuzzPath:
+		report = &webscan.FuzzPathReport{}
+	case webscan.DiffReportTypeVuln:
+		report = &vuln.VulnerabilityReport{}
+	case webscan.DiffReportTypeFingerprint:
+		report = &webscan.FingerprintReport{}
+	default:
+		return nil, fmt.Errorf("unsupported report type: %s", reportType)
+	}
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, fmt.Errorf("failed to decode report file %s as a %s report: %v", path, reportType, err)
+	}
+	return report, nil
+}
+
+// target returns the target of a report, or an empty string for reports of several targets.
+func target(report interface{}) string {
+	switch r := report.(type) {
+	case *webscan.RoutesReport:
+		return r.Target
+	case *webscan.RouteCaptureReport:
+		return r.Target
+	case *webscan.FuzzPathReport:
+		return r.Target
+	case *vuln.VulnerabilityReport:
+		return r.Target
+	case *webscan.FingerprintReport:
+		return r.Target
+	}
+	return ""
+}
+
+// mismatches returns the differences of the scans the reports were produced by, which make every item of the reports
+// differ.
+func mismatches(baseline interface{}, current interface{}) []string {
+	errs := []string{}
+	if baselineTarget, currentTarget := target(baseline), target(current); baselineTarget != currentTarget {
+		errs = append(errs, fmt.Sprintf("the baseline report is of target %s and the current report of target %s", baselineTarget, currentTarget))
+	}
+	baselineServers, ok := baseline.(*webscan.WebServerReport)
+	if !ok {
+		return errs
+	}
+	currentServers := current.(*webscan.WebServerReport)
+	if baselineServers.Server != currentServers.Server || baselineServers.Probe != currentServers.Probe {
+		errs = append(errs, fmt.Sprintf("the baseline report is of the %s %s probe and the current report of the %s %s probe",
+			baselineServers.Server, baselineServers.Probe, currentServers.Server, currentServers.Probe))
+	}
+	return errs
+}
+
+// items returns the items of a report with their stable keys.
+func items(report interface{}) []item {
+	items := []item{}
+	switch r := report.(type) {
+	case *webscan.RoutesReport:
+		for _, route := range r.Routes {
+			// The case of methods differs between specifications, so the method is only compared through the key
+			compared := fields(route)
+			delete(compared, "method")
+			items = append(items, item{itemType: webscan.DiffItemTypeRoute, target: r.Target, key: strings.ToUpper(route.Method) + " " + route.Path, value: route, compared: compared})
+		}
+	case *webscan.RouteCaptureReport:
+		for _, route := range r.Routes {
+			method, path := "", route.Url
+			if route.Method != nil {
+				method = string(*route.Method)
+			}
+			if route.Path != nil && *route.Path != "" {
+				path = *route.Path
+			}
+			items = append(items, item{itemType: webscan.DiffItemTypeRoute, target: r.Target, key: strings.TrimSpace(method + " " + path), value: route})
+		}
+		for _, u := range r.Urls {
+			items = append(items, item{itemType: webscan.DiffItemTypeUrl, target: r.Target, key: u, value: u})
+		}
+	case *webscan.WebServerReport:
+		// Attempts record the requests and responses of their module, which differ between runs, so only whether the
+		// module found something is compared
+		for _, server := range r.WebServers {
+			for _, attempt := range server.Attempts {
+				if attempt == nil {
+					continue
+				}
+				items = append(items, item{itemType: webscan.DiffItemTypeAttempt, target: server.Target, key: string(attempt.Name), value: attempt,
+					compared: map[string]interface{}{"finding": attempt.Finding}})
+			}
+		}
+	case *webscan.FuzzPathReport:
+		// The size of dynamic pages differs between runs, so only the status of paths is compared
+		for _, details := range r.Urls {
+			items = append(items, item{itemType: webscan.DiffItemTypePath, target: r.Target, key: details.Url, value: details,
+				compared: map[string]interface{}{"status": details.Status}})
+		}
+	case *vuln.VulnerabilityReport:
+		for _, finding := range r.Reports {
+			items = append(items, item{itemType: webscan.DiffItemTypeFinding, target: r.Target, key: finding.ID, value: finding,
+				compared: map[string]interface{}{
+					"severity":          finding.Info.SeverityHolder.Severity.String(),
+					"extracted-results": finding.Context.ExtractedResults,
+				}})
+		}
+	case *webscan.FingerprintReport:
+		items = append(items, fingerprintItems(r.Target, "", r.HttpHeaders, r.TlsInfo)...)
+		if r.RedirectUrl != nil {
+			items = append(items, item{itemType: webscan.DiffItemTypeRedirect, target: r.Target, key: "redirectUrl", value: *r.RedirectUrl})
+		}
+		items = append(items, fingerprintItems(r.Target, "redirect ", r.RedirectHttpHeaders, r.RedirectTlsInfo)...)
+	}
+	return items
+}
+
+// fingerprintItems returns the headers, TLS configuration and certificates of a fingerprint, whose keys are prefixed
+// for the fingerprint of the redirect.
+func fingerprintItems(target string, prefix string, headers *webscan.HttpHeaders, tlsInfo *webscan.TlsInfo) []item {
+	items := []item{}
+	if headers != nil {
+		values := fields(headers)
+		for _, name := range []string{"location", "server", "xPoweredBy", "xFrameOptions", "xClusterName", "crossOriginResourcePolicy",
+			"accessControlAllowOrigin", "xAspNetVersion", "allowedHttpMethods"} {
+			if value, ok := values[name]; ok {
+				items = append(items, item{itemType: webscan.DiffItemTypeHeader, target: target, key: prefix + name, value: value})
+			}
+		}
+	}
+	if tlsInfo == nil {
+		return items
+	}
+
+	items = append(items, item{itemType: webscan.DiffItemTypeTls, target: target, key: prefix + "tls",
+		value: map[string]interface{}{"version": tlsInfo.Version, "cipherSuite": tlsInfo.CipherSuite}})
+	for i, certificate := range tlsInfo.Certificates {
+		// Certificates are matched by their subject, so that a renewed certificate is reported as changed
+		key := fmt.Sprintf("%scertificate #%d", prefix, i)
+		if certificate.SubjectCommonName != nil && *certificate.SubjectCommonName != "" {
+			key = prefix + "certificate " + *certificate.SubjectCommonName
+		}
+		compared := fields(certificate)
+		delete(compared, "certificate")
+		delete(compared, "signature")
+		items = append(items, item{itemType: webscan.DiffItemTypeCertificate, target: target, key: key, value: certificate, compared: compared})
+	}
+	return items
+}
+
+// fields returns the JSON fields of a value.
+func fields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if data, err := json.Marshal(value); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+// expiringCertificates returns the certificates of a fingerprint that expire within the window from now, expired
+// certificates included.
+func expiringCertificates(report *webscan.FingerprintReport, now time.Time, window time.Duration) []*webscan.DiffChange {
+	changes := []*webscan.DiffChange{}
+	for _, i := range items(report) {
+		certificate, ok := i.value.(*webscan.Certificate)
+		if !ok || certificate.ValidTo == nil || certificate.ValidTo.After(now.Add(window)) {
+			continue
+		}
+		changes = append(changes, newChange(webscan.DiffChangeTypeExpiring, i, nil, certificate))
+	}
+	return changes
+}
@@ -2,24 +2,20 @@ package fingerprint
 
 import (
 	"context"
-	"crypto/tls"
 	"net/http"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 // performOptionsRequest performs an OPTIONS request against a target URL and captures the HTTP headers
-func performOptionsRequest(target string) (*webscan.HttpHeaders, error) {
-	req, err := http.NewRequest("OPTIONS", target, nil)
+func performOptionsRequest(ctx context.Context, target string) (*webscan.HttpHeaders, error) {
+	req, err := http.NewRequestWithContext(ctx, "OPTIONS", target, nil)
 	if err != nil {
 		return &webscan.HttpHeaders{}, err
 	}
 
-	client := &http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse // Prevent following redirects
-		},
-	}
+	client := httpclient.FromContext(ctx).Client(httpclient.WithoutRedirects()) // Prevent following redirects
 	resp, err := client.Do(req)
 	if err != nil {
 		return &webscan.HttpHeaders{}, err
@@ -39,14 +35,9 @@ func performOptionsRequest(target string) (*webscan.HttpHeaders, error) {
 }
 
 // PerformTlsInspedction performs a TLS inspection against a target URL and captures the TLS information
-func performTLSInspection(target string) (*webscan.TlsInfo, error) {
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+func performTLSInspection(ctx context.Context, target string) (*webscan.TlsInfo, error) {
+	// The certificate is inspected rather than verified, so that invalid certificates are reported too
+	client := httpclient.FromContext(ctx).Client(httpclient.WithoutTLSVerification())
 
 	resp, err := client.Get(target)
 	if err != nil {
@@ -79,7 +70,7 @@ func PerformFingerprint(ctx context.Context, target string) webscan.FingerprintR
 	}
 
 	// Perform OPTIONS request
-	httpHeaders, err := performOptionsRequest(target)
+	httpHeaders, err := performOptionsRequest(ctx, target)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 	} else {
@@ -87,7 +78,7 @@ func PerformFingerprint(ctx context.Context, target string) webscan.FingerprintR
 	}
 
 	// Perform TLS inspection
-	tlsInfo, err := performTLSInspection(target)
+	tlsInfo, err := performTLSInspection(ctx, target)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 	} else {
@@ -97,7 +88,7 @@ func PerformFingerprint(ctx context.Context, target string) webscan.FingerprintR
 	// Check if there was a redirect and if so follow the redirect and perform another OPTIONS request
 	// And TLS inspection
 	if httpHeaders.Location != nil && httpHeaders.Location != &target {
-		redirectHTTPHeaders, err := performOptionsRequest(*httpHeaders.Location)
+		redirectHTTPHeaders, err := performOptionsRequest(ctx, *httpHeaders.Location)
 		if err != nil {
 			report.Errors = append(report.Errors, err.Error())
 		} else {
@@ -106,7 +97,7 @@ func PerformFingerprint(ctx context.Context, target string) webscan.FingerprintR
 		}
 
 		// Perform TLS inspection
-		redirectTLSInfo, err := performTLSInspection(*httpHeaders.Location)
+		redirectTLSInfo, err := performTLSInspection(ctx, *httpHeaders.Location)
 		if err != nil {
 			report.Errors = append(report.Errors, err.Error())
 		} else {
@@ -2,8 +2,11 @@ package fuzz
 
 import (
 	"bufio"
+	"context"
 	"net/http"
 	"strings"
+
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type HTTPResponseProfile struct {
@@ -13,9 +16,13 @@ type HTTPResponseProfile struct {
 	Size       int
 }
 
-func profileBaseURL(url string) (HTTPResponseProfile, error) {
+func profileBaseURL(ctx context.Context, url string) (HTTPResponseProfile, error) {
 	// Send HTTP request
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return HTTPResponseProfile{}, err
+	}
+	resp, err := httpclient.FromContext(ctx).Client().Do(req)
 	if err != nil {
 		return HTTPResponseProfile{}, err
 	}
@@ -24,6 +24,8 @@ type CustomOutput struct {
 	fuzzkeywords   []string
 	Results        []ffuf.Result
 	CurrentResults []ffuf.Result
+	// OnResult, when set, is called with every result as soon as it is received
+	OnResult func(ffuf.Result)
 }
 
 // NewCustomOutput creates a new CustomOutput instance with the provided ffuf configuration.
@@ -185,19 +187,19 @@ func (s *CustomOutput) Progress(status ffuf.Progress) {
 }
 
 func (s *CustomOutput) Info(infostring string) {
-	fmt.Printf("[INFO] %s\n", infostring)
+	fmt.Fprintf(os.Stderr, "[INFO] %s\n", infostring)
 }
 
 func (s *CustomOutput) Error(errstring string) {
-	fmt.Printf("[ERROR] %s\n", errstring)
+	fmt.Fprintf(os.Stderr, "[ERROR] %s\n", errstring)
 }
 
 func (s *CustomOutput) Warning(warnstring string) {
-	fmt.Printf("[WARNING] %s\n", warnstring)
+	fmt.Fprintf(os.Stderr, "[WARNING] %s\n", warnstring)
 }
 
 func (s *CustomOutput) Raw(output string) {
-	fmt.Printf("%s\n", output)
+	fmt.Fprintf(os.Stderr, "%s\n", output)
 }
 
 func (s *CustomOutput) Finalize() error {
@@ -224,6 +226,9 @@ func (s *CustomOutput) Result(resp ffuf.Response) {
 		Duration:         resp.Time,
 	}
 	s.CurrentResults = append(s.CurrentResults, sResult)
+	if s.OnResult != nil {
+		s.OnResult(sResult)
+	}
 }
 
 func (s *CustomOutput) PrintResult(res ffuf.Result) {
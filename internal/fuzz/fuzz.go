@@ -1,8 +1,19 @@
 package fuzz
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/scope"
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 	"github.com/ffuf/ffuf/v2/pkg/filter"
 	"github.com/ffuf/ffuf/v2/pkg/input"
@@ -10,8 +21,9 @@ import (
 )
 
 // PrepareJob creates a new ffuf job with the provided configuration, leveraging the CustomOutput ffuf type to provide
-// control over the output.
-func PrepareJob(conf *ffuf.Config) (*ffuf.Job, error) {
+// control over the output. The requests of the job are checked against the scope, throttled by the limiter and recorded
+// by the HAR recorder of the HTTP clients.
+func PrepareJob(ctx context.Context, conf *ffuf.Config) (*ffuf.Job, error) {
 	job := ffuf.NewJob(conf)
 
 	var errs ffuf.Multierror
@@ -21,6 +33,23 @@ func PrepareJob(conf *ffuf.Config) (*ffuf.Job, error) {
 	if job.Runner == nil {
 		return nil, fmt.Errorf("error creating runner")
 	}
+	factory := httpclient.FromContext(ctx)
+	limited := &limitedRunner{
+		RunnerProvider: job.Runner,
+		ctx:            ctx,
+		scope:          factory.Scope(),
+		limiter:        factory.Limiter(),
+		recorder:       factory.Recorder(),
+		authHeaders:    factory.AuthHeaders,
+	}
+	if factory.Replaying() {
+		options := []httpclient.ClientOption{httpclient.WithTimeout(time.Duration(conf.Timeout) * time.Second)}
+		if !conf.FollowRedirects {
+			options = append(options, httpclient.WithoutRedirects())
+		}
+		limited.replayClient = factory.Client(options...)
+	}
+	job.Runner = limited
 
 	job.Output = NewCustomOutput(conf)
 	if job.Output == nil {
@@ -30,6 +59,119 @@ func PrepareJob(conf *ffuf.Config) (*ffuf.Job, error) {
 	return job, errs.ErrorOrNil()
 }
 
+// limitedRunner blocks the requests of the ffuf runner that are not in the scope, throttles the others with the
+// limiter of the HTTP clients and records them in their HAR recorder, as ffuf sends its requests with its own client.
+// The authentication headers are read again for every request so that OAuth2 tokens are refreshed during the job.
+type limitedRunner struct {
+	ffuf.RunnerProvider
+	ctx         context.Context
+	scope       *scope.Scope
+	limiter     *httpclient.Limiter
+	recorder    *har.Recorder
+	authHeaders func() (http.Header, error)
+	// replayClient answers the requests from recorded traffic when replaying, applying the scope, limits and
+	// recording itself
+	replayClient *http.Client
+	// onExecuted, when set, is called with the wordlist position and error of every request that was sent
+	onExecuted func(position int, err error)
+}
+
+func (r *limitedRunner) Execute(req *ffuf.Request) (ffuf.Response, error) {
+	resp, err := r.execute(req)
+	// Requests that failed because the job stopped were not sent and are sent again when the job is resumed
+	if r.onExecuted != nil && r.ctx.Err() == nil {
+		r.onExecuted(req.Position, err)
+	}
+	return resp, err
+}
+
+func (r *limitedRunner) execute(req *ffuf.Request) (ffuf.Response, error) {
+	if r.authHeaders != nil {
+		headers, err := r.authHeaders()
+		if err != nil {
+			return ffuf.Response{}, err
+		}
+		for name := range headers {
+			req.Headers[name] = headers.Get(name)
+		}
+	}
+	if r.replayClient != nil {
+		return r.replay(req)
+	}
+	target, err := url.Parse(req.Url)
+	if err != nil {
+		return ffuf.Response{}, fmt.Errorf("invalid URL %s: %v", req.Url, err)
+	}
+	if err := r.scope.Check(r.ctx, target); err != nil {
+		return ffuf.Response{}, err
+	}
+	release, err := r.limiter.Wait(r.ctx, target.Host)
+	if err != nil {
+		return ffuf.Response{}, err
+	}
+	started := time.Now()
+	resp, err := r.RunnerProvider.Execute(req)
+	release()
+	if err == nil {
+		r.limiter.Observe(target.Host, int(resp.StatusCode), http.Header(resp.Headers).Get("Retry-After"))
+	}
+	if r.recorder != nil {
+		r.record(req, resp, started, err)
+	}
+	return resp, err
+}
+
+// replay answers the request with the client replaying recorded traffic, reading the response as ffuf does.
+func (r *limitedRunner) replay(req *ffuf.Request) (ffuf.Response, error) {
+	httpReq, err := http.NewRequestWithContext(r.ctx, req.Method, req.Url, bytes.NewReader(req.Data))
+	if err != nil {
+		return ffuf.Response{}, err
+	}
+	for name, value := range req.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	if req.Host != "" {
+		httpReq.Host = req.Host
+	}
+	started := time.Now()
+	httpResp, err := r.replayClient.Do(httpReq)
+	if err != nil {
+		return ffuf.Response{}, err
+	}
+	defer httpResp.Body.Close()
+
+	resp := ffuf.NewResponse(httpResp, req)
+	resp.Time = time.Since(started)
+	if body, err := io.ReadAll(httpResp.Body); err == nil {
+		resp.Data = body
+		resp.ContentLength = int64(len(body))
+	}
+	resp.ContentWords = int64(len(strings.Split(string(resp.Data), " ")))
+	resp.ContentLines = int64(len(strings.Split(string(resp.Data), "\n")))
+	return resp, nil
+}
+
+func (r *limitedRunner) record(req *ffuf.Request, resp ffuf.Response, started time.Time, err error) {
+	headers := http.Header{}
+	for name, value := range req.Headers {
+		headers.Set(name, value)
+	}
+	if req.Host != "" {
+		headers.Set("Host", req.Host)
+	}
+	entry := har.NewEntry(r.recorder.Module(r.ctx), started, req.Method, req.Url, "HTTP/1.1", headers, req.Data)
+	if err != nil {
+		entry.SetError(err, time.Since(started))
+	} else {
+		body := resp.Data
+		if len(body) > har.MaxBodySize {
+			body = body[:har.MaxBodySize]
+		}
+		entry.SetResponse(int(resp.StatusCode), "HTTP/1.1", http.Header(resp.Headers), body, len(resp.Data), resp.Time, 0)
+	}
+	r.recorder.Add(entry)
+}
+
 // SetupFilters sets up the filters for the ffuf job based on the provided configuration options.
 func SetupFilters(parseOpts *ffuf.ConfigOptions, conf *ffuf.Config) error {
 	errs := ffuf.NewMultierror()
@@ -118,7 +260,7 @@ func SetupFilters(parseOpts *ffuf.ConfigOptions, conf *ffuf.Config) error {
 		}
 	}
 	if conf.IgnoreBody && warningIgnoreBody {
-		fmt.Printf("*** Warning: possible undesired combination of -ignore-body and the response options: fl,fs,fw,ml,ms and mw.\n")
+		fmt.Fprintf(os.Stderr, "*** Warning: possible undesired combination of -ignore-body and the response options: fl,fs,fw,ml,ms and mw.\n")
 	}
 	return errs.ErrorOrNil()
 }
@@ -6,10 +6,15 @@ import (
 	"math"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/stream"
 	"github.com/ffuf/ffuf/v2/pkg/ffuf"
 )
 
-// PerformPathFuzz performs a path fuzzing operation against a target URL, using the provided pathlist and responsecodes
+// PerformPathFuzz performs a path fuzzing operation against a target URL, using the provided pathlist and responsecodes.
+// The wordlist position and results of the fuzz are checkpointed, so that an interrupted fuzz continues after the last
+// position every request was sent up to when it is resumed.
 func PerformPathFuzz(ctx context.Context, target string, pathlist string, ignorebase bool, responsecodes string, maxtime int) webscan.FuzzPathReport {
 	report := webscan.FuzzPathReport{
 		Target:                   target,
@@ -18,6 +23,24 @@ func PerformPathFuzz(ctx context.Context, target string, pathlist string, ignore
 		Errors:                   []string{},
 	}
 
+	state := pathState{Urls: []*webscan.UrlDetails{}, Skipped: []*webscan.UrlDetails{}}
+	params := pathParams{
+		Target:         target,
+		Pathlist:       pathlist,
+		PathlistDigest: fileDigest(pathlist),
+		ResponseCodes:  responsecodes,
+		IgnoreBase:     ignorebase,
+	}
+	progress, err := checkpoint.Open(ctx, "fuzz-path", params, &state)
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	if progress.Completed() {
+		report.Urls, report.UrlsSkippedFromBaseMatch = state.Urls, state.Skipped
+		return report
+	}
+
 	// 1. Modify context
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -52,6 +75,18 @@ func PerformPathFuzz(ctx context.Context, target string, pathlist string, ignore
 		},
 	}
 
+	// Requests share the proxy, headers, authentication and client certificate of the HTTP clients
+	factory := httpclient.FromContext(ctx)
+	opts.HTTP.ProxyURL = factory.ProxyURL()
+	opts.HTTP.ClientCert = factory.Options().ClientCertFile
+	opts.HTTP.ClientKey = factory.Options().ClientKeyFile
+	headers, err := factory.HeaderLines()
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return report
+	}
+	opts.HTTP.Headers = append(opts.HTTP.Headers, headers...)
+
 	// 3. Create ffuf config
 	conf, err := ffuf.ConfigFromOptions(&opts, ctx, cancel)
 	if err != nil {
@@ -67,7 +102,7 @@ func PerformPathFuzz(ctx context.Context, target string, pathlist string, ignore
 	}
 
 	// 5. Prepare Job
-	job, err := PrepareJob(conf)
+	job, err := PrepareJob(ctx, conf)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 		return report
@@ -79,44 +114,65 @@ func PerformPathFuzz(ctx context.Context, target string, pathlist string, ignore
 		return report
 	}
 
-	// 6. Start the job
-	job.Start()
-
-	// 7. Get the results
-	customOutput, ok := job.Output.(*CustomOutput)
-	if !ok {
-		report.Errors = append(report.Errors, "custom output provider errored")
-		return report
-	}
-
-	// 8. Profile the base URL if ignorebase is true
+	// 6. Profile the base URL if ignorebase is true
 	baseProfile := HTTPResponseProfile{}
 	if ignorebase {
-		baseProfile, err = profileBaseURL(target)
+		baseProfile, err = profileBaseURL(ctx, target)
 		if err != nil {
 			report.Errors = append(report.Errors, err.Error())
 			return report
 		}
 	}
 
-	for _, result := range customOutput.CurrentResults {
-		if ignorebase && baseProfile.StatusCode == 200 {
-			// ffuz seems to report an extra byte and line for every response, so we need to check accordingly
-			if (result.ContentLength == int64(baseProfile.Size) || math.Abs(float64(result.ContentLength-int64(baseProfile.Size))) <= 1) && (result.ContentLines == int64(baseProfile.Lines) || math.Abs(float64(result.ContentLines-int64(baseProfile.Lines))) <= 1) {
-				report.UrlsSkippedFromBaseMatch = append(report.UrlsSkippedFromBaseMatch, &webscan.UrlDetails{
-					Url:    result.Url,
-					Status: fmt.Sprintf("%d", result.StatusCode),
-					Size:   int(result.ContentLength),
-				})
-				continue // Skip this result because it matches the base HTTP profile, likely a redirect
-			}
+	customOutput, ok := job.Output.(*CustomOutput)
+	if !ok {
+		report.Errors = append(report.Errors, "custom output provider errored")
+		return report
+	}
+	tracker := newPositionTracker(progress, &state)
+	customOutput.OnResult = func(result ffuf.Result) {
+		// Results that match the base HTTP profile are skipped, as they likely are redirects
+		skipped := matchesBaseProfile(result, ignorebase, baseProfile)
+		if tracker.addResult(urlDetails(result), skipped) && !skipped {
+			stream.Emit(ctx, stream.TypePath, urlDetails(result))
 		}
-		report.Urls = append(report.Urls, &webscan.UrlDetails{
-			Url:    result.Url,
-			Status: fmt.Sprintf("%d", result.StatusCode),
-			Size:   int(result.ContentLength),
-		})
+	}
+	if limited, ok := job.Runner.(*limitedRunner); ok {
+		limited.onExecuted = tracker.onExecuted
+	}
+
+	// 7. Start the job, from the position of a resumed fuzz
+	total := job.Input.Total()
+	if state.Position < total {
+		job.Input = &resumedInput{InputProvider: job.Input, position: state.Position}
+		job.Start()
+	}
+
+	// 8. Get the results, along with those of the earlier runs of a resumed fuzz
+	progress.Update(func() {
+		report.Urls = append(report.Urls, state.Urls...)
+		report.UrlsSkippedFromBaseMatch = append(report.UrlsSkippedFromBaseMatch, state.Skipped...)
+	})
+	if err := progress.Finish(state.Position >= total); err != nil {
+		report.Errors = append(report.Errors, err.Error())
 	}
 
 	return report
 }
+
+// matchesBaseProfile reports whether the result looks like the response of the base URL when ignorebase is true.
+func matchesBaseProfile(result ffuf.Result, ignorebase bool, baseProfile HTTPResponseProfile) bool {
+	if !ignorebase || baseProfile.StatusCode != 200 {
+		return false
+	}
+	// ffuz seems to report an extra byte and line for every response, so we need to check accordingly
+	return (result.ContentLength == int64(baseProfile.Size) || math.Abs(float64(result.ContentLength-int64(baseProfile.Size))) <= 1) && (result.ContentLines == int64(baseProfile.Lines) || math.Abs(float64(result.ContentLines-int64(baseProfile.Lines))) <= 1)
+}
+
+func urlDetails(result ffuf.Result) *webscan.UrlDetails {
+	return &webscan.UrlDetails{
+		Url:    result.Url,
+		Status: fmt.Sprintf("%d", result.StatusCode),
+		Size:   int(result.ContentLength),
+	}
+}
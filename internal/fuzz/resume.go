@@ -0,0 +1,112 @@
+package fuzz
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/ffuf/ffuf/v2/pkg/ffuf"
+)
+
+// pathParams identify the checkpoint of a path fuzz. The pathlist is identified by its content, as wordlist positions
+// are meaningless once it changes.
+type pathParams struct {
+	Target         string `json:"target"`
+	Pathlist       string `json:"pathlist"`
+	PathlistDigest string `json:"pathlistDigest"`
+	ResponseCodes  string `json:"responseCodes"`
+	IgnoreBase     bool   `json:"ignoreBase"`
+}
+
+// pathState is the checkpointed progress of a path fuzz: the wordlist position up to which every request was sent,
+// and the results found so far.
+type pathState struct {
+	Position int                   `json:"position"`
+	Urls     []*webscan.UrlDetails `json:"urls"`
+	Skipped  []*webscan.UrlDetails `json:"skipped"`
+}
+
+// fileDigest returns the SHA-256 digest of the content of a file, or an empty string when it cannot be read.
+func fileDigest(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+// positionTracker advances the position of a path state as the requests of the job are sent, which ffuf does
+// concurrently and out of order.
+type positionTracker struct {
+	checkpoint *checkpoint.Checkpoint
+	state      *pathState
+	// executed are the positions above the position of the state whose requests were sent
+	executed map[int]bool
+	// failed are the positions whose request failed once, which ffuf sends once more
+	failed map[int]bool
+}
+
+func newPositionTracker(c *checkpoint.Checkpoint, state *pathState) *positionTracker {
+	return &positionTracker{checkpoint: c, state: state, executed: map[int]bool{}, failed: map[int]bool{}}
+}
+
+// onExecuted records the request of a position as sent, unless it failed for the first time.
+func (t *positionTracker) onExecuted(position int, err error) {
+	t.checkpoint.Update(func() {
+		if err != nil && !t.failed[position] {
+			t.failed[position] = true
+			return
+		}
+		delete(t.failed, position)
+		t.executed[position] = true
+		for t.executed[t.state.Position+1] {
+			delete(t.executed, t.state.Position+1)
+			t.state.Position++
+		}
+	})
+}
+
+// addResult adds a result of the job to the path state, unless a request of an earlier run found its URL.
+func (t *positionTracker) addResult(details *webscan.UrlDetails, skipped bool) bool {
+	added := false
+	t.checkpoint.Update(func() {
+		for _, found := range append(t.state.Urls, t.state.Skipped...) {
+			if found.Url == details.Url {
+				return
+			}
+		}
+		if skipped {
+			t.state.Skipped = append(t.state.Skipped, details)
+		} else {
+			t.state.Urls = append(t.state.Urls, details)
+		}
+		added = true
+	})
+	return added
+}
+
+// resumedInput continues the wordlist of a job after the position of a resumed path fuzz.
+type resumedInput struct {
+	ffuf.InputProvider
+	position int
+	started  bool
+}
+
+// Reset resets the wordlist to the position it is resumed from the first time, which ffuf does when the job starts.
+func (i *resumedInput) Reset() {
+	i.InputProvider.Reset()
+	if !i.started && i.position > 0 {
+		// SetPosition makes the position it is given the next one of the wordlist
+		i.InputProvider.SetPosition(i.position + 1)
+	}
+	i.started = true
+}
+
+// Total returns the number of positions after the one the wordlist is resumed from, which ffuf counts the progress
+// of the job to.
+func (i *resumedInput) Total() int {
+	return i.InputProvider.Total() - i.position
+}
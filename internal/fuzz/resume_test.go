@@ -0,0 +1,142 @@
+package fuzz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPathFuzzResume interrupts a path fuzz, resumes it and checks that every wordlist entry is requested: those up to
+// the checkpointed position by the interrupted run, and the others exactly once by the resumed run.
+func TestPathFuzzResume(t *testing.T) {
+	const words = 300
+	dir := t.TempDir()
+	pathlist := filepath.Join(dir, "pathlist.txt")
+	lines := make([]string, words)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("word%03d", i)
+	}
+	require.NoError(t, os.WriteFile(pathlist, []byte(strings.Join(lines, "\n")+"\n"), 0600))
+
+	var mu sync.Mutex
+	var requested map[string]int
+	var onRequest func(count int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		word := strings.TrimPrefix(r.URL.Path, "/")
+		mu.Lock()
+		requested[word]++
+		count := 0
+		for _, n := range requested {
+			count += n
+		}
+		notify := onRequest
+		mu.Unlock()
+		if notify != nil {
+			notify(count)
+		}
+		if strings.HasSuffix(word, "0") {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// The first run is interrupted once a fifth of the wordlist was requested
+	stateDir := filepath.Join(dir, "state")
+	store, err := checkpoint.NewStore(stateDir, false)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(checkpoint.WithStore(context.Background(), store))
+	defer cancel()
+	requested, onRequest = map[string]int{}, func(count int) {
+		if count >= words/5 {
+			cancel()
+		}
+	}
+	report := runPathFuzz(t, ctx, server.URL, pathlist)
+	require.NotEmpty(t, report.Errors)
+	assert.Contains(t, report.Errors[len(report.Errors)-1], "continues with --resume")
+	mu.Lock()
+	first := requested
+	mu.Unlock()
+
+	position := savedPosition(t, stateDir)
+	require.Greater(t, position, 0)
+	require.Less(t, position, words)
+	for _, word := range lines[:position] {
+		assert.Equal(t, 1, first[word], "%s was not requested once before the checkpointed position", word)
+	}
+
+	// The resumed run continues after the checkpointed position
+	store, err = checkpoint.NewStore(stateDir, true)
+	require.NoError(t, err)
+	mu.Lock()
+	requested, onRequest = map[string]int{}, nil
+	mu.Unlock()
+	report = runPathFuzz(t, checkpoint.WithStore(context.Background(), store), server.URL, pathlist)
+	assert.Empty(t, report.Errors)
+	mu.Lock()
+	second := requested
+	mu.Unlock()
+
+	for i, word := range lines {
+		if i < position {
+			assert.Zero(t, second[word], "%s was requested again by the resumed run", word)
+		} else {
+			assert.Equal(t, 1, second[word], "%s was not requested once by the resumed run", word)
+		}
+	}
+	assert.Len(t, second, words-position)
+
+	// Every match is reported once, whichever run found it
+	urls := map[string]bool{}
+	for _, details := range report.Urls {
+		assert.False(t, urls[details.Url], "%s was reported twice", details.Url)
+		urls[details.Url] = true
+	}
+	assert.Len(t, urls, words/10)
+}
+
+// runPathFuzz runs a path fuzz matching 200 responses. ffuf waits for the request count to reach the total of the
+// wordlist, so a resume that skips positions never returns: the test fails instead of waiting for it.
+func runPathFuzz(t *testing.T, ctx context.Context, target string, pathlist string) webscan.FuzzPathReport {
+	t.Helper()
+	done := make(chan webscan.FuzzPathReport, 1)
+	go func() {
+		done <- PerformPathFuzz(ctx, target, pathlist, false, "200", 0)
+	}()
+	select {
+	case report := <-done:
+		return report
+	case <-time.After(time.Minute):
+		t.Fatal("the path fuzz did not finish, the total of its wordlist does not match the positions it sent")
+		return webscan.FuzzPathReport{}
+	}
+}
+
+func savedPosition(t *testing.T, stateDir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(stateDir, "fuzz-path-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	saved := struct {
+		State pathState `json:"state"`
+	}{}
+	require.NoError(t, json.Unmarshal(data, &saved))
+	return saved.State.Position
+}
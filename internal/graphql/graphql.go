@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 // PerformGraphQLScan performs a GraphQL scan against a target URL and returns the report.
@@ -23,7 +24,7 @@ func PerformGraphQLScan(ctx context.Context, target string) webscan.RoutesReport
 
 	addTopLevelRoute(&report, basePath)
 
-	body, err := fetchGraphQLSchema(target)
+	body, err := fetchGraphQLSchema(ctx, target)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 		return report
@@ -67,9 +68,14 @@ func addTopLevelRoute(report *webscan.RoutesReport, basePath string) {
 	report.Routes = append(report.Routes, &baseRoute)
 }
 
-func fetchGraphQLSchema(target string) ([]byte, error) {
+func fetchGraphQLSchema(ctx context.Context, target string) ([]byte, error) {
 	query := `{"query":"{ __schema { types { name kind description fields { name } } } }"}`
-	resp, err := http.Post(target, "application/json", bytes.NewBuffer([]byte(query)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewBuffer([]byte(query)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL schema request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpclient.FromContext(ctx).Client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch GraphQL schema: %v", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
@@ -19,6 +20,12 @@ import (
 func PerformGRPCScan(ctx context.Context, target string) webscan.RoutesReport {
 	report := webscan.RoutesReport{Target: target, BaseEndpointUrl: target, AppType: webscan.ApiTypeGrpc}
 
+	// gRPC reflection is not HTTP traffic that can be recorded, so it cannot be replayed
+	if httpclient.FromContext(ctx).Replaying() {
+		report.Errors = append(report.Errors, "gRPC reflection cannot replay recorded traffic")
+		return report
+	}
+
 	conn, err := connectToGRPCServer(target)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
@@ -0,0 +1,274 @@
+// Package har records the HTTP traffic of a command as a HAR 1.2 archive, so that every finding can be backed by the
+// raw requests and responses it is based on. The Recorder is carried by the HTTP client Factory: the clients record
+// every request they send, the browsers record their network events and the third party engines record the traffic
+// they report. Every entry names the module that sent the request in its _module field. The Replayer answers the
+// requests of the clients from recorded archives, so that modules can run again without the targets.
+package har
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Version is the version of the HAR format written by the Recorder.
+const Version = "1.2"
+
+// MaxBodySize is the size above which the bodies of requests and responses are truncated in the archive.
+const MaxBodySize = 1 << 20
+
+// Archive is the root object of a HAR file.
+type Archive struct {
+	Log Log `json:"log"`
+}
+
+// Log lists the entries of an Archive.
+type Log struct {
+	Version string   `json:"version"`
+	Creator Creator  `json:"creator"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Creator is the application that created an Archive.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is a request and its response. Requests that failed have a response with status 0 and the error in _error.
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"`
+	Request         Request   `json:"request"`
+	Response        Response  `json:"response"`
+	Cache           struct{}  `json:"cache"`
+	Timings         Timings   `json:"timings"`
+	ServerIPAddress string    `json:"serverIPAddress,omitempty"`
+	Comment         string    `json:"comment,omitempty"`
+	Module          string    `json:"_module"`
+	Error           string    `json:"_error,omitempty"`
+}
+
+// Request is the request of an Entry.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// Response is the response of an Entry.
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// NameValue is a header or query string parameter.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Cookie is a cookie sent with a request or set by a response.
+type Cookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// PostData is the body of a request.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"_encoding,omitempty"`
+}
+
+// Content is the body of a response. Binary bodies are base64 encoded.
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// Timings are the durations of the phases of a request in milliseconds, -1 for the phases that are not known.
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// NewEntry creates the Entry of a request sent by the module at started. Its response is set with SetResponse, or
+// SetError when the request failed.
+func NewEntry(module string, started time.Time, method string, rawURL string, httpVersion string, headers http.Header, body []byte) *Entry {
+	entry := &Entry{
+		StartedDateTime: started,
+		Module:          module,
+		Timings:         Timings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1},
+		Request: Request{
+			Method:      method,
+			URL:         rawURL,
+			HTTPVersion: httpVersion,
+			Cookies:     requestCookies(headers),
+			Headers:     nameValues(headers),
+			QueryString: []NameValue{},
+			HeadersSize: -1,
+			BodySize:    len(body),
+		},
+		Response: Response{Cookies: []Cookie{}, Headers: []NameValue{}, HeadersSize: -1, BodySize: -1},
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		for name, values := range u.Query() {
+			for _, value := range values {
+				entry.Request.QueryString = append(entry.Request.QueryString, NameValue{Name: name, Value: value})
+			}
+		}
+		sortNameValues(entry.Request.QueryString)
+	}
+	if len(body) > 0 {
+		if len(body) > MaxBodySize {
+			body = body[:MaxBodySize]
+			entry.Comment = fmt.Sprintf("request body truncated to %d of %d bytes", MaxBodySize, entry.Request.BodySize)
+		}
+		text, encoding := encodeBody(body)
+		entry.Request.PostData = &PostData{MimeType: headers.Get("Content-Type"), Text: text, Encoding: encoding}
+	}
+	return entry
+}
+
+// SetRequestHeaders replaces the headers of the request, for clients that only know the headers they send once the
+// request was sent.
+func (e *Entry) SetRequestHeaders(headers http.Header) {
+	e.Request.Headers = nameValues(headers)
+	e.Request.Cookies = requestCookies(headers)
+}
+
+// SetResponse sets the response of the Entry. size is the size of the whole body, which may be larger than the
+// recorded body when it was truncated. wait is the time until the response headers arrived and receive the time it
+// took to read the body.
+func (e *Entry) SetResponse(status int, httpVersion string, headers http.Header, body []byte, size int, wait time.Duration, receive time.Duration) {
+	e.Response = Response{
+		Status:      status,
+		StatusText:  http.StatusText(status),
+		HTTPVersion: httpVersion,
+		Cookies:     responseCookies(headers),
+		Headers:     nameValues(headers),
+		Content:     Content{Size: size, MimeType: headers.Get("Content-Type")},
+		RedirectURL: headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    size,
+	}
+	if len(body) > 0 {
+		e.Response.Content.Text, e.Response.Content.Encoding = encodeBody(body)
+		if len(body) < size {
+			e.Response.Content.Comment = fmt.Sprintf("truncated to %d bytes", len(body))
+		}
+	}
+	e.Timings.Send = 0
+	e.Timings.Wait = milliseconds(wait)
+	e.Timings.Receive = milliseconds(receive)
+	e.Time = milliseconds(wait + receive)
+}
+
+// SetError records the error of a request that did not receive a response.
+func (e *Entry) SetError(err error, elapsed time.Duration) {
+	e.Error = err.Error()
+	e.Response.HTTPVersion = e.Request.HTTPVersion
+	e.Timings.Wait = milliseconds(elapsed)
+	e.Time = milliseconds(elapsed)
+}
+
+// moduleKey is the context key of the module name.
+type moduleKey struct{}
+
+// WithModule returns a copy of ctx whose requests are recorded as sent by the module.
+func WithModule(ctx context.Context, module string) context.Context {
+	return context.WithValue(ctx, moduleKey{}, module)
+}
+
+// Module returns the name of the module of ctx, or an empty string when ctx has none.
+func Module(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	module, _ := ctx.Value(moduleKey{}).(string)
+	return module
+}
+
+// encodeBody returns the body as text and its encoding, base64 when it is not valid UTF-8.
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func nameValues(headers http.Header) []NameValue {
+	values := []NameValue{}
+	for name, headerValues := range headers {
+		for _, value := range headerValues {
+			values = append(values, NameValue{Name: name, Value: value})
+		}
+	}
+	sortNameValues(values)
+	return values
+}
+
+func sortNameValues(values []NameValue) {
+	sort.SliceStable(values, func(i, j int) bool {
+		return strings.ToLower(values[i].Name) < strings.ToLower(values[j].Name)
+	})
+}
+
+func requestCookies(headers http.Header) []Cookie {
+	cookies := []Cookie{}
+	for _, cookie := range (&http.Request{Header: headers}).Cookies() {
+		cookies = append(cookies, Cookie{Name: cookie.Name, Value: cookie.Value})
+	}
+	return cookies
+}
+
+func responseCookies(headers http.Header) []Cookie {
+	cookies := []Cookie{}
+	for _, cookie := range (&http.Response{Header: headers}).Cookies() {
+		cookies = append(cookies, Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			HTTPOnly: cookie.HttpOnly,
+			Secure:   cookie.Secure,
+		})
+	}
+	return cookies
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,128 @@
+package har
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PageResponse is a response reported by the network events of a browser.
+type PageResponse struct {
+	Status     int
+	StatusText string
+	// Protocol is the protocol reported by the browser, such as http/1.1 or h2.
+	Protocol string
+	Headers  http.Header
+	// RequestHeaders are the headers the browser actually sent, including those it adds itself such as cookies, when
+	// the browser reports them.
+	RequestHeaders  http.Header
+	RemoteIPAddress string
+}
+
+// PageRecorder records the requests of a browser page from its network events, which report a request, its response
+// and the end of its body separately. The times of the events are those of the browser's monotonic clock.
+type PageRecorder struct {
+	recorder *Recorder
+	module   string
+
+	mu      sync.Mutex
+	pending map[string]*pendingRequest
+}
+
+// pendingRequest is a request of a page whose response has not been fully received yet.
+type pendingRequest struct {
+	entry    *Entry
+	sent     time.Time
+	response *PageResponse
+	received time.Time
+}
+
+// Page returns the PageRecorder of a page whose requests are sent with ctx, or nil when nothing is recorded.
+func (r *Recorder) Page(ctx context.Context) *PageRecorder {
+	if r == nil {
+		return nil
+	}
+	return &PageRecorder{recorder: r, module: r.Module(ctx), pending: map[string]*pendingRequest{}}
+}
+
+// RequestSent records that the page sent a request. Redirects are reported as new requests with the ID of the
+// request they redirect, along with the response that caused them.
+func (p *PageRecorder) RequestSent(id string, started time.Time, at time.Time, method string, url string, headers http.Header, body []byte, redirect *PageResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if previous, ok := p.pending[id]; ok && redirect != nil {
+		previous.response, previous.received = redirect, at
+		p.recorder.Add(previous.complete(nil, at))
+	}
+	p.pending[id] = &pendingRequest{entry: NewEntry(p.module, started, method, url, "", headers, body), sent: at}
+}
+
+// ResponseReceived records the response headers of a request.
+func (p *PageRecorder) ResponseReceived(id string, at time.Time, response PageResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if request, ok := p.pending[id]; ok {
+		request.response, request.received = &response, at
+	}
+}
+
+// Finished records that the body of a request was received. body is only called for requests that were recorded.
+func (p *PageRecorder) Finished(id string, at time.Time, body func() []byte) {
+	p.mu.Lock()
+	request, ok := p.pending[id]
+	delete(p.pending, id)
+	p.mu.Unlock()
+	if ok {
+		p.recorder.Add(request.complete(body(), at))
+	}
+}
+
+// Failed records that a request failed.
+func (p *PageRecorder) Failed(id string, at time.Time, errorText string) {
+	p.mu.Lock()
+	request, ok := p.pending[id]
+	delete(p.pending, id)
+	p.mu.Unlock()
+	if ok {
+		request.entry.SetError(errors.New(errorText), at.Sub(request.sent))
+		p.recorder.Add(request.entry)
+	}
+}
+
+// complete sets the response of the request's entry, whose body was received until finished.
+func (r *pendingRequest) complete(body []byte, finished time.Time) *Entry {
+	entry := r.entry
+	if r.response == nil {
+		entry.SetError(errors.New("the browser did not report the response"), finished.Sub(r.sent))
+		return entry
+	}
+	response := r.response
+	if len(response.RequestHeaders) > 0 {
+		entry.SetRequestHeaders(response.RequestHeaders)
+	}
+	entry.Request.HTTPVersion = httpVersion(response.Protocol)
+	size := len(body)
+	if size > MaxBodySize {
+		body = body[:MaxBodySize]
+	}
+	entry.SetResponse(response.Status, entry.Request.HTTPVersion, response.Headers, body, size, r.received.Sub(r.sent), finished.Sub(r.received))
+	if response.StatusText != "" {
+		entry.Response.StatusText = response.StatusText
+	}
+	entry.ServerIPAddress = response.RemoteIPAddress
+	return entry
+}
+
+// httpVersion returns the HAR HTTP version of the protocol reported by a browser.
+func httpVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "h2":
+		return "HTTP/2.0"
+	case "h3":
+		return "HTTP/3.0"
+	}
+	return strings.ToUpper(protocol)
+}
@@ -0,0 +1,205 @@
+package har
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces the values of the credential headers and cookies in written archives.
+const Redacted = "REDACTED"
+
+// credentialHeaders are the headers whose values are redacted from every written archive.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Recorder collects the entries of an Archive. A nil Recorder records nothing, so that callers do not have to check
+// whether traffic is recorded.
+type Recorder struct {
+	creator       Creator
+	defaultModule string
+
+	mu       sync.Mutex
+	entries  []*Entry
+	redacted map[string]bool
+}
+
+// NewRecorder creates a Recorder for the version of webscan. Requests whose context does not name a module are
+// recorded as sent by the default module, usually the command.
+func NewRecorder(version string, defaultModule string) *Recorder {
+	r := &Recorder{creator: Creator{Name: "webscan", Version: version}, defaultModule: defaultModule, redacted: map[string]bool{}}
+	r.RedactHeaders(credentialHeaders...)
+	return r
+}
+
+// RedactHeaders adds headers whose values are redacted from the written archive, such as the headers of an
+// authentication profile. The Authorization, Proxy-Authorization, Cookie and Set-Cookie headers are always redacted.
+func (r *Recorder) RedactHeaders(names ...string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, name := range names {
+		r.redacted[http.CanonicalHeaderKey(name)] = true
+	}
+}
+
+// Module returns the module the requests sent with ctx are recorded as.
+func (r *Recorder) Module(ctx context.Context) string {
+	if r == nil {
+		return ""
+	}
+	if module := Module(ctx); module != "" {
+		return module
+	}
+	return r.defaultModule
+}
+
+// Add records the entry.
+func (r *Recorder) Add(entry *Entry) {
+	if r == nil || entry == nil {
+		return
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+}
+
+// AddRaw records a request and its response from their raw HTTP/1.x dumps, as reported by third party engines.
+// target is the URL of the request, as the dump only holds its path. Responses that cannot be parsed are recorded as
+// errors, and nothing is recorded when the request cannot be parsed.
+func (r *Recorder) AddRaw(ctx context.Context, started time.Time, elapsed time.Duration, target string, rawRequest string, rawResponse string) {
+	if r == nil || rawRequest == "" {
+		return
+	}
+	req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(normalizeLineEndings(rawRequest))))
+	if err != nil {
+		return
+	}
+	body, _ := io.ReadAll(req.Body)
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		target = u.ResolveReference(&url.URL{Path: req.URL.Path, RawPath: req.URL.RawPath, RawQuery: req.URL.RawQuery}).String()
+	}
+	headers := req.Header.Clone()
+	if req.Host != "" && headers.Get("Host") == "" {
+		headers.Set("Host", req.Host)
+	}
+	entry := NewEntry(r.Module(ctx), started, req.Method, target, req.Proto, headers, body)
+
+	if rawResponse == "" {
+		entry.SetError(fmt.Errorf("the engine did not report the response"), elapsed)
+		r.Add(entry)
+		return
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(strings.NewReader(normalizeLineEndings(rawResponse))), req)
+	if err != nil {
+		entry.SetError(fmt.Errorf("failed to parse the response reported by the engine: %v", err), elapsed)
+		r.Add(entry)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	size := len(respBody)
+	if size > MaxBodySize {
+		respBody = respBody[:MaxBodySize]
+	}
+	entry.SetResponse(resp.StatusCode, resp.Proto, resp.Header, respBody, size, elapsed, 0)
+	r.Add(entry)
+}
+
+// Len returns the number of recorded entries.
+func (r *Recorder) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Archive returns the Archive of the recorded entries, sorted by the time their request was sent.
+func (r *Recorder) Archive() Archive {
+	r.mu.Lock()
+	entries := append([]*Entry{}, r.entries...)
+	r.mu.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return Archive{Log: Log{Version: Version, Creator: r.creator, Entries: entries}}
+}
+
+// Write writes the Archive of the recorded entries to the file at path, readable by its owner only. The values of the
+// credential headers and of the cookies are redacted, but the bodies of requests and responses are written as they
+// were sent, so the archive may still hold secrets such as the credentials of a login form.
+func (r *Recorder) Write(path string) error {
+	if r == nil {
+		return nil
+	}
+	archive := r.Archive()
+	r.mu.Lock()
+	for i, entry := range archive.Log.Entries {
+		archive.Log.Entries[i] = r.redact(entry)
+	}
+	r.mu.Unlock()
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode HAR archive: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write HAR file: %v", err)
+	}
+	return nil
+}
+
+// redact returns a copy of the entry whose redacted headers and cookies have their values replaced. The recorded entry
+// is left as is.
+func (r *Recorder) redact(entry *Entry) *Entry {
+	redacted := *entry
+	redacted.Request.Headers = r.redactHeaders(entry.Request.Headers)
+	redacted.Request.Cookies = redactCookies(entry.Request.Cookies)
+	redacted.Response.Headers = r.redactHeaders(entry.Response.Headers)
+	redacted.Response.Cookies = redactCookies(entry.Response.Cookies)
+	return &redacted
+}
+
+func (r *Recorder) redactHeaders(headers []NameValue) []NameValue {
+	redacted := make([]NameValue, len(headers))
+	for i, header := range headers {
+		redacted[i] = header
+		if r.redacted[http.CanonicalHeaderKey(header.Name)] {
+			redacted[i].Value = Redacted
+		}
+	}
+	return redacted
+}
+
+func redactCookies(cookies []Cookie) []Cookie {
+	redacted := make([]Cookie, len(cookies))
+	for i, cookie := range cookies {
+		redacted[i] = cookie
+		redacted[i].Value = Redacted
+	}
+	return redacted
+}
+
+// normalizeLineEndings terminates the header lines of a raw HTTP dump with CRLF, as some engines report them with LF
+// only. The body is left as is.
+func normalizeLineEndings(raw string) string {
+	end, separator := len(raw), ""
+	for _, candidate := range []string{"\r\n\r\n", "\n\n"} {
+		if i := strings.Index(raw, candidate); i >= 0 && i < end {
+			end, separator = i, candidate
+		}
+	}
+	head := strings.ReplaceAll(strings.ReplaceAll(raw[:end], "\r\n", "\n"), "\n", "\r\n")
+	return head + "\r\n\r\n" + raw[end+len(separator):]
+}
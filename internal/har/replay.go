@@ -0,0 +1,199 @@
+package har
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Replayer is an http.RoundTripper answering requests with the responses of recorded archives instead of sending
+// them. Requests are matched by method and URL, and by body when several recorded requests share them. Requests that
+// were recorded several times are answered with their recorded responses in order, the last one being repeated.
+type Replayer struct {
+	mu      sync.Mutex
+	entries map[string][]*Entry
+	served  map[*Entry]bool
+}
+
+// LoadReplayer loads the archive at path, or every .har file of the fixture directory at path in the order of their
+// names.
+func LoadReplayer(path string) (*Replayer, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay archive %s: %v", path, err)
+	}
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.har"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replay fixtures in %s: %v", path, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("replay fixture directory %s has no .har files", path)
+		}
+		sort.Strings(files)
+	}
+
+	r := &Replayer{entries: map[string][]*Entry{}, served: map[*Entry]bool{}}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replay archive %s: %v", file, err)
+		}
+		archive := Archive{}
+		if err := json.Unmarshal(data, &archive); err != nil {
+			return nil, fmt.Errorf("failed to decode replay archive %s: %v", file, err)
+		}
+		for _, entry := range archive.Log.Entries {
+			if entry == nil {
+				continue
+			}
+			key, err := replayKey(entry.Request.Method, entry.Request.URL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid entry in replay archive %s: %v", file, err)
+			}
+			r.entries[key] = append(r.entries[key], entry)
+		}
+	}
+	return r, nil
+}
+
+// RoundTrip answers the request with its recorded response, or returns an error when it was not recorded.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	key, err := replayKey(req.Method, req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+	entry := r.next(key, body)
+	if entry == nil {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL)
+	}
+	if entry.Error != "" {
+		return nil, fmt.Errorf("replayed error: %s", entry.Error)
+	}
+	if entry.Response.Status == 0 {
+		return nil, errors.New("replayed request has no recorded response")
+	}
+	return replayedResponse(req, entry)
+}
+
+// next returns the entry answering the request with the key and body: the first entry with the same body that was
+// not served yet, or else the first entry that was not served yet, or else the last entry.
+func (r *Replayer) next(key string, body []byte) *Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	candidates := r.entries[key]
+	if len(candidates) == 0 {
+		return nil
+	}
+	var unserved *Entry
+	for _, candidate := range candidates {
+		if r.served[candidate] {
+			continue
+		}
+		if bytes.Equal(requestBody(candidate), body) {
+			r.served[candidate] = true
+			return candidate
+		}
+		if unserved == nil {
+			unserved = candidate
+		}
+	}
+	if unserved == nil {
+		return candidates[len(candidates)-1]
+	}
+	r.served[unserved] = true
+	return unserved
+}
+
+func replayedResponse(req *http.Request, entry *Entry) (*http.Response, error) {
+	content := entry.Response.Content
+	body := []byte(content.Text)
+	if content.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(content.Text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 body of the recorded response to %s %s: %v", req.Method, req.URL, err)
+		}
+		body = decoded
+	}
+
+	headers := http.Header{}
+	for _, header := range entry.Response.Headers {
+		// The body is replayed decoded and whole, so the headers describing its transfer no longer apply
+		switch http.CanonicalHeaderKey(header.Name) {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		headers.Add(header.Name, header.Value)
+	}
+	proto := entry.Response.HTTPVersion
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		proto, major, minor = "HTTP/1.1", 1, 1
+	}
+	status := fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText)
+	return &http.Response{
+		Status:        strings.TrimSpace(status),
+		StatusCode:    entry.Response.Status,
+		Proto:         proto,
+		ProtoMajor:    major,
+		ProtoMinor:    minor,
+		Header:        headers,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func requestBody(entry *Entry) []byte {
+	if entry.Request.PostData == nil {
+		return nil
+	}
+	if entry.Request.PostData.Encoding == "base64" {
+		decoded, _ := base64.StdEncoding.DecodeString(entry.Request.PostData.Text)
+		return decoded
+	}
+	return []byte(entry.Request.PostData.Text)
+}
+
+// replayKey returns the key matching the requests with the method and URL, ignoring the case of the scheme and host,
+// default ports, fragments and the order of query parameters.
+func replayKey(method string, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %v", rawURL, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	query := u.Query().Encode()
+	if query == "" && u.RawQuery != "" {
+		query = u.RawQuery
+	}
+	return strings.ToUpper(method) + " " + scheme + "://" + host + path + "?" + query, nil
+}
@@ -0,0 +1,62 @@
+package htmlreport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/reportfile"
+	"github.com/Method-Security/webscan/internal/vuln"
+)
+
+// Decode decodes the contents of a report file written by webscan in any of its output formats. Reports are not
+// tagged with the command that produced them, so the report type is recognized from the fields that only it has.
+// Reports of other types are returned as generic JSON objects, for which only the raw report is rendered.
+func Decode(data []byte) (interface{}, error) {
+	content, err := reportfile.Content(data)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, fmt.Errorf("report is not a JSON object: %v", err)
+	}
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, ok := fields[name]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	var report interface{}
+	switch {
+	case has("webServers"), has("server", "probe"):
+		report = &webscan.WebServerReport{}
+	case has("appType", "baseEndpointUrl"):
+		report = &webscan.RoutesReport{}
+	case has("routesTested"):
+		report = &webscan.AuthzReport{}
+	case has("results", "baseUrl"):
+		report = &webscan.RequestBatchReport{}
+	case has("method", "path", "baseUrl", "statusCode"):
+		report = &webscan.RequestReport{}
+	case has("urls"), has("urlsSkippedFromBaseMatch"):
+		report = &webscan.FuzzPathReport{}
+	case has("screenshot"):
+		report = &webscan.PageScreenshotReport{}
+	case has("target", "report"):
+		report = &vuln.VulnerabilityReport{}
+	default:
+		generic := map[string]interface{}{}
+		if err := json.Unmarshal(content, &generic); err != nil {
+			return nil, err
+		}
+		return generic, nil
+	}
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", kindOf(report), err)
+	}
+	return report, nil
+}
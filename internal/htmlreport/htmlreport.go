@@ -0,0 +1,381 @@
+// Package htmlreport renders webscan reports as a single self-contained HTML file that can be handed to application
+// owners. The page has no external dependencies: findings are listed in sortable tables, request and response
+// evidence is shown next to the web server attempts it belongs to, screenshots are embedded as data URIs and route
+// inventories are listed with their parameters and security requirements.
+package htmlreport
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/sarif"
+)
+
+// maxBodyLength is the length after which response bodies are truncated in the evidence.
+const maxBodyLength = 16 * 1024
+
+//go:embed report.html.tmpl
+var pageTemplate string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(pageTemplate))
+
+// Metadata describes the command that produced the report. Zero values are left out of the page.
+type Metadata struct {
+	Title        string
+	Command      string
+	Version      string
+	StartedAt    time.Time
+	CompletedAt  time.Time
+	Status       int
+	ErrorMessage *string
+}
+
+type page struct {
+	Metadata
+	Kind        string
+	Target      string
+	Findings    []findingRow
+	WebServers  []webServerView
+	Requests    []evidenceView
+	Routes      *routesView
+	Screenshots []screenshotView
+	Errors      []string
+	Raw         string
+}
+
+type findingRow struct {
+	Rule     string
+	Name     string
+	Level    string
+	Message  string
+	Location string
+}
+
+type webServerView struct {
+	Target   string
+	Attempts []attemptView
+}
+
+type attemptView struct {
+	Module    string
+	Finding   bool
+	Timestamp string
+	Evidence  []evidenceView
+}
+
+// evidenceView is a request along with the response it received.
+type evidenceView struct {
+	Title           string
+	Method          string
+	URL             string
+	RequestHeaders  []keyValue
+	Params          []keyValue
+	StatusCode      int
+	ResponseHeaders []keyValue
+	Body            string
+	Error           string
+}
+
+type keyValue struct {
+	Key   string
+	Value string
+}
+
+type routesView struct {
+	BaseURL string
+	AppType string
+	Version string
+	Routes  []routeRow
+}
+
+type routeRow struct {
+	Method      string
+	Path        string
+	Type        string
+	Security    string
+	QueryParams string
+	Description string
+}
+
+type screenshotView struct {
+	Target  string
+	DataURI template.URL
+}
+
+// Render writes the HTML page of the report content to w. content is the content of an output signal, or a report
+// decoded with Decode.
+func Render(w io.Writer, content interface{}, metadata Metadata) error {
+	p := page{Metadata: metadata, Kind: kindOf(content)}
+	if p.Title == "" {
+		p.Title = "webscan report"
+	}
+
+	// Findings are shared with the SARIF output so that both formats report the same results
+	if log, err := sarif.Convert(content, metadata.Version, metadata.Status, nil); err == nil {
+		p.Findings = findingRows(log)
+	}
+
+	switch report := content.(type) {
+	case webscan.WebServerReport:
+		p.addWebServerReport(&report)
+	case *webscan.WebServerReport:
+		p.addWebServerReport(report)
+	case webscan.RoutesReport:
+		p.addRoutesReport(&report)
+	case *webscan.RoutesReport:
+		p.addRoutesReport(report)
+	case webscan.PageScreenshotReport:
+		p.addScreenshotReport(&report)
+	case *webscan.PageScreenshotReport:
+		p.addScreenshotReport(report)
+	case webscan.RequestReport:
+		p.addRequestReport(&report)
+	case *webscan.RequestReport:
+		p.addRequestReport(report)
+	case webscan.RequestBatchReport:
+		p.addRequestBatchReport(&report)
+	case *webscan.RequestBatchReport:
+		p.addRequestBatchReport(report)
+	}
+
+	if content != nil {
+		raw, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %v", err)
+		}
+		p.Raw = string(raw)
+		p.Target, p.Errors = targetAndErrors(raw)
+	}
+
+	return reportTemplate.Execute(w, p)
+}
+
+func findingRows(log *sarif.Log) []findingRow {
+	rows := []findingRow{}
+	for _, run := range log.Runs {
+		for _, result := range run.Results {
+			row := findingRow{Rule: result.RuleID, Level: result.Level, Message: result.Message.Text}
+			if result.RuleIndex < len(run.Tool.Driver.Rules) {
+				row.Name = run.Tool.Driver.Rules[result.RuleIndex].Name
+			}
+			if len(result.Locations) > 0 {
+				row.Location = result.Locations[0].PhysicalLocation.ArtifactLocation.URI
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func (p *page) addWebServerReport(report *webscan.WebServerReport) {
+	for _, server := range report.WebServers {
+		if server == nil {
+			continue
+		}
+		view := webServerView{Target: server.Target}
+		for _, attempt := range server.Attempts {
+			if attempt == nil {
+				continue
+			}
+			view.Attempts = append(view.Attempts, attemptView{
+				Module:    string(attempt.Name),
+				Finding:   attempt.Finding,
+				Timestamp: attempt.Timestamp.Format(time.RFC3339),
+				Evidence:  attemptEvidence(attempt.AttemptInfo),
+			})
+		}
+		p.WebServers = append(p.WebServers, view)
+	}
+}
+
+func attemptEvidence(info *webscan.AttemptInfoUnion) []evidenceView {
+	if info == nil {
+		return nil
+	}
+	switch {
+	case info.GeneralAttempt != nil:
+		return []evidenceView{generalEvidence("", info.GeneralAttempt.Request, info.GeneralAttempt.Response)}
+	case info.VersionAttempt != nil:
+		evidence := generalEvidence("", info.VersionAttempt.Request, nil)
+		if response := info.VersionAttempt.Response; response != nil {
+			evidence.StatusCode = response.StatusCode
+			lines := []string{}
+			if response.Header != nil {
+				lines = append(lines, "Header: "+*response.Header)
+			}
+			if response.VersionType != nil {
+				lines = append(lines, "Version type: "+*response.VersionType)
+			}
+			if response.VersionNumber != nil {
+				lines = append(lines, "Version: "+*response.VersionNumber)
+			}
+			evidence.Body = strings.Join(lines, "\n")
+			if response.Error != nil {
+				evidence.Error = *response.Error
+			}
+		}
+		return []evidenceView{evidence}
+	case info.MultiplePathsAttempt != nil:
+		evidence := []evidenceView{}
+		for _, path := range info.MultiplePathsAttempt.Paths {
+			if path != nil {
+				evidence = append(evidence, generalEvidence(path.Path, path.Request, path.Response))
+			}
+		}
+		return evidence
+	}
+	return nil
+}
+
+func generalEvidence(title string, request *webscan.GeneralRequestInfo, response *webscan.GeneralResponseInfo) evidenceView {
+	evidence := evidenceView{Title: title}
+	if request != nil {
+		evidence.Method = string(request.Method)
+		evidence.URL = request.Url
+		evidence.RequestHeaders = sortedPairs(request.Headers)
+		evidence.Params = sortedPairs(request.Params)
+	}
+	if response != nil {
+		evidence.StatusCode = response.StatusCode
+		evidence.ResponseHeaders = sortedPairs(response.Headers)
+		if response.Body != nil {
+			evidence.Body = truncate(*response.Body)
+		}
+		if response.Error != nil {
+			evidence.Error = *response.Error
+		}
+	}
+	return evidence
+}
+
+func (p *page) addRequestReport(report *webscan.RequestReport) {
+	requestURL := strings.TrimSuffix(report.BaseUrl, "/") + "/" + strings.TrimPrefix(report.Path, "/")
+	params := []keyValue{}
+	for _, group := range []struct {
+		location string
+		values   map[string]string
+	}{
+		{"path", report.PathParams},
+		{"query", report.QueryParams},
+		{"form", report.FormParams},
+		{"multipart", report.MultipartParams},
+	} {
+		for _, pair := range sortedPairs(group.values) {
+			params = append(params, keyValue{Key: group.location + " " + pair.Key, Value: pair.Value})
+		}
+	}
+	if report.BodyParams != nil {
+		params = append(params, keyValue{Key: "body", Value: *report.BodyParams})
+	}
+	p.Requests = append(p.Requests, evidenceView{
+		Title:           string(report.Method) + " " + report.Path,
+		Method:          string(report.Method),
+		URL:             requestURL,
+		RequestHeaders:  sortedPairs(report.HeaderParams),
+		Params:          params,
+		StatusCode:      report.StatusCode,
+		ResponseHeaders: sortedPairs(report.ResponseHeaders),
+		Body:            truncate(report.ResponseBody),
+	})
+}
+
+func (p *page) addRequestBatchReport(report *webscan.RequestBatchReport) {
+	for _, result := range report.Results {
+		if result != nil && result.Report != nil {
+			p.addRequestReport(result.Report)
+		}
+	}
+}
+
+func (p *page) addRoutesReport(report *webscan.RoutesReport) {
+	view := &routesView{BaseURL: report.BaseEndpointUrl, AppType: string(report.AppType)}
+	if report.Version != nil {
+		view.Version = *report.Version
+	}
+	for _, route := range report.Routes {
+		if route == nil {
+			continue
+		}
+		row := routeRow{
+			Method:      route.Method,
+			Path:        route.Path,
+			Type:        string(route.Type),
+			QueryParams: strings.Join(route.QueryParams, ", "),
+			Description: route.Description,
+		}
+		if route.Security != nil {
+			security, _ := json.Marshal(route.Security)
+			row.Security = string(security)
+		}
+		view.Routes = append(view.Routes, row)
+	}
+	p.Routes = view
+}
+
+func (p *page) addScreenshotReport(report *webscan.PageScreenshotReport) {
+	if report.Screenshot == nil || len(*report.Screenshot) == 0 {
+		return
+	}
+	contentType := http.DetectContentType(*report.Screenshot)
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = "image/png"
+	}
+	p.Screenshots = append(p.Screenshots, screenshotView{
+		Target:  report.Target,
+		DataURI: template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(*report.Screenshot)),
+	})
+}
+
+// targetAndErrors reads the target and the non-fatal errors shared by most reports from their JSON encoding.
+func targetAndErrors(raw []byte) (string, []string) {
+	var common struct {
+		Target interface{} `json:"target"`
+		Errors []string    `json:"errors"`
+	}
+	_ = json.Unmarshal(raw, &common)
+	target, _ := common.Target.(string)
+	return target, common.Errors
+}
+
+// kindOf names the report for the page heading, e.g. "WebServerReport".
+func kindOf(content interface{}) string {
+	if content == nil {
+		return ""
+	}
+	kind := fmt.Sprintf("%T", content)
+	kind = strings.TrimPrefix(kind, "*")
+	if index := strings.LastIndex(kind, "."); index >= 0 {
+		kind = kind[index+1:]
+	}
+	if kind == "map[string]interface {}" {
+		return "Report"
+	}
+	return kind
+}
+
+func sortedPairs(values map[string]string) []keyValue {
+	pairs := make([]keyValue, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, keyValue{Key: key, Value: value})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs
+}
+
+func truncate(body string) string {
+	if len(body) <= maxBodyLength {
+		return body
+	}
+	return body[:maxBodyLength] + fmt.Sprintf("\n... (%d bytes truncated)", len(body)-maxBodyLength)
+}
@@ -0,0 +1,403 @@
+// Package httpclient provides the HTTP clients used by every webscan command. The clients share the configuration set
+// by the root command's flags: the proxy (HTTP or SOCKS5), trusted CA certificates, the client certificate used for
+// mTLS, TLS verification, the headers and user agent sent with every request, retries, the default timeout and the rate
+// limits. The clients also authenticate requests with the selected authentication profile, block requests outside of
+// the scope and record requests in the HAR recorder, or answer them from a recorded archive when replaying.
+//
+// The Factory is carried through the command's context. Scanners call FromContext to create their clients, and the
+// configuration of third party engines such as ffuf, katana, httpx and nuclei is derived from the Factory's Options.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/scope"
+)
+
+// Options configure the HTTP clients of a command.
+type Options struct {
+	// Proxy is the URL of the proxy requests are sent through, with the http, https or socks5 scheme.
+	Proxy string
+	// CACertFile is a PEM file of CA certificates trusted in addition to the system roots.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the PEM files of the client certificate presented for mTLS.
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables the verification of the certificates of targets. Certificates are verified by
+	// default, only scanners that inspect certificates or probe web servers skip the verification of their requests.
+	InsecureSkipVerify bool
+	// Headers are added to every request that does not set them itself, as "Name: value".
+	Headers []string
+	// UserAgent replaces the user agent of every request that does not set one itself.
+	UserAgent string
+	// Retries is the number of times a request is retried after a network error or a 429, 502, 503 or 504 response.
+	Retries int
+	// Timeout is the timeout of requests for scanners without a timeout of their own. Zero disables the timeout.
+	Timeout time.Duration
+	// RateLimit is the maximum number of requests per second across all hosts. Zero disables the limit.
+	RateLimit float64
+	// MaxConcurrencyPerHost is the maximum number of concurrent requests to a host. Zero disables the limit.
+	MaxConcurrencyPerHost int
+	// Delay is the minimum time between the start of consecutive requests to a host, and Jitter the maximum random
+	// time added to it.
+	Delay  time.Duration
+	Jitter time.Duration
+}
+
+// Authenticator provides the credentials of the authentication profile requests are sent with.
+type Authenticator interface {
+	// Headers returns the headers that authenticate a request, fetching or refreshing tokens when needed.
+	Headers() (http.Header, error)
+}
+
+// Factory creates HTTP clients sharing the configuration of its Options.
+type Factory struct {
+	options       Options
+	proxy         *url.URL
+	tlsConfig     *tls.Config
+	transport     *http.Transport
+	insecure      *http.Transport
+	headers       http.Header
+	authenticator Authenticator
+	scope         *scope.Scope
+	limiter       *Limiter
+	recorder      *har.Recorder
+	replayer      *har.Replayer
+	module        string
+}
+
+// NewFactory validates the options, loading the certificate files and parsing the proxy URL and headers.
+func NewFactory(options Options) (*Factory, error) {
+	factory := &Factory{options: options, headers: http.Header{}}
+
+	if options.Proxy != "" {
+		proxy, err := url.Parse(options.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %v", options.Proxy, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q, expected http, https or socks5", proxy.Scheme)
+		}
+		factory.proxy = proxy
+	}
+
+	factory.tlsConfig = &tls.Config{InsecureSkipVerify: options.InsecureSkipVerify}
+	if options.CACertFile != "" {
+		pem, err := os.ReadFile(options.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificates: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", options.CACertFile)
+		}
+		factory.tlsConfig.RootCAs = pool
+	}
+	if options.ClientCertFile != "" || options.ClientKeyFile != "" {
+		if options.ClientCertFile == "" || options.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both a client certificate and a client key are required for mTLS")
+		}
+		certificate, err := tls.LoadX509KeyPair(options.ClientCertFile, options.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		factory.tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	for _, header := range options.Headers {
+		name, value, found := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", header)
+		}
+		factory.headers.Add(name, strings.TrimSpace(value))
+	}
+	if options.UserAgent != "" {
+		factory.headers.Set("User-Agent", options.UserAgent)
+	}
+
+	factory.transport = factory.newTransport(factory.TLSConfig())
+	factory.insecure = factory.transport
+	if !factory.tlsConfig.InsecureSkipVerify {
+		insecure := factory.TLSConfig()
+		insecure.InsecureSkipVerify = true
+		factory.insecure = factory.newTransport(insecure)
+	}
+
+	if options.RateLimit < 0 || options.MaxConcurrencyPerHost < 0 || options.Delay < 0 || options.Jitter < 0 {
+		return nil, fmt.Errorf("the rate limit, concurrency, delay and jitter must not be negative")
+	}
+	factory.limiter = newLimiter(options)
+	return factory, nil
+}
+
+// WithAuthenticator returns a copy of the Factory whose clients authenticate their requests with the Authenticator.
+func (f *Factory) WithAuthenticator(authenticator Authenticator) *Factory {
+	factory := *f
+	factory.authenticator = authenticator
+	return &factory
+}
+
+// WithScope returns a copy of the Factory whose clients block the requests that are not in the scope.
+func (f *Factory) WithScope(s *scope.Scope) *Factory {
+	factory := *f
+	factory.scope = s
+	return &factory
+}
+
+// Scope returns the scope enforced by the clients of the Factory, or nil when every request is allowed.
+func (f *Factory) Scope() *scope.Scope {
+	return f.scope
+}
+
+// EngineProxy returns the proxy URL of the third party engines that do not use the Factory's clients, and a function
+// to call once the engine is done. When a scope is enforced, it is the URL of a local proxy blocking the requests that
+// are not in the scope before sending the others through the configured proxy. Engines cannot run when recorded
+// traffic is replayed.
+func (f *Factory) EngineProxy(ctx context.Context) (string, func(), error) {
+	if f.replayer != nil {
+		return "", nil, fmt.Errorf("third party engines send their own requests and cannot replay recorded traffic")
+	}
+	if f.scope == nil {
+		return f.ProxyURL(), func() {}, nil
+	}
+	proxy, err := scope.NewProxy(ctx, f.scope, f.proxy, f.Transport())
+	if err != nil {
+		return "", nil, err
+	}
+	return proxy.URL(), func() {
+		_ = proxy.Close()
+	}, nil
+}
+
+// WithRecorder returns a copy of the Factory whose clients record their requests in the HAR recorder.
+func (f *Factory) WithRecorder(recorder *har.Recorder) *Factory {
+	factory := *f
+	factory.recorder = recorder
+	return &factory
+}
+
+// Recorder returns the HAR recorder of the Factory, or nil when requests are not recorded.
+func (f *Factory) Recorder() *har.Recorder {
+	return f.recorder
+}
+
+// WithReplayer returns a copy of the Factory whose clients answer requests with the responses recorded in the
+// Replayer instead of sending them.
+func (f *Factory) WithReplayer(replayer *har.Replayer) *Factory {
+	factory := *f
+	factory.replayer = replayer
+	return &factory
+}
+
+// Replaying reports whether the clients of the Factory replay recorded responses instead of sending requests.
+func (f *Factory) Replaying() bool {
+	return f.replayer != nil
+}
+
+// Limiter returns the Limiter shared by the clients of the Factory.
+func (f *Factory) Limiter() *Limiter {
+	return f.limiter
+}
+
+// Authenticator returns the Authenticator of the Factory, or nil when requests are not authenticated.
+func (f *Factory) Authenticator() Authenticator {
+	return f.authenticator
+}
+
+// AuthHeaders returns the current headers of the Authenticator, or no headers when requests are not authenticated.
+func (f *Factory) AuthHeaders() (http.Header, error) {
+	if f.authenticator == nil {
+		return http.Header{}, nil
+	}
+	return f.authenticator.Headers()
+}
+
+// HeaderLines returns every header the Factory adds to requests as "Name: value" lines, including the current
+// authentication headers. It is used to configure third party engines that do not use the Factory's clients. The lines
+// are a snapshot: engines that only read them when they start keep sending OAuth2 tokens after they expire.
+func (f *Factory) HeaderLines() ([]string, error) {
+	headers := f.Headers()
+	authHeaders, err := f.AuthHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range authHeaders {
+		headers[name] = values
+	}
+	lines := make([]string, 0, len(headers))
+	for name, values := range headers {
+		for _, value := range values {
+			lines = append(lines, name+": "+value)
+		}
+	}
+	sort.Strings(lines)
+	return lines, nil
+}
+
+// Options returns the options of the Factory.
+func (f *Factory) Options() Options {
+	return f.options
+}
+
+// ProxyURL returns the proxy requests are sent through, or an empty string when there is none.
+func (f *Factory) ProxyURL() string {
+	if f.proxy == nil {
+		return ""
+	}
+	return f.proxy.String()
+}
+
+// Headers returns the headers added to every request, including the user agent when one is configured.
+func (f *Factory) Headers() http.Header {
+	return f.headers.Clone()
+}
+
+// TLSConfig returns a copy of the TLS configuration of the clients.
+func (f *Factory) TLSConfig() *tls.Config {
+	return f.tlsConfig.Clone()
+}
+
+// clientConfig holds the per client settings applied by ClientOptions.
+type clientConfig struct {
+	timeout         time.Duration
+	followRedirects bool
+	skipVerify      bool
+}
+
+// ClientOption customizes a single client created by the Factory.
+type ClientOption func(*clientConfig)
+
+// WithTimeout overrides the default timeout of the client, for scanners that have a timeout flag of their own.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(config *clientConfig) {
+		config.timeout = timeout
+	}
+}
+
+// WithoutRedirects makes the client return redirect responses instead of following them.
+func WithoutRedirects() ClientOption {
+	return func(config *clientConfig) {
+		config.followRedirects = false
+	}
+}
+
+// WithoutTLSVerification makes the client skip TLS verification regardless of the options, for scanners that inspect
+// the certificates of targets, probe web servers for vulnerabilities or were asked to ignore certificate errors.
+func WithoutTLSVerification() ClientOption {
+	return func(config *clientConfig) {
+		config.skipVerify = true
+	}
+}
+
+// newTransport creates a transport using the proxy of the Factory and the TLS configuration.
+func (f *Factory) newTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if f.proxy != nil {
+		transport.Proxy = http.ProxyURL(f.proxy)
+	}
+	return transport
+}
+
+// Transport returns the transport using the proxy and TLS configuration of the Factory. It is shared by the clients of
+// the Factory and its copies, so that they reuse their connections, and must not be modified.
+func (f *Factory) Transport() *http.Transport {
+	return f.transport
+}
+
+// Client creates an HTTP client. Its requests carry the default headers of the Factory and are retried as configured.
+// Clients are cheap to create: they send their requests through the transports shared by the clients of the Factory.
+func (f *Factory) Client(options ...ClientOption) *http.Client {
+	config := clientConfig{timeout: f.options.Timeout, followRedirects: true}
+	for _, option := range options {
+		option(&config)
+	}
+
+	var next http.RoundTripper = f.transport
+	if config.skipVerify {
+		next = f.insecure
+	}
+	if f.replayer != nil {
+		next = f.replayer
+	}
+	client := &http.Client{
+		Timeout: config.timeout,
+		Transport: &roundTripper{
+			next:          next,
+			headers:       f.headers,
+			authenticator: f.authenticator,
+			scope:         f.scope,
+			limiter:       f.limiter,
+			recorder:      f.recorder,
+			module:        f.module,
+			retries:       f.options.Retries,
+		},
+	}
+	if !config.followRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	return client
+}
+
+type factoryKey struct{}
+
+type withoutAuthenticationKey struct{}
+
+// WithoutAuthentication returns a copy of the context whose requests are not authenticated by the Authenticator of the
+// Factory, for scanners that manage the credentials of their requests themselves.
+func WithoutAuthentication(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutAuthenticationKey{}, true)
+}
+
+func authenticationDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(withoutAuthenticationKey{}).(bool)
+	return disabled
+}
+
+// WithModule returns a copy of the context whose requests are recorded in the HAR recorder as sent by the module. It
+// also applies to the requests of clients created from the context's Factory, even when they are sent without it.
+func WithModule(ctx context.Context, module string) context.Context {
+	factory := *FromContext(ctx)
+	factory.module = module
+	return har.WithModule(WithFactory(ctx, &factory), module)
+}
+
+// WithFactory returns a copy of the context carrying the Factory.
+func WithFactory(ctx context.Context, factory *Factory) context.Context {
+	return context.WithValue(ctx, factoryKey{}, factory)
+}
+
+// defaultFactory is the Factory with the default options, shared by the contexts that carry none.
+var defaultFactory = sync.OnceValue(func() *Factory {
+	factory, _ := NewFactory(Options{})
+	return factory
+})
+
+// FromContext returns the Factory carried by the context, or a Factory with the default options when there is none.
+func FromContext(ctx context.Context) *Factory {
+	if ctx != nil {
+		if factory, ok := ctx.Value(factoryKey{}).(*Factory); ok && factory != nil {
+			return factory
+		}
+	}
+	return defaultFactory()
+}
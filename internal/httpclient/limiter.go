@@ -0,0 +1,246 @@
+package httpclient
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minBackoff is the first pause of a host after a 429 or 503 response without a Retry-After header.
+	minBackoff = time.Second
+	// maxBackoff caps the pause of a host, including the pauses requested with Retry-After headers.
+	maxBackoff = 30 * time.Second
+)
+
+// Limiter throttles the requests of every scanner. A token bucket limits the rate of requests across all hosts, and
+// for every host the number of concurrent requests is limited and consecutive requests are spaced by the delay plus
+// a random jitter. Hosts answering 429 or 503 are paused with an exponential backoff.
+type Limiter struct {
+	rate       *rate.Limiter
+	maxPerHost int
+	delay      time.Duration
+	jitter     time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState is the throttling state of a single host.
+type hostState struct {
+	slots       chan struct{}
+	nextStart   time.Time
+	pausedUntil time.Time
+	backoff     time.Duration
+}
+
+func newLimiter(options Options) *Limiter {
+	l := &Limiter{
+		maxPerHost: options.MaxConcurrencyPerHost,
+		delay:      options.Delay,
+		jitter:     options.Jitter,
+		hosts:      map[string]*hostState{},
+	}
+	if options.RateLimit > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(options.RateLimit), 1)
+	}
+	return l
+}
+
+func (l *Limiter) host(host string) *hostState {
+	host = strings.ToLower(host)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	state, ok := l.hosts[host]
+	if !ok {
+		state = &hostState{}
+		if l.maxPerHost > 0 {
+			state.slots = make(chan struct{}, l.maxPerHost)
+		}
+		l.hosts[host] = state
+	}
+	return state
+}
+
+// Limited reports whether requests are throttled by more than the adaptive backoff.
+func (l *Limiter) Limited() bool {
+	return l.rate != nil || l.maxPerHost > 0 || l.delay > 0 || l.jitter > 0
+}
+
+// Wait blocks until a request may be sent to the host, and returns the function that must be called once the request
+// is done to free its slot.
+func (l *Limiter) Wait(ctx context.Context, host string) (func(), error) {
+	state := l.host(host)
+
+	// Paused hosts are waited for before taking a slot, so that the slots go to hosts that can be sent requests
+	for {
+		l.mu.Lock()
+		pause := time.Until(state.pausedUntil)
+		l.mu.Unlock()
+		if pause <= 0 {
+			break
+		}
+		if err := sleep(ctx, pause); err != nil {
+			return nil, err
+		}
+	}
+
+	release := func() {}
+	if state.slots != nil {
+		select {
+		case state.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() {
+			once.Do(func() {
+				<-state.slots
+			})
+		}
+	}
+
+	if l.delay > 0 || l.jitter > 0 {
+		l.mu.Lock()
+		start := time.Now()
+		if state.nextStart.After(start) {
+			start = state.nextStart
+		}
+		spacing := l.delay
+		if l.jitter > 0 {
+			spacing += time.Duration(rand.Int63n(int64(l.jitter)))
+		}
+		state.nextStart = start.Add(spacing)
+		l.mu.Unlock()
+		if err := sleep(ctx, time.Until(start)); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// Observe adapts the pace of requests to the host to the status code of its response. 429 and 503 responses pause
+// the host for the duration of their Retry-After header or a backoff doubling with every such response, and other
+// responses halve the backoff.
+func (l *Limiter) Observe(host string, statusCode int, retryAfter string) {
+	state := l.host(host)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusServiceUnavailable {
+		state.backoff /= 2
+		if state.backoff < minBackoff {
+			state.backoff = 0
+		}
+		return
+	}
+
+	state.backoff *= 2
+	if state.backoff < minBackoff {
+		state.backoff = minBackoff
+	}
+	pause := state.backoff
+	if requested, ok := parseRetryAfter(retryAfter); ok {
+		pause = requested
+	}
+	if pause > maxBackoff {
+		pause = maxBackoff
+	}
+	if state.backoff > maxBackoff {
+		state.backoff = maxBackoff
+	}
+	if until := time.Now().Add(pause); until.After(state.pausedUntil) {
+		state.pausedUntil = until
+	}
+}
+
+// EngineLimits are the limits of the third party engines, which pace their requests themselves.
+type EngineLimits struct {
+	// RequestsPerSecond is the rate of requests, or zero when it is not limited. The delay and jitter between the
+	// requests to a host are converted to a rate, as the engines only scan a few hosts at once.
+	RequestsPerSecond float64
+	// Concurrency is the number of concurrent requests, or zero to keep the engine's default.
+	Concurrency int
+}
+
+// EngineLimits returns the limits third party engines are configured with.
+func (l *Limiter) EngineLimits() EngineLimits {
+	limits := EngineLimits{Concurrency: l.maxPerHost}
+	if l.rate != nil {
+		limits.RequestsPerSecond = float64(l.rate.Limit())
+	}
+	if spacing := l.delay + l.jitter/2; spacing > 0 {
+		delayRate := 1 / spacing.Seconds()
+		if limits.RequestsPerSecond == 0 || delayRate < limits.RequestsPerSecond {
+			limits.RequestsPerSecond = delayRate
+		}
+	}
+	return limits
+}
+
+// PerSecond returns the rate as requests per second, or zero when it is not limited or below one request per second.
+func (e EngineLimits) PerSecond() int {
+	if e.RequestsPerSecond < 1 {
+		return 0
+	}
+	return int(math.Floor(e.RequestsPerSecond))
+}
+
+// PerMinute returns the rate as requests per minute, for rates below one request per second, or zero otherwise.
+func (e EngineLimits) PerMinute() int {
+	if e.RequestsPerSecond == 0 || e.RequestsPerSecond >= 1 {
+		return 0
+	}
+	return int(math.Max(1, math.Floor(e.RequestsPerSecond*60)))
+}
+
+// Cap returns the engine's default concurrency, lowered to the concurrency limit when there is one.
+func (e EngineLimits) Cap(defaultConcurrency int) int {
+	if e.Concurrency > 0 && e.Concurrency < defaultConcurrency {
+		return e.Concurrency
+	}
+	return defaultConcurrency
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/har"
+)
+
+// recordableRequest returns the request with a body that can be read again and the body it sends, so that the body
+// can be recorded without consuming the one sent.
+func recordableRequest(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		return req, data, err
+	}
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	// RoundTrippers must not modify the request they are given
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return req, data, nil
+}
+
+// record adds the request and its response to the HAR recorder. The beginning of the response body is read to be
+// recorded, and the response is returned with a body that still yields all of it.
+func (t *roundTripper) record(req *http.Request, body []byte, started time.Time, resp *http.Response, err error) *http.Response {
+	module := har.Module(req.Context())
+	if module == "" {
+		module = t.module
+	}
+	if module == "" {
+		module = t.recorder.Module(req.Context())
+	}
+	entry := har.NewEntry(module, started, req.Method, req.URL.String(), req.Proto, req.Header, body)
+	if err != nil {
+		entry.SetError(err, time.Since(started))
+		t.recorder.Add(entry)
+		return resp
+	}
+
+	wait := time.Since(started)
+	data, readErr := io.ReadAll(io.LimitReader(resp.Body, har.MaxBodySize+1))
+	receive := time.Since(started) - wait
+	recorded, size := data, len(data)
+	if len(data) > har.MaxBodySize {
+		recorded = data[:har.MaxBodySize]
+		if resp.ContentLength > 0 {
+			size = int(resp.ContentLength)
+		}
+	}
+	entry.SetResponse(resp.StatusCode, resp.Proto, resp.Header, recorded, size, wait, receive)
+	if readErr != nil {
+		entry.Error = readErr.Error()
+	}
+	t.recorder.Add(entry)
+
+	resp.Body = &recordedBody{Reader: io.MultiReader(bytes.NewReader(data), resp.Body), Closer: resp.Body}
+	return resp
+}
+
+// recordedBody is a response body whose beginning was read to be recorded.
+type recordedBody struct {
+	io.Reader
+	io.Closer
+}
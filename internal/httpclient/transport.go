@@ -0,0 +1,149 @@
+package httpclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/scope"
+)
+
+// retryBackoff is the delay before the first retry, doubled for every following retry.
+const retryBackoff = 500 * time.Millisecond
+
+// roundTripper blocks the requests that are not in the scope, adds the default and authentication headers to the
+// others, throttles them with the Limiter, records them in the HAR recorder and retries them after network errors and
+// transient responses.
+type roundTripper struct {
+	next          http.RoundTripper
+	headers       http.Header
+	authenticator Authenticator
+	scope         *scope.Scope
+	limiter       *Limiter
+	recorder      *har.Recorder
+	module        string
+	retries       int
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Every request is checked, including those of redirects, which reach the RoundTripper as new requests
+	if err := t.scope.Check(req.Context(), req.URL); err != nil {
+		return nil, err
+	}
+	headers := t.headers
+	if t.authenticator != nil && !authenticationDisabled(req.Context()) {
+		authHeaders, err := t.authenticator.Headers()
+		if err != nil {
+			return nil, fmt.Errorf("failed to authenticate request: %v", err)
+		}
+		headers = http.Header{}
+		for name, values := range t.headers {
+			headers[name] = values
+		}
+		for name, values := range authHeaders {
+			headers[name] = values
+		}
+	}
+	if len(headers) > 0 {
+		// RoundTrippers must not modify the request they are given
+		req = req.Clone(req.Context())
+		for name, values := range headers {
+			if _, ok := req.Header[name]; !ok {
+				req.Header[name] = values
+			}
+		}
+	}
+
+	var body []byte
+	if t.recorder != nil {
+		var err error
+		if req, body, err = recordableRequest(req); err != nil {
+			return nil, err
+		}
+	}
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		release, err := t.limiter.Wait(req.Context(), req.URL.Host)
+		if err != nil {
+			return nil, err
+		}
+		started := time.Now()
+		resp, err := t.next.RoundTrip(req)
+		if t.recorder != nil {
+			// Every attempt is recorded, including those that are retried
+			resp = t.record(req, body, started, resp, err)
+		}
+		if resp != nil {
+			t.limiter.Observe(req.URL.Host, resp.StatusCode, resp.Header.Get("Retry-After"))
+		}
+		if attempt >= t.retries || !retryable(resp, err) || !rewindable(req) {
+			if err != nil {
+				release()
+				return resp, err
+			}
+			// The request holds its slot of the host until its response is read
+			resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+			return resp, nil
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		release()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+
+		if req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// releasingBody frees the slot of its request once it is read to the end or closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewindable reports whether the body of the request can be sent again.
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
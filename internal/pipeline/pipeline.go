@@ -0,0 +1,288 @@
+// Package pipeline runs declarative scan pipelines, which chain the webscan scans as a DAG of stages. A stage scans the
+// targets of the pipeline definition and those selected from the reports of earlier stages, such as the URLs found by
+// a web server probe or the matched URLs of application fingerprint findings. Stages run as soon as the stages they
+// select from completed, and each stage scans its targets concurrently.
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/reportfile"
+)
+
+// defaultConcurrency is the number of targets scanned concurrently by a stage when the definition does not set it.
+const defaultConcurrency = 5
+
+// A Report is the report of a pipeline run, with the reports of every stage in the order of the definition.
+type Report struct {
+	Name   string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Stages []StageReport `json:"stages" yaml:"stages"`
+	Errors []string      `json:"errors" yaml:"errors"`
+}
+
+// A StageReport holds the targets a stage scanned and the reports of its scans, one per target for the scans of a
+// single target and a single report for WEBSERVER_PROBE and SPIDER. Stages without targets are skipped and have no
+// reports.
+type StageReport struct {
+	Name    string                   `json:"name" yaml:"name"`
+	Scan    webscan.PipelineScanType `json:"scan" yaml:"scan"`
+	Targets []string                 `json:"targets" yaml:"targets"`
+	Reports []interface{}            `json:"reports" yaml:"reports"`
+	Errors  []string                 `json:"errors" yaml:"errors"`
+}
+
+// outputs are the selectors each scan type can be selected from, which type the references between stages.
+var outputs = map[webscan.PipelineScanType][]webscan.PipelineSelectorType{
+	webscan.PipelineScanTypeWebserverProbe: {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeUrls},
+	webscan.PipelineScanTypeFingerprint:    {webscan.PipelineSelectorTypeTargets},
+	webscan.PipelineScanTypeSpider:         {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeLinks},
+	webscan.PipelineScanTypeRouteCapture: {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeUrls,
+		webscan.PipelineSelectorTypeRoutes},
+	webscan.PipelineScanTypeAppFingerprint: {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeFindings},
+	webscan.PipelineScanTypeSwagger:        {webscan.PipelineSelectorTypeTargets},
+	webscan.PipelineScanTypeGraphql:        {webscan.PipelineSelectorTypeTargets},
+	webscan.PipelineScanTypeFuzzPath:       {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeUrls},
+	webscan.PipelineScanTypeVuln:           {webscan.PipelineSelectorTypeTargets, webscan.PipelineSelectorTypeFindings},
+}
+
+// Load loads the pipeline definition file at path, in YAML or JSON. Relative pathlists of FUZZ_PATH stages are
+// resolved from the directory of the file.
+func Load(path string) (*webscan.PipelineDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipeline file %s: %v", path, err)
+	}
+	content, err := reportfile.Content(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pipeline file %s: %v", path, err)
+	}
+	definition := webscan.PipelineDefinition{}
+	if err := json.Unmarshal(content, &definition); err != nil {
+		return nil, fmt.Errorf("failed to decode pipeline file %s: %v", path, err)
+	}
+	for _, stage := range definition.Stages {
+		if stage != nil && stage.Pathlist != nil && !filepath.IsAbs(*stage.Pathlist) {
+			pathlist := filepath.Join(filepath.Dir(path), *stage.Pathlist)
+			stage.Pathlist = &pathlist
+		}
+	}
+	return &definition, nil
+}
+
+// stage is a stage of a validated pipeline.
+type stage struct {
+	*webscan.PipelineStage
+	selectors []selector
+	done      chan struct{}
+	report    StageReport
+	// reports are the reports of the scans of the stage, which selectors read once done is closed
+	reports []interface{}
+}
+
+// Validate checks a pipeline definition: stage names are unique, stages have the options of their scan type, and
+// selectors reference earlier or later stages by name with a selector of their scan type, without cycles.
+func Validate(definition *webscan.PipelineDefinition) error {
+	_, err := compile(definition)
+	return err
+}
+
+func compile(definition *webscan.PipelineDefinition) ([]*stage, error) {
+	if definition == nil || len(definition.Stages) == 0 {
+		return nil, errors.New("the pipeline has no stages")
+	}
+	if definition.Concurrency != nil && *definition.Concurrency < 1 {
+		return nil, errors.New("concurrency must be at least 1")
+	}
+	stages := []*stage{}
+	byName := map[string]*stage{}
+	for i, definitionStage := range definition.Stages {
+		if definitionStage == nil || definitionStage.Name == "" {
+			return nil, fmt.Errorf("stage %d has no name", i+1)
+		}
+		if _, ok := byName[definitionStage.Name]; ok {
+			return nil, fmt.Errorf("stage %s is defined twice", definitionStage.Name)
+		}
+		s := &stage{PipelineStage: definitionStage, done: make(chan struct{})}
+		s.report = StageReport{Name: s.Name, Scan: s.Scan, Targets: []string{}, Reports: []interface{}{}, Errors: []string{}}
+		if err := validateStage(s.PipelineStage); err != nil {
+			return nil, fmt.Errorf("stage %s: %v", s.Name, err)
+		}
+		stages = append(stages, s)
+		byName[s.Name] = s
+	}
+	for _, s := range stages {
+		for _, definitionSelector := range s.From {
+			compiled, err := compileSelector(definitionSelector, byName)
+			if err != nil {
+				return nil, fmt.Errorf("stage %s: %v", s.Name, err)
+			}
+			s.selectors = append(s.selectors, compiled)
+		}
+	}
+	if err := checkCycles(stages); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// validateStage checks that a stage has the options its scan type requires.
+func validateStage(s *webscan.PipelineStage) error {
+	if _, ok := outputs[s.Scan]; !ok {
+		return fmt.Errorf("invalid scan type %q", s.Scan)
+	}
+	if len(s.Targets) == 0 && len(s.From) == 0 {
+		return errors.New("targets or from is required")
+	}
+	if s.TimeoutSeconds != nil && *s.TimeoutSeconds < 1 {
+		return errors.New("timeoutSeconds must be at least 1")
+	}
+	if s.Scan == webscan.PipelineScanTypeFuzzPath && (s.Pathlist == nil || *s.Pathlist == "") {
+		return errors.New("pathlist is required by FUZZ_PATH stages")
+	}
+	if s.CaptureMethod != nil && *s.CaptureMethod != webscan.PageCaptureMethodRequest &&
+		*s.CaptureMethod != webscan.PageCaptureMethodBrowser {
+		return errors.New("captureMethod must be REQUEST or BROWSER")
+	}
+	return nil
+}
+
+// compileSelector checks that a selector references a stage with an output of its type.
+func compileSelector(definition *webscan.PipelineSelector, stages map[string]*stage) (selector, error) {
+	if definition == nil {
+		return selector{}, errors.New("empty selector")
+	}
+	from, ok := stages[definition.Stage]
+	if !ok {
+		return selector{}, fmt.Errorf("selector references unknown stage %q", definition.Stage)
+	}
+	supported := false
+	for _, output := range outputs[from.Scan] {
+		supported = supported || output == definition.Select
+	}
+	if !supported {
+		return selector{}, fmt.Errorf("%s stage %s has no %s to select", from.Scan, from.Name, definition.Select)
+	}
+	if (len(definition.Tags) > 0 || len(definition.Templates) > 0) && definition.Select != webscan.PipelineSelectorTypeFindings {
+		return selector{}, errors.New("tags and templates only filter FINDINGS selectors")
+	}
+	compiled := selector{PipelineSelector: definition, from: from}
+	if definition.Match != nil {
+		match, err := regexp.Compile(*definition.Match)
+		if err != nil {
+			return selector{}, fmt.Errorf("invalid match expression %q: %v", *definition.Match, err)
+		}
+		compiled.match = match
+	}
+	return compiled, nil
+}
+
+// checkCycles returns an error when stages select from themselves, directly or through other stages.
+func checkCycles(stages []*stage) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[*stage]int{}
+	var visit func(s *stage, path []string) error
+	visit = func(s *stage, path []string) error {
+		path = append(path, s.Name)
+		switch state[s] {
+		case visiting:
+			return fmt.Errorf("the stages select from each other in a cycle: %s", strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+		state[s] = visiting
+		for _, sel := range s.selectors {
+			if err := visit(sel.from, path); err != nil {
+				return err
+			}
+		}
+		state[s] = visited
+		return nil
+	}
+	for _, s := range stages {
+		if err := visit(s, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run validates and runs a pipeline. Every stage runs once the stages it selects from completed, even when they
+// reported errors, and is skipped when it has no targets. The context's error is returned once it is canceled.
+func Run(ctx context.Context, definition *webscan.PipelineDefinition) (Report, error) {
+	stages, err := compile(definition)
+	if err != nil {
+		return Report{}, err
+	}
+	concurrency := defaultConcurrency
+	if definition.Concurrency != nil {
+		concurrency = *definition.Concurrency
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range stages {
+		wg.Add(1)
+		go func(s *stage) {
+			defer wg.Done()
+			defer close(s.done)
+			for _, sel := range s.selectors {
+				select {
+				case <-sel.from.done:
+				case <-ctx.Done():
+					return
+				}
+			}
+			s.run(ctx, concurrency)
+		}(s)
+	}
+	wg.Wait()
+
+	report := Report{Stages: []StageReport{}, Errors: []string{}}
+	if definition.Name != nil {
+		report.Name = *definition.Name
+	}
+	for _, s := range stages {
+		report.Stages = append(report.Stages, s.report)
+		for _, stageErr := range s.report.Errors {
+			report.Errors = append(report.Errors, s.Name+": "+stageErr)
+		}
+	}
+	return report, ctx.Err()
+}
+
+// run scans the targets of the stage, those of the definition followed by those selected from earlier stages.
+func (s *stage) run(ctx context.Context, concurrency int) {
+	targets := []string{}
+	seen := map[string]bool{}
+	add := func(target string) {
+		if target != "" && !seen[target] {
+			seen[target] = true
+			targets = append(targets, target)
+		}
+	}
+	for _, target := range s.Targets {
+		add(target)
+	}
+	for _, sel := range s.selectors {
+		for _, target := range sel.selectFrom() {
+			add(target)
+		}
+	}
+	s.report.Targets = targets
+	if len(targets) == 0 {
+		return
+	}
+	s.reports, s.report.Errors = scan(ctx, s.PipelineStage, targets, concurrency)
+	s.report.Reports = s.reports
+}
@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/fingerprint"
+	"github.com/Method-Security/webscan/internal/fuzz"
+	"github.com/Method-Security/webscan/internal/graphql"
+	"github.com/Method-Security/webscan/internal/routecapture"
+	"github.com/Method-Security/webscan/internal/spider"
+	"github.com/Method-Security/webscan/internal/swagger"
+	"github.com/Method-Security/webscan/internal/vuln"
+	"github.com/Method-Security/webscan/internal/webserver"
+)
+
+// appFingerprintTags are the nuclei tags of the application types identified by APP_FINGERPRINT stages without tags,
+// the defaults of `webscan app fingerprint`.
+var appFingerprintTags = []string{"swagger", "k8s", "graphql", "grpc", "bucket"}
+
+// scan runs the scan of a stage against its targets and returns the reports of the scans along with the errors of
+// the scans that failed without a report. WEBSERVER_PROBE and SPIDER scan every target at once.
+func scan(ctx context.Context, s *webscan.PipelineStage, targets []string, concurrency int) ([]interface{}, []string) {
+	switch s.Scan {
+	case webscan.PipelineScanTypeWebserverProbe:
+		report, err := webserver.PerformWebServerProbe(ctx, strings.Join(targets, ","), time.Duration(timeout(s, 30))*time.Second)
+		return []interface{}{&report}, errorList(err)
+	case webscan.PipelineScanTypeSpider:
+		report, err := spider.PerformWebSpider(ctx, strings.Join(targets, ","))
+		return []interface{}{&report}, errorList(err)
+	case webscan.PipelineScanTypeAppFingerprint, webscan.PipelineScanTypeVuln:
+		// Every nuclei scan loads its own engine and templates, so targets are scanned one at a time
+		concurrency = 1
+	}
+
+	reports := make([]interface{}, len(targets))
+	failures := make([][]string, len(targets))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				return
+			}
+			report, err := scanTarget(ctx, s, target)
+			if err != nil {
+				failures[i] = []string{target + ": " + err.Error()}
+				return
+			}
+			reports[i] = report
+		}(i, target)
+	}
+	wg.Wait()
+
+	scanned := []interface{}{}
+	errs := []string{}
+	for i := range targets {
+		if reports[i] != nil {
+			scanned = append(scanned, reports[i])
+		}
+		errs = append(errs, failures[i]...)
+	}
+	return scanned, errs
+}
+
+// scanTarget runs the scan of a stage against a single target.
+func scanTarget(ctx context.Context, s *webscan.PipelineStage, target string) (interface{}, error) {
+	switch s.Scan {
+	case webscan.PipelineScanTypeFingerprint:
+		report := fingerprint.PerformFingerprint(ctx, target)
+		return &report, nil
+	case webscan.PipelineScanTypeRouteCapture:
+		method := webscan.PageCaptureMethodRequest
+		if s.CaptureMethod != nil {
+			method = *s.CaptureMethod
+		}
+		report := routecapture.PerformRouteCapture(ctx, target, method, true, false, timeout(s, 30), 5, false, nil, nil, nil, nil)
+		return &report, nil
+	case webscan.PipelineScanTypeAppFingerprint:
+		tags := s.Tags
+		if len(tags) == 0 {
+			tags = appFingerprintTags
+		}
+		report, err := vuln.PerformVulnScan(ctx, target, tags, "", "", "")
+		if err != nil {
+			return nil, err
+		}
+		return &report, nil
+	case webscan.PipelineScanTypeVuln:
+		report, err := vuln.PerformVulnScan(ctx, target, s.Tags, strings.Join(s.Severities, ","), "", "")
+		if err != nil {
+			return nil, err
+		}
+		return &report, nil
+	case webscan.PipelineScanTypeSwagger:
+		report := swagger.PerformSwaggerScan(ctx, target, false)
+		return &report, nil
+	case webscan.PipelineScanTypeGraphql:
+		report := graphql.PerformGraphQLScan(ctx, target)
+		return &report, nil
+	case webscan.PipelineScanTypeFuzzPath:
+		responseCodes := "200-299"
+		if s.ResponseCodes != nil {
+			responseCodes = *s.ResponseCodes
+		}
+		report := fuzz.PerformPathFuzz(ctx, target, *s.Pathlist, true, responseCodes, timeout(s, 300))
+		return &report, nil
+	}
+	return nil, nil
+}
+
+// timeout returns the timeout of a stage in seconds, or the default of its scan.
+func timeout(s *webscan.PipelineStage, defaultSeconds int) int {
+	if s.TimeoutSeconds != nil {
+		return *s.TimeoutSeconds
+	}
+	return defaultSeconds
+}
+
+// errorList returns the error as a list of errors, empty for a nil error.
+func errorList(err error) []string {
+	if err == nil {
+		return []string{}
+	}
+	return []string{err.Error()}
+}
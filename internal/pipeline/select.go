@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/spider"
+	"github.com/Method-Security/webscan/internal/vuln"
+	"github.com/Method-Security/webscan/internal/webserver"
+)
+
+// selector selects the targets of a stage from the reports of another stage.
+type selector struct {
+	*webscan.PipelineSelector
+	from  *stage
+	match *regexp.Regexp
+}
+
+// selectFrom returns the values the selector selects from the reports of its stage, which must be done.
+func (s selector) selectFrom() []string {
+	values := []string{}
+	add := func(value string) {
+		if value != "" && (s.match == nil || s.match.MatchString(value)) {
+			values = append(values, value)
+		}
+	}
+	if s.Select == webscan.PipelineSelectorTypeTargets {
+		for _, target := range s.from.report.Targets {
+			add(target)
+		}
+		return values
+	}
+	for _, report := range s.from.reports {
+		switch report := report.(type) {
+		case *webserver.ProbeReport:
+			for _, details := range report.URLs {
+				add(details.URL)
+			}
+		case *spider.WebSpiderReport:
+			for _, link := range report.Links {
+				add(link.Link)
+			}
+		case *webscan.FuzzPathReport:
+			for _, details := range report.Urls {
+				if details != nil {
+					add(details.Url)
+				}
+			}
+		case *webscan.RouteCaptureReport:
+			if s.Select == webscan.PipelineSelectorTypeRoutes {
+				for _, route := range report.Routes {
+					if route != nil {
+						add(route.Url)
+					}
+				}
+			} else {
+				for _, u := range report.Urls {
+					add(u)
+				}
+			}
+		case *vuln.VulnerabilityReport:
+			for _, finding := range report.Reports {
+				if !s.matchesFinding(finding) {
+					continue
+				}
+				// The matched URL of a finding is the URL of the application it identified, e.g. a Swagger UI
+				if finding.Context.FullPath != "" {
+					add(finding.Context.FullPath)
+				} else {
+					add(finding.Context.URL)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// matchesFinding reports whether a finding is from a template with one of the tags or IDs of the selector, or any
+// finding when the selector filters neither.
+func (s selector) matchesFinding(finding vuln.VulnerabilityFinding) bool {
+	if len(s.Tags) == 0 && len(s.Templates) == 0 {
+		return true
+	}
+	for _, template := range s.Templates {
+		if strings.EqualFold(template, finding.Context.TemplateID) {
+			return true
+		}
+	}
+	for _, tag := range s.Tags {
+		for _, findingTag := range finding.Info.Tags.ToSlice() {
+			if strings.EqualFold(tag, findingTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
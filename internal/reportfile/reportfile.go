@@ -0,0 +1,68 @@
+// Package reportfile loads reports previously written by webscan so that they can be used as the input of another
+// command. Reports are accepted in every output format webscan writes (signal, json and yaml) as well as bare report
+// content without the signal envelope.
+package reportfile
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the report file at path and decodes its content into report.
+func Load(path string, report interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read report file %s: %v", path, err)
+	}
+	content, err := Content(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse report file %s: %v", path, err)
+	}
+	if err := json.Unmarshal(content, report); err != nil {
+		return fmt.Errorf("failed to decode report file %s: %v", path, err)
+	}
+	return nil
+}
+
+// Content extracts the JSON encoded report from the contents of a report file. The signal envelope is removed, and
+// base64 encoded signal content and YAML documents are converted to JSON.
+func Content(data []byte) ([]byte, error) {
+	if !json.Valid(data) {
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("report is neither JSON nor YAML: %v", err)
+		}
+		converted, err := json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert YAML report to JSON: %v", err)
+		}
+		data = converted
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return data, nil
+	}
+	content, ok := envelope["content"]
+	if !ok {
+		return data, nil
+	}
+	if _, ok := envelope["started_at"]; !ok {
+		return data, nil
+	}
+
+	// The signal format stores the report as base64 encoded JSON
+	var encoded string
+	if err := json.Unmarshal(content, &encoded); err == nil {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode signal content: %v", err)
+		}
+		return decoded, nil
+	}
+	return content, nil
+}
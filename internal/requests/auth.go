@@ -0,0 +1,285 @@
+package requests
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const (
+	authTechniqueRemoved        = "auth-header-removed"
+	authTechniqueMalformed      = "malformed-token"
+	authTechniqueMethodOverride = "method-override"
+	authTechniquePath           = "path-normalization"
+)
+
+// authHeaderPattern matches the names of headers that commonly carry credentials or session state.
+var authHeaderPattern = regexp.MustCompile(`(?i)^(?:authorization|proxy-authorization|cookie)$|auth|token|session|api[-_]?key`)
+
+// methodOverrideHeaders are honoured by many frameworks and proxies to tunnel one HTTP method through another.
+var methodOverrideHeaders = []string{"X-HTTP-Method-Override", "X-HTTP-Method", "X-Method-Override"}
+
+// authVariant is a modified version of the authenticated request that should be rejected if the route enforces
+// authentication.
+type authVariant struct {
+	Technique string
+	Location  webscan.ParamLocation
+	Parameter string
+	Payload   string
+	Method    string
+	Path      string
+	Headers   map[string]string
+}
+
+// authBypassCheck replays the authenticated request without its credentials, with malformed credentials, through a
+// method override and through equivalent spellings of the path. Every variant that returns the same content as the
+// authenticated baseline is reported.
+type authBypassCheck struct{}
+
+func (c *authBypassCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	authHeaders := authHeaderNames(s.params.HeaderParams)
+	if len(authHeaders) == 0 {
+		return findings, append(errors, "AUTH check requires authentication headers in headerParams")
+	}
+	if s.baseline.StatusCode >= http.StatusBadRequest {
+		return findings, append(errors, fmt.Sprintf("AUTH check skipped because the authenticated baseline returned status %d", s.baseline.StatusCode))
+	}
+
+	// Removing every credential is the reference variant: when it already returns the authenticated content the
+	// route does not enforce authentication at all, and the remaining variants add nothing beyond that
+	variants := c.variants(s, authHeaders)
+	enforced := true
+	for i, variant := range variants {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+
+		params := cloneParams(s.params)
+		params.HeaderParams = variant.Headers
+		resp, err := executeRequest(ctx, variant.Method, s.baseURL, variant.Path, params)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("%s AUTH variant %q failed: %v", variant.Technique, variant.Payload, err))
+			continue
+		}
+		if !similarResponses(s.baseline, resp) {
+			continue
+		}
+
+		confidence := webscan.ConfidenceHigh
+		evidence := fmt.Sprintf(
+			"variant returned status %d, %d bytes, body sha256 %s matching the authenticated baseline (status %d, %d bytes, body sha256 %s)",
+			resp.StatusCode, len(resp.Body), shortHash(resp.Body), s.baseline.StatusCode, len(s.baseline.Body), shortHash(s.baseline.Body),
+		)
+		if i == 0 {
+			enforced = false
+		} else if !enforced {
+			confidence = webscan.ConfidenceLow
+			evidence += "; the route also returns this content without any credentials"
+		}
+		point := injectionPoint{Location: variant.Location, Name: variant.Parameter}
+		findings = append(findings, newFinding(webscan.VulnTypeAuth, variant.Technique, point, variant.Payload, evidence, confidence, resp))
+	}
+
+	return findings, errors
+}
+
+// variants lists the request variants to try. The first variant always removes every authentication header.
+func (c *authBypassCheck) variants(s *scanner, authHeaders []string) []authVariant {
+	unauthenticated := cloneMap(s.params.HeaderParams)
+	for _, name := range authHeaders {
+		delete(unauthenticated, name)
+	}
+
+	variants := []authVariant{{
+		Technique: authTechniqueRemoved,
+		Location:  webscan.ParamLocationHeader,
+		Parameter: strings.Join(authHeaders, ", "),
+		Payload:   "removed " + strings.Join(authHeaders, ", "),
+		Method:    s.method,
+		Path:      s.path,
+		Headers:   unauthenticated,
+	}}
+
+	// With several credentials, each one on its own may be the only one the route actually checks
+	if len(authHeaders) > 1 {
+		for _, name := range authHeaders {
+			headers := cloneMap(s.params.HeaderParams)
+			delete(headers, name)
+			variants = append(variants, authVariant{
+				Technique: authTechniqueRemoved,
+				Location:  webscan.ParamLocationHeader,
+				Parameter: name,
+				Payload:   "removed " + name,
+				Method:    s.method,
+				Path:      s.path,
+				Headers:   headers,
+			})
+		}
+	}
+
+	for _, name := range authHeaders {
+		for _, value := range malformedCredentials(name, s.params.HeaderParams[name]) {
+			headers := cloneMap(s.params.HeaderParams)
+			headers[name] = value
+			variants = append(variants, authVariant{
+				Technique: authTechniqueMalformed,
+				Location:  webscan.ParamLocationHeader,
+				Parameter: name,
+				Payload:   fmt.Sprintf("%s: %s", name, value),
+				Method:    s.method,
+				Path:      s.path,
+				Headers:   headers,
+			})
+		}
+	}
+
+	overrideMethod := http.MethodPost
+	if strings.EqualFold(s.method, http.MethodPost) {
+		overrideMethod = http.MethodGet
+	}
+	for _, header := range methodOverrideHeaders {
+		headers := cloneMap(unauthenticated)
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[header] = strings.ToUpper(s.method)
+		variants = append(variants, authVariant{
+			Technique: authTechniqueMethodOverride,
+			Location:  webscan.ParamLocationHeader,
+			Parameter: header,
+			Payload:   fmt.Sprintf("%s %s with %s: %s", overrideMethod, s.path, header, strings.ToUpper(s.method)),
+			Method:    overrideMethod,
+			Path:      s.path,
+			Headers:   headers,
+		})
+	}
+
+	for _, path := range normalizedPaths(s.path) {
+		variants = append(variants, authVariant{
+			Technique: authTechniquePath,
+			Location:  webscan.ParamLocationPath,
+			Parameter: path,
+			Payload:   fmt.Sprintf("%s %s", s.method, path),
+			Method:    s.method,
+			Path:      path,
+			Headers:   unauthenticated,
+		})
+	}
+
+	return variants
+}
+
+// authHeaderNames returns the sorted names of the headers that look like they carry credentials.
+func authHeaderNames(headers map[string]string) []string {
+	names := []string{}
+	for name := range headers {
+		if authHeaderPattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// malformedCredentials returns values for the header that keep its shape but should never be accepted: empty and
+// placeholder tokens, and for JWTs an unsigned token and one with a corrupted signature.
+func malformedCredentials(name string, value string) []string {
+	scheme, token := "", value
+	if strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Proxy-Authorization") {
+		if parts := strings.SplitN(value, " ", 2); len(parts) == 2 {
+			scheme, token = parts[0]+" ", parts[1]
+		}
+	}
+
+	values := []string{scheme, scheme + "null", scheme + "undefined"}
+	if strings.EqualFold(scheme, "Basic ") {
+		values = append(values, scheme+base64.StdEncoding.EncodeToString([]byte("invalid:invalid")))
+	}
+
+	if segments := strings.Split(token, "."); len(segments) == 3 && segments[0] != "" && segments[1] != "" {
+		unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + segments[1] + "."
+		values = append(values, scheme+unsigned, scheme+segments[0]+"."+segments[1]+"."+corrupt(segments[2]))
+	} else if token != "" {
+		values = append(values, scheme+corrupt(token))
+	}
+	return values
+}
+
+// corrupt changes the last character of value so that it no longer matches the original.
+func corrupt(value string) string {
+	if value == "" {
+		return "x"
+	}
+	last := value[len(value)-1]
+	replacement := byte('A')
+	if last == 'A' {
+		replacement = 'B'
+	}
+	return value[:len(value)-1] + string(replacement)
+}
+
+// normalizedPaths returns spellings of path that many servers route to the same handler while access rules written
+// for the canonical path may not match them: a toggled trailing slash, upper case, a matrix parameter, a dot segment
+// and a doubled slash.
+func normalizedPaths(path string) []string {
+	if path == "" || path == "/" {
+		return nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	candidates := []string{}
+	if strings.HasSuffix(path, "/") {
+		candidates = append(candidates, strings.TrimSuffix(path, "/"))
+	} else {
+		candidates = append(candidates, path+"/")
+	}
+	candidates = append(candidates, upperPath(path))
+
+	// Insert a matrix parameter after the first segment, turning /admin/users into /admin;/users
+	if index := strings.Index(path[1:], "/"); index >= 0 {
+		candidates = append(candidates, path[:index+1]+";"+path[index+1:])
+	} else {
+		candidates = append(candidates, path+";/")
+	}
+	candidates = append(candidates, "/%2e"+path, "/"+path)
+
+	paths := []string{}
+	seen := map[string]bool{path: true}
+	for _, candidate := range candidates {
+		if !seen[candidate] {
+			seen[candidate] = true
+			paths = append(paths, candidate)
+		}
+	}
+	return paths
+}
+
+// upperPath upper-cases the literal parts of a path template, leaving {name} placeholders intact.
+func upperPath(path string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range path {
+		switch {
+		case r == '{':
+			depth++
+		case r == '}' && depth > 0:
+			depth--
+		case depth == 0:
+			r = []rune(strings.ToUpper(string(r)))[0]
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
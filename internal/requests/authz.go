@@ -0,0 +1,539 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// authzUnknownOwner is reported as the owner of an object when no identity could be tied to it.
+const authzUnknownOwner = "unknown"
+
+// idParamPattern matches the names of query parameters that usually reference an object, e.g. id, user_id or orderId.
+var idParamPattern = regexp.MustCompile(`^(?i:id|.*[-_]id|.*uuid|.*guid)$|[a-z0-9]Id$`)
+
+// idSegmentPattern matches literal path segments that look like object identifiers: integers, UUIDs and MongoDB
+// ObjectIds.
+var idSegmentPattern = regexp.MustCompile(`^(?:\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{24})$`)
+
+// authzParam is a parameter of a request that references the object the request operates on. Key is the name under
+// which the identities declare their own value for the parameter, if any of them do.
+type authzParam struct {
+	Location webscan.ParamLocation
+	Name     string
+	Value    string
+	Key      string
+}
+
+// authzSubject is an object-scoped request that is replayed as each of the identities.
+type authzSubject struct {
+	Route   string
+	Method  string
+	BaseURL string
+	Path    string
+	Params  webscan.ParsedParams
+	Objects []authzParam
+}
+
+// PerformAuthzScan tests every object-scoped request of the batch for broken object level authorization. Each request
+// is sent as every identity for that identity's own objects, then replayed as the other identities and with the object
+// IDs swapped for another identity's or incremented. Responses that return another identity's data are reported.
+// Requests without object IDs declared by an identity are assumed to reference objects of the first identity.
+func PerformAuthzScan(ctx context.Context, target string, batch []webscan.RequestParams, identities []*webscan.AuthzIdentity, concurrency int) webscan.AuthzReport {
+	report := webscan.AuthzReport{Target: target}
+	// Identities provide the credentials of their requests, which must not be replaced by the authentication profile
+	ctx = httpclient.WithoutAuthentication(ctx)
+
+	names := make(map[string]bool)
+	for _, identity := range identities {
+		if identity == nil || identity.Name == "" {
+			report.Errors = append(report.Errors, "every identity must have a name")
+			return report
+		}
+		// The headers of identities hold their credentials, which are not written to the HAR archive
+		for header := range identity.Headers {
+			httpclient.FromContext(ctx).Recorder().RedactHeaders(header)
+		}
+		if names[identity.Name] {
+			report.Errors = append(report.Errors, fmt.Sprintf("identity %s is declared more than once", identity.Name))
+			return report
+		}
+		names[identity.Name] = true
+		report.Identities = append(report.Identities, identity.Name)
+	}
+	if len(identities) < 2 {
+		report.Errors = append(report.Errors, "at least two identities are required")
+		return report
+	}
+
+	subjects := []*authzSubject{}
+	for _, params := range batch {
+		subject, err := newAuthzSubject(params, identities)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		if subject != nil {
+			subjects = append(subjects, subject)
+			if report.BaseUrl == "" {
+				report.BaseUrl = subject.BaseURL
+			}
+		}
+	}
+	report.RoutesTested = len(subjects)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	findings := make([][]*webscan.AuthzFinding, len(subjects))
+	errs := make([][]string, len(subjects))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(subjects); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				findings[i], errs[i] = subjects[i].test(ctx, identities)
+			}
+		}()
+	}
+
+queue:
+	for i := range subjects {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			report.Errors = append(report.Errors, ctx.Err().Error())
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := range subjects {
+		report.Findings = append(report.Findings, findings[i]...)
+		report.Errors = append(report.Errors, errs[i]...)
+	}
+	return report
+}
+
+// newAuthzSubject prepares a request for authorization testing. Literal path segments that look like object IDs are
+// turned into path parameters named after their position, e.g. segment2 for /users/42. A nil subject is returned for
+// requests that do not reference any object.
+func newAuthzSubject(params webscan.RequestParams, identities []*webscan.AuthzIdentity) (*authzSubject, error) {
+	baseURL, path, method := stringValueOf(params.BaseUrl), stringValueOf(params.Path), strings.ToUpper(stringValueOf(params.Method))
+	if baseURL == "" || path == "" || method == "" {
+		return nil, fmt.Errorf("request %s %s is missing its base URL, path or method", method, path)
+	}
+	if !isValidHTTPMethod(method) {
+		return nil, fmt.Errorf("request %s %s has an invalid HTTP method", method, path)
+	}
+	parsed, err := parseAllParams(params)
+	if err != nil {
+		return nil, fmt.Errorf("request %s %s: %v", method, path, err)
+	}
+
+	subject := &authzSubject{
+		Route:   method + " " + path,
+		Method:  method,
+		BaseURL: baseURL,
+		Path:    path,
+	}
+
+	if !strings.Contains(path, "{") {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if idSegmentPattern.MatchString(segment) {
+				name := fmt.Sprintf("segment%d", i)
+				segments[i] = "{" + name + "}"
+				if parsed.PathParams == nil {
+					parsed.PathParams = make(map[string]string)
+				}
+				parsed.PathParams[name] = segment
+			}
+		}
+		subject.Path = strings.Join(segments, "/")
+	}
+	subject.Params = parsed
+
+	for name, value := range parsed.PathParams {
+		subject.Objects = append(subject.Objects, authzParam{Location: webscan.ParamLocationPath, Name: name, Value: value})
+	}
+	for name, value := range parsed.QueryParams {
+		if idParamPattern.MatchString(name) {
+			subject.Objects = append(subject.Objects, authzParam{Location: webscan.ParamLocationQuery, Name: name, Value: value})
+		}
+	}
+	if len(subject.Objects) == 0 {
+		return nil, nil
+	}
+	sort.Slice(subject.Objects, func(i, j int) bool {
+		if subject.Objects[i].Location != subject.Objects[j].Location {
+			return subject.Objects[i].Location < subject.Objects[j].Location
+		}
+		return subject.Objects[i].Name < subject.Objects[j].Name
+	})
+	for i := range subject.Objects {
+		subject.Objects[i].Key = objectKey(subject.Objects[i], identities)
+	}
+	return subject, nil
+}
+
+// objectKey finds the name under which identities declare their value for the parameter: the parameter's own name, or
+// else the name of a declared object ID equal to the parameter's value.
+func objectKey(param authzParam, identities []*webscan.AuthzIdentity) string {
+	for _, identity := range identities {
+		if _, ok := identity.ObjectIds[param.Name]; ok {
+			return param.Name
+		}
+	}
+	for _, identity := range identities {
+		keys := make([]string, 0, len(identity.ObjectIds))
+		for key := range identity.ObjectIds {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if identity.ObjectIds[key] == param.Value {
+				return key
+			}
+		}
+	}
+	return ""
+}
+
+// test sends the request as every identity and replays each identity's objects as the others.
+func (s *authzSubject) test(ctx context.Context, identities []*webscan.AuthzIdentity) ([]*webscan.AuthzFinding, []string) {
+	log := svc1log.FromContext(ctx)
+	log.Debug("Testing object level authorization", svc1log.SafeParam("route", s.Route))
+
+	findings := []*webscan.AuthzFinding{}
+	errs := []string{}
+	anonymous, err := s.send(ctx, s.Params, nil)
+	if err != nil {
+		return findings, []string{fmt.Sprintf("anonymous request to %s failed: %v", s.Route, err)}
+	}
+
+	owned := make([]webscan.ParsedParams, len(identities))
+	ownedResponses := make([]*response, len(identities))
+	for i, identity := range identities {
+		owned[i] = s.paramsFor(identity)
+		resp, err := s.send(ctx, owned[i], identity)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("request to %s as %s failed: %v", s.Route, identity.Name, err))
+			continue
+		}
+		ownedResponses[i] = resp
+	}
+
+	for a, owner := range identities {
+		ownerResp := ownedResponses[a]
+		if ownerResp == nil || !isSuccess(ownerResp.StatusCode) || len(ownerResp.Body) == 0 {
+			continue
+		}
+		// Objects returned to anonymous requests are public rather than leaked
+		if isSuccess(anonymous.StatusCode) && sameObjectParams(owned[a], s.Params) && similarResponses(anonymous, ownerResp) {
+			continue
+		}
+
+		for b, attacker := range identities {
+			if a == b {
+				continue
+			}
+			finding, err := s.replay(ctx, identities, a, b, owned, ownedResponses)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("request to %s as %s for the object of %s failed: %v", s.Route, attacker.Name, owner.Name, err))
+				continue
+			}
+			if finding != nil {
+				findings = append(findings, finding)
+			}
+		}
+
+		incremented, incrementErrs := s.increment(ctx, identities, a, owned[a], ownerResp)
+		findings = append(findings, incremented...)
+		errs = append(errs, incrementErrs...)
+	}
+	return findings, errs
+}
+
+// replay requests the object of identity a as identity b and reports the response when it returns a's data.
+func (s *authzSubject) replay(ctx context.Context, identities []*webscan.AuthzIdentity, a int, b int, owned []webscan.ParsedParams, ownedResponses []*response) (*webscan.AuthzFinding, error) {
+	owner, attacker := identities[a], identities[b]
+	ownerResp, attackerOwn := ownedResponses[a], ownedResponses[b]
+
+	technique := webscan.AuthzTechniqueIdSwap
+	var resp *response
+	if sameObjectParams(owned[a], owned[b]) {
+		technique = webscan.AuthzTechniqueCrossIdentity
+		resp = attackerOwn
+	} else {
+		var err error
+		if resp, err = s.send(ctx, owned[a], attacker); err != nil {
+			return nil, err
+		}
+	}
+	if resp == nil || !isSuccess(resp.StatusCode) || len(resp.Body) == 0 {
+		return nil, nil
+	}
+
+	param, original, tested := s.swappedParam(owned[b], owned[a])
+	if marker := foundMarker(resp, owner, attackerOwn, technique); marker != "" {
+		evidence := fmt.Sprintf("response to %s contains %q from the data of %s", attacker.Name, marker, owner.Name)
+		return s.newFinding(technique, owner.Name, attacker.Name, param, original, tested, evidence, webscan.ConfidenceHigh, resp), nil
+	}
+	if !similarResponses(ownerResp, resp, original, tested) {
+		return nil, nil
+	}
+
+	switch technique {
+	case webscan.AuthzTechniqueIdSwap:
+		// When the attacker's own object is indistinguishable the route does not return object specific data
+		if attackerOwn != nil && isSuccess(attackerOwn.StatusCode) && similarResponses(attackerOwn, ownerResp, original, tested) {
+			return nil, nil
+		}
+		evidence := fmt.Sprintf("%s received the same %d response as %s for %s=%s, which %s declares as its own", attacker.Name, resp.StatusCode, owner.Name, param.Name, tested, owner.Name)
+		return s.newFinding(technique, owner.Name, attacker.Name, param, original, tested, evidence, webscan.ConfidenceHigh, resp), nil
+	default:
+		// Without declared object IDs, the object is only attributed to the first identity
+		if a != 0 {
+			return nil, nil
+		}
+		evidence := fmt.Sprintf("%s received the same %d response as %s, whose object the request is assumed to reference; verify that the object is not shared", attacker.Name, resp.StatusCode, owner.Name)
+		return s.newFinding(technique, owner.Name, attacker.Name, nil, "", "", evidence, webscan.ConfidenceMedium, resp), nil
+	}
+}
+
+// increment requests the objects next to each numeric object ID of identity a as identity a, and reports objects that
+// are returned with the same structure but different content: objects a does not own but is able to read.
+func (s *authzSubject) increment(ctx context.Context, identities []*webscan.AuthzIdentity, a int, params webscan.ParsedParams, ownerResp *response) ([]*webscan.AuthzFinding, []string) {
+	identity := identities[a]
+	findings := []*webscan.AuthzFinding{}
+	errs := []string{}
+
+	for _, object := range s.Objects {
+		value := objectValue(params, object)
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		for _, delta := range []int64{1, -1} {
+			if id+delta < 1 {
+				continue
+			}
+			tested := strconv.FormatInt(id+delta, 10)
+			if declaredObjectID(identities, object.Key, tested) {
+				// Requests for the objects of other identities are covered by the ID swap
+				continue
+			}
+
+			variant := withObjectValue(params, object, tested)
+			resp, err := s.send(ctx, variant, identity)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("request to %s as %s with %s=%s failed: %v", s.Route, identity.Name, object.Name, tested, err))
+				continue
+			}
+			if !isSuccess(resp.StatusCode) || len(resp.Body) == 0 || similarResponses(ownerResp, resp, value, tested) || !sameShape(ownerResp, resp) {
+				continue
+			}
+			anonymous, err := s.send(ctx, variant, nil)
+			if err == nil && isSuccess(anonymous.StatusCode) && similarResponses(anonymous, resp) {
+				continue
+			}
+
+			owner, confidence := authzUnknownOwner, webscan.ConfidenceLow
+			evidence := fmt.Sprintf("%s=%s returned a different %d response with the same structure as %s's own object %s=%s", object.Name, tested, resp.StatusCode, identity.Name, object.Name, value)
+			for b, other := range identities {
+				if b == a {
+					continue
+				}
+				if marker := foundMarker(resp, other, nil, webscan.AuthzTechniqueIdIncrement); marker != "" {
+					owner, confidence = other.Name, webscan.ConfidenceHigh
+					evidence = fmt.Sprintf("%s=%s returned a response containing %q from the data of %s", object.Name, tested, marker, other.Name)
+					break
+				}
+			}
+			param := object
+			findings = append(findings, s.newFinding(webscan.AuthzTechniqueIdIncrement, owner, identity.Name, &param, value, tested, evidence, confidence, resp))
+		}
+	}
+	return findings, errs
+}
+
+// paramsFor returns the request parameters that reference the identity's own objects, using the object IDs it
+// declares.
+func (s *authzSubject) paramsFor(identity *webscan.AuthzIdentity) webscan.ParsedParams {
+	params := s.Params
+	for _, object := range s.Objects {
+		if value, ok := identity.ObjectIds[object.Key]; ok && object.Key != "" && value != objectValue(params, object) {
+			params = withObjectValue(params, object, value)
+		}
+	}
+	return params
+}
+
+// swappedParam returns the first object parameter whose value differs between the attacker's and the owner's
+// parameters, along with both values.
+func (s *authzSubject) swappedParam(attackerParams webscan.ParsedParams, ownerParams webscan.ParsedParams) (*authzParam, string, string) {
+	for _, object := range s.Objects {
+		original, tested := objectValue(attackerParams, object), objectValue(ownerParams, object)
+		if original != tested {
+			param := object
+			return &param, original, tested
+		}
+	}
+	return nil, "", ""
+}
+
+// send sends the request with the credentials of the identity, or without any credentials when identity is nil.
+func (s *authzSubject) send(ctx context.Context, params webscan.ParsedParams, identity *webscan.AuthzIdentity) (*response, error) {
+	return executeRequest(ctx, s.Method, s.BaseURL, s.Path, withIdentity(params, identity))
+}
+
+func (s *authzSubject) newFinding(technique webscan.AuthzTechnique, owner string, attacker string, param *authzParam, original string, tested string, evidence string, confidence webscan.Confidence, resp *response) *webscan.AuthzFinding {
+	hash := bodyHash(resp.Body)
+	finding := &webscan.AuthzFinding{
+		Route:        s.Route,
+		Technique:    technique,
+		Owner:        owner,
+		Attacker:     attacker,
+		Evidence:     evidence,
+		Confidence:   confidence,
+		StatusCode:   resp.StatusCode,
+		ResponseHash: &hash,
+	}
+	if param != nil {
+		location, name := param.Location, param.Name
+		finding.Location, finding.Parameter = &location, &name
+		finding.OriginalValue, finding.TestedValue = &original, &tested
+	}
+	return finding
+}
+
+// withIdentity replaces the credentials of the request with those of the identity.
+func withIdentity(params webscan.ParsedParams, identity *webscan.AuthzIdentity) webscan.ParsedParams {
+	params = cloneParams(params)
+	if params.HeaderParams == nil {
+		params.HeaderParams = make(map[string]string)
+	}
+	for _, name := range authHeaderNames(params.HeaderParams) {
+		delete(params.HeaderParams, name)
+	}
+	if identity == nil {
+		return params
+	}
+
+	for name, value := range identity.Headers {
+		params.HeaderParams[name] = value
+	}
+	if len(identity.Cookies) > 0 {
+		cookies := make([]string, 0, len(identity.Cookies))
+		for name, value := range identity.Cookies {
+			cookies = append(cookies, name+"="+value)
+		}
+		sort.Strings(cookies)
+		if existing, ok := params.HeaderParams["Cookie"]; ok {
+			cookies = append([]string{existing}, cookies...)
+		}
+		params.HeaderParams["Cookie"] = strings.Join(cookies, "; ")
+	}
+	return params
+}
+
+func objectValue(params webscan.ParsedParams, object authzParam) string {
+	if object.Location == webscan.ParamLocationQuery {
+		return params.QueryParams[object.Name]
+	}
+	return params.PathParams[object.Name]
+}
+
+func withObjectValue(params webscan.ParsedParams, object authzParam, value string) webscan.ParsedParams {
+	params = cloneParams(params)
+	if object.Location == webscan.ParamLocationQuery {
+		params.QueryParams[object.Name] = value
+	} else {
+		params.PathParams[object.Name] = value
+	}
+	return params
+}
+
+func sameObjectParams(a webscan.ParsedParams, b webscan.ParsedParams) bool {
+	for name, value := range a.PathParams {
+		if b.PathParams[name] != value {
+			return false
+		}
+	}
+	for name, value := range a.QueryParams {
+		if b.QueryParams[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func declaredObjectID(identities []*webscan.AuthzIdentity, key string, value string) bool {
+	for _, identity := range identities {
+		if key != "" && identity.ObjectIds[key] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// foundMarker returns the first marker of the owner contained in the response. For an ID swap, markers that the
+// attacker's own object also contains are ignored, as the route returns them regardless of the object.
+func foundMarker(resp *response, owner *webscan.AuthzIdentity, attackerOwn *response, technique webscan.AuthzTechnique) string {
+	for _, marker := range owner.Markers {
+		if marker == "" || !bytes.Contains(resp.Body, []byte(marker)) {
+			continue
+		}
+		if technique == webscan.AuthzTechniqueIdSwap && attackerOwn != nil && bytes.Contains(attackerOwn.Body, []byte(marker)) {
+			continue
+		}
+		return marker
+	}
+	return ""
+}
+
+// sameShape reports whether two responses have the same structure: JSON objects with the same keys, JSON arrays, or
+// otherwise the same media type.
+func sameShape(a *response, b *response) bool {
+	var aValue, bValue interface{}
+	aErr, bErr := json.Unmarshal(a.Body, &aValue), json.Unmarshal(b.Body, &bValue)
+	if aErr != nil || bErr != nil {
+		return aErr != nil && bErr != nil && responseMediaType(a) == responseMediaType(b)
+	}
+	switch aTyped := aValue.(type) {
+	case map[string]interface{}:
+		bTyped, ok := bValue.(map[string]interface{})
+		if !ok || len(aTyped) != len(bTyped) {
+			return false
+		}
+		for key := range aTyped {
+			if _, ok := bTyped[key]; !ok {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		_, ok := bValue.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
@@ -0,0 +1,162 @@
+package requests
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/stream"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// batchRequest is a single request of a batch. SecurityRequired and MassAssignment are only known when the request was
+// generated from an API specification.
+type batchRequest struct {
+	Params           webscan.RequestParams
+	SecurityRequired *bool
+	MassAssignment   *massAssignmentTarget
+}
+
+// PerformBatchScan sends every request of the batch, using up to concurrency workers, and checks each one for the
+// requested vulnerability types. Requests that do not specify a base URL are sent to baseURL, and the headerParams are
+// sent with every request that does not set the same headers itself. The results are consolidated into a single report
+// in the order of the batch.
+func PerformBatchScan(ctx context.Context, target string, baseURL string, headerParams string, batch []webscan.RequestParams, vulnTypes []string, concurrency int) webscan.RequestBatchReport {
+	report := webscan.RequestBatchReport{
+		Target:  target,
+		BaseUrl: baseURL,
+	}
+	headers, err := parseJSONParams(headerParams)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to parse header parameters: %v", err))
+		return report
+	}
+
+	requests := make([]batchRequest, 0, len(batch))
+	for _, params := range batch {
+		if (params.BaseUrl == nil || *params.BaseUrl == "") && baseURL != "" {
+			params.BaseUrl = &baseURL
+		}
+		if len(headers) > 0 {
+			params.HeaderParams = mergeHeaderParams(headers, params.HeaderParams)
+		}
+		requests = append(requests, batchRequest{Params: params})
+	}
+	runBatch(ctx, &report, requests, vulnTypes, concurrency)
+	return report
+}
+
+// mergeHeaderParams adds the headers to the header parameters of a request, whose own values take precedence. Invalid
+// header parameters are left as they are, so that the request reports them.
+func mergeHeaderParams(headers map[string]string, headerParams string) string {
+	own, err := parseJSONParams(headerParams)
+	if err != nil {
+		return headerParams
+	}
+	merged := make(map[string]string, len(headers)+len(own))
+	for name, value := range headers {
+		merged[name] = value
+	}
+	for name, value := range own {
+		for existing := range merged {
+			if strings.EqualFold(existing, name) {
+				delete(merged, existing)
+			}
+		}
+		merged[name] = value
+	}
+	encoded, err := encodeParams(merged)
+	if err != nil {
+		return headerParams
+	}
+	return encoded
+}
+
+// runBatch sends the requests through a bounded pool of workers and adds their results to the report.
+func runBatch(ctx context.Context, report *webscan.RequestBatchReport, batch []batchRequest, vulnTypes []string, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]*webscan.RequestResult, len(batch))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency && worker < len(batch); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = performBatchRequest(ctx, batch[i], vulnTypes)
+				stream.Emit(ctx, stream.TypeRequest, results[i])
+			}
+		}()
+	}
+
+queue:
+	for i := range batch {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			report.Errors = append(report.Errors, ctx.Err().Error())
+			break queue
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		report.Results = append(report.Results, result)
+		summarizeResult(report, result)
+	}
+}
+
+func performBatchRequest(ctx context.Context, request batchRequest, vulnTypes []string) *webscan.RequestResult {
+	log := svc1log.FromContext(ctx)
+	params := request.Params
+	baseURL, path, method := stringValueOf(params.BaseUrl), stringValueOf(params.Path), strings.ToUpper(stringValueOf(params.Method))
+	route := strings.TrimSpace(method + " " + path)
+
+	headers, _ := parseJSONParams(params.HeaderParams)
+	result := &webscan.RequestResult{
+		Route:            route,
+		SecurityRequired: request.SecurityRequired,
+		CredentialsSent:  len(authHeaderNames(headers)) > 0 || httpclient.FromContext(ctx).Authenticator() != nil,
+	}
+
+	missing := []string{}
+	for name, value := range map[string]string{"baseUrl": baseURL, "path": path, "method": method} {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		result.Outcome = webscan.RequestOutcomeFailed
+		result.Report = &webscan.RequestReport{
+			BaseUrl: baseURL,
+			Path:    path,
+			Errors:  []string{fmt.Sprintf("request is missing %s", strings.Join(missing, ", "))},
+		}
+		return result
+	}
+
+	log.Debug("Sending batch request", svc1log.SafeParam("route", route))
+	requestReport := performRequestScan(ctx, baseURL, path, method, params, vulnTypes, request.MassAssignment)
+	result.Outcome = requestOutcome(&requestReport)
+	result.Report = &requestReport
+	return result
+}
+
+func stringValueOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
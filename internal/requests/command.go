@@ -0,0 +1,139 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const (
+	commandTechniqueTime = "time-based"
+	commandTechniqueEcho = "output-echo"
+)
+
+// commandSeparator chains an injected command after the original one.
+type commandSeparator struct {
+	Prefix string
+	Suffix string
+}
+
+// commandShell describes how to chain, delay and echo commands for one family of shells. Echo takes two halves of a
+// marker and returns a command whose output joins them; the joined marker never appears in the payload itself, so
+// finding it in the response proves the command ran rather than being reflected.
+type commandShell struct {
+	Name       string
+	Separators []commandSeparator
+	Delay      func(seconds int) string
+	Echo       func(first, second string) string
+}
+
+var commandShells = []commandShell{
+	{
+		Name: "sh",
+		Separators: []commandSeparator{
+			{Prefix: ";", Suffix: ";"},
+			{Prefix: "|", Suffix: ""},
+			{Prefix: "&&", Suffix: ""},
+			{Prefix: "\n", Suffix: "\n"},
+			{Prefix: "$(", Suffix: ")"},
+			{Prefix: "`", Suffix: "`"},
+		},
+		Delay: func(seconds int) string { return fmt.Sprintf("sleep %d", seconds) },
+		Echo:  func(first, second string) string { return fmt.Sprintf("echo %s''%s", first, second) },
+	},
+	{
+		Name: "cmd",
+		Separators: []commandSeparator{
+			{Prefix: "&", Suffix: "&"},
+			{Prefix: "|", Suffix: ""},
+			{Prefix: "&&", Suffix: ""},
+			{Prefix: "\r\n", Suffix: "\r\n"},
+		},
+		Delay: func(seconds int) string { return fmt.Sprintf("ping -n %d 127.0.0.1", seconds+1) },
+		Echo:  func(first, second string) string { return fmt.Sprintf("echo %s^%s", first, second) },
+	},
+}
+
+// commandInjectionCheck tests every injection point for OS command injection, first by echoing a marker that has to
+// be assembled by the shell and then, for blind injection, by asking the shell to sleep.
+type commandInjectionCheck struct{}
+
+func (c *commandInjectionCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+
+		finding, errs := c.outputEcho(ctx, s, point)
+		errors = append(errors, errs...)
+		if finding == nil {
+			finding, errs = c.timeBased(ctx, s, point)
+			errors = append(errors, errs...)
+		}
+		if finding != nil {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, errors
+}
+
+func (c *commandInjectionCheck) outputEcho(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, shell := range commandShells {
+		for _, separator := range shell.Separators {
+			first, second := newCanary(), newCanary()
+			marker := first + second
+			payload := separator.Prefix + shell.Echo(first, second) + separator.Suffix
+
+			resp, err := s.sendPayload(ctx, point, point.Value+payload)
+			if err != nil {
+				errors = append(errors, probeError(webscan.VulnTypeCommand, point, err))
+				continue
+			}
+			index := bytes.Index(resp.Body, []byte(marker))
+			if index < 0 {
+				continue
+			}
+
+			evidence := fmt.Sprintf("%s echoed the assembled marker %s: %s", shell.Name, marker, strings.TrimSpace(excerpt(resp.Body, index, len(marker), 40)))
+			finding := newFinding(webscan.VulnTypeCommand, commandTechniqueEcho, point, payload, evidence, webscan.ConfidenceHigh, resp)
+			engine := shell.Name
+			finding.Engine = &engine
+			return finding, errors
+		}
+	}
+	return nil, errors
+}
+
+func (c *commandInjectionCheck) timeBased(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, shell := range commandShells {
+		for _, separator := range shell.Separators {
+			payload := separator.Prefix + shell.Delay(sleepSeconds) + separator.Suffix
+			control := separator.Prefix + shell.Delay(0) + separator.Suffix
+
+			delay, err := s.confirmDelay(ctx, point, point.Value+payload, point.Value+control)
+			if err != nil {
+				errors = append(errors, probeError(webscan.VulnTypeCommand, point, err))
+				continue
+			}
+			if delay == nil {
+				continue
+			}
+
+			finding := newFinding(webscan.VulnTypeCommand, commandTechniqueTime, point, payload, delay.describe(s.baseline), webscan.ConfidenceHigh, delay.Delayed)
+			engine := shell.Name
+			finding.Engine = &engine
+			return finding, errors
+		}
+	}
+	return nil, errors
+}
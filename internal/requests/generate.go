@@ -0,0 +1,288 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+// maxSchemaDepth bounds how deep nested objects and arrays are generated, guarding against recursive schemas.
+const maxSchemaDepth = 8
+
+var pathParamPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// routeRequestParams builds request parameters for a route discovered from an API specification. Path and query
+// parameter values are derived from their names, the JSON body is generated from the route's request schema and the
+// provided headers are sent with every request.
+func routeRequestParams(route *webscan.Route, headerParams string) (webscan.RequestParams, error) {
+	params := webscan.RequestParams{HeaderParams: headerParams}
+
+	pathParams := make(map[string]string)
+	for _, match := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+		pathParams[match[1]] = valueForName(match[1])
+	}
+	queryParams := make(map[string]string)
+	for _, name := range route.QueryParams {
+		queryParams[name] = valueForName(name)
+	}
+
+	var err error
+	if params.PathParams, err = encodeParams(pathParams); err != nil {
+		return params, err
+	}
+	if params.QueryParams, err = encodeParams(queryParams); err != nil {
+		return params, err
+	}
+
+	if route.RequestSchema != nil {
+		body, err := json.Marshal(generateSchemaValue(route.RequestSchema, 0))
+		if err != nil {
+			return params, fmt.Errorf("failed to encode generated request body: %v", err)
+		}
+		params.BodyParams = string(body)
+	}
+	return params, nil
+}
+
+func encodeParams(params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode generated parameters: %v", err)
+	}
+	return string(encoded), nil
+}
+
+// valueForName guesses a plausible value for a parameter that has no schema from its name.
+func valueForName(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.Contains(lower, "uuid") || strings.Contains(lower, "guid"):
+		return "00000000-0000-4000-8000-000000000001"
+	case strings.HasSuffix(lower, "id") || strings.Contains(lower, "count") ||
+		strings.Contains(lower, "page") || strings.Contains(lower, "limit") || strings.Contains(lower, "size") ||
+		strings.Contains(lower, "offset") || strings.Contains(lower, "number"):
+		return "1"
+	case strings.Contains(lower, "email"):
+		return "user@example.com"
+	case strings.Contains(lower, "date") || strings.Contains(lower, "time") || strings.Contains(lower, "since"):
+		return "2024-01-01T00:00:00Z"
+	case strings.Contains(lower, "url") || strings.Contains(lower, "uri"):
+		return "https://example.com"
+	default:
+		return "test"
+	}
+}
+
+// generateSchemaValue produces a value that satisfies the schema, preferring the example, enum and default values
+// declared by the specification over values derived from the type, format and constraints.
+func generateSchemaValue(schema *webscan.RequestSchema, depth int) interface{} {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if schema.Default != nil {
+		return typedValue(*schema.Default, schema.Type)
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, part := range schema.AllOf {
+			if object, ok := generateSchemaValue(part, depth+1).(map[string]interface{}); ok {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return generateSchemaValue(schema.OneOf[0], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return generateSchemaValue(schema.AnyOf[0], depth+1)
+	}
+
+	switch schemaType(schema.Type, schema.Properties != nil, schema.Items != nil) {
+	case "object":
+		object := make(map[string]interface{})
+		for _, property := range schema.Properties {
+			object[property.Name] = generatePropertyValue(property, depth+1)
+		}
+		return object
+	case "array":
+		item := generateSchemaValue(schema.Items, depth+1)
+		count := 1
+		if schema.MinItems != nil && *schema.MinItems > count {
+			count = *schema.MinItems
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			items[i] = item
+		}
+		return items
+	case "integer":
+		return int64(numberValue(schema.Minimum, schema.Maximum, schema.ExclusiveMinimum, schema.ExclusiveMaximum, 1))
+	case "number":
+		return numberValue(schema.Minimum, schema.Maximum, schema.ExclusiveMinimum, schema.ExclusiveMaximum, 1.5)
+	case "boolean":
+		return true
+	case "string":
+		return stringValue(schema.Format, schema.MinLength, schema.MaxLength)
+	default:
+		return nil
+	}
+}
+
+// generatePropertyValue produces a value for an object property following the same precedence as
+// generateSchemaValue.
+func generatePropertyValue(property *webscan.SchemaProperty, depth int) interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+	if property.Example != nil {
+		return typedValue(*property.Example, property.Type)
+	}
+	if len(property.Enum) > 0 {
+		return typedValue(property.Enum[0], property.Type)
+	}
+	if property.Default != nil {
+		return typedValue(*property.Default, property.Type)
+	}
+
+	switch schemaType(property.Type, property.Properties != nil, property.Items != nil) {
+	case "object":
+		object := make(map[string]interface{})
+		for _, nested := range property.Properties {
+			object[nested.Name] = generatePropertyValue(nested, depth+1)
+		}
+		if property.AdditionalProperties != nil && len(object) == 0 {
+			object["key"] = generateSchemaValue(property.AdditionalProperties, depth+1)
+		}
+		return object
+	case "array":
+		return []interface{}{generateSchemaValue(property.Items, depth+1)}
+	case "integer":
+		return int64(numberValue(property.Minimum, property.Maximum, nil, nil, 1))
+	case "number":
+		return numberValue(property.Minimum, property.Maximum, nil, nil, 1.5)
+	case "boolean":
+		return true
+	case "string":
+		return stringValue(property.Format, property.MinLength, property.MaxLength)
+	default:
+		return nil
+	}
+}
+
+// schemaType returns the first concrete type of a schema, inferring object and array types when the type is omitted.
+func schemaType(types []string, hasProperties bool, hasItems bool) string {
+	for _, t := range types {
+		if t == "circular_reference" {
+			return ""
+		}
+		if t != "null" {
+			return t
+		}
+	}
+	switch {
+	case hasProperties:
+		return "object"
+	case hasItems:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
+// typedValue converts a value the specification stores as a string back to the type of the schema.
+func typedValue(value string, types []string) interface{} {
+	switch schemaType(types, false, false) {
+	case "integer":
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	case "number":
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	case "boolean":
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	case "object", "array":
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+// numberValue picks fallback when it lies within the bounds, otherwise the nearest value that does.
+func numberValue(minimum, maximum *float64, exclusiveMinimum, exclusiveMaximum *bool, fallback float64) float64 {
+	value := fallback
+	if minimum != nil && value < *minimum {
+		value = *minimum
+		if exclusiveMinimum != nil && *exclusiveMinimum {
+			value = math.Floor(value) + 1
+		}
+	}
+	if maximum != nil && value > *maximum {
+		value = *maximum
+		if exclusiveMaximum != nil && *exclusiveMaximum {
+			value = math.Ceil(value) - 1
+		}
+	}
+	return value
+}
+
+// stringValue returns a sample string for the format, padded or truncated to satisfy the length constraints.
+func stringValue(format *string, minLength, maxLength *int) string {
+	value := "test"
+	if format != nil {
+		switch *format {
+		case "date-time":
+			value = "2024-01-01T00:00:00Z"
+		case "date":
+			value = "2024-01-01"
+		case "time":
+			value = "00:00:00"
+		case "email":
+			value = "user@example.com"
+		case "uuid":
+			value = "00000000-0000-4000-8000-000000000001"
+		case "uri", "url":
+			value = "https://example.com"
+		case "hostname":
+			value = "example.com"
+		case "ipv4":
+			value = "192.0.2.1"
+		case "ipv6":
+			value = "2001:db8::1"
+		case "byte":
+			value = "dGVzdA=="
+		case "password":
+			value = "Password123!"
+		}
+	}
+	if minLength != nil && len(value) < *minLength {
+		value += strings.Repeat("a", *minLength-len(value))
+	}
+	if maxLength != nil && *maxLength >= 0 && len(value) > *maxLength {
+		value = value[:*maxLength]
+	}
+	return value
+}
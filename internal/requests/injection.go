@@ -0,0 +1,241 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+// injectionPoint identifies a single parameter of the request that payloads can be injected into. For JSON bodies every
+// leaf value is its own injection point, addressed by jsonPath and named with a dotted path such as user.roles[0].
+type injectionPoint struct {
+	Location webscan.ParamLocation
+	Name     string
+	Value    string
+	jsonPath []interface{}
+}
+
+// collectInjectionPoints lists every parameter of the request in a stable order: path, query, header, JSON body, form
+// and multipart parameters. The excluded headers are left out.
+func collectInjectionPoints(params webscan.ParsedParams, excludedHeaders []string) []injectionPoint {
+	headers := cloneMap(params.HeaderParams)
+	for _, name := range excludedHeaders {
+		delete(headers, name)
+	}
+	points := []injectionPoint{}
+	points = append(points, mapInjectionPoints(webscan.ParamLocationPath, params.PathParams)...)
+	points = append(points, mapInjectionPoints(webscan.ParamLocationQuery, params.QueryParams)...)
+	points = append(points, mapInjectionPoints(webscan.ParamLocationHeader, headers)...)
+	if params.BodyParams != "" {
+		var body interface{}
+		if err := json.Unmarshal([]byte(params.BodyParams), &body); err == nil {
+			points = append(points, jsonInjectionPoints(body, nil)...)
+		}
+	}
+	points = append(points, mapInjectionPoints(webscan.ParamLocationForm, params.FormParams)...)
+	points = append(points, mapInjectionPoints(webscan.ParamLocationMultipart, params.MultipartParams)...)
+	return points
+}
+
+func mapInjectionPoints(location webscan.ParamLocation, params map[string]string) []injectionPoint {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	points := make([]injectionPoint, 0, len(keys))
+	for _, key := range keys {
+		points = append(points, injectionPoint{Location: location, Name: key, Value: params[key]})
+	}
+	return points
+}
+
+func jsonInjectionPoints(node interface{}, path []interface{}) []injectionPoint {
+	points := []injectionPoint{}
+	switch v := node.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			points = append(points, jsonInjectionPoints(v[key], appendPath(path, key))...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			points = append(points, jsonInjectionPoints(item, appendPath(path, i))...)
+		}
+	default:
+		if len(path) == 0 {
+			return points
+		}
+		value := ""
+		switch leaf := v.(type) {
+		case string:
+			value = leaf
+		case nil:
+			value = ""
+		default:
+			encoded, _ := json.Marshal(leaf)
+			value = string(encoded)
+		}
+		points = append(points, injectionPoint{Location: webscan.ParamLocationBody, Name: jsonPathName(path), Value: value, jsonPath: path})
+	}
+	return points
+}
+
+func appendPath(path []interface{}, element interface{}) []interface{} {
+	next := make([]interface{}, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, element)
+}
+
+func jsonPathName(path []interface{}) string {
+	var b strings.Builder
+	for _, element := range path {
+		switch e := element.(type) {
+		case int:
+			b.WriteString("[" + strconv.Itoa(e) + "]")
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(e)
+		}
+	}
+	return b.String()
+}
+
+// inject returns a copy of params with the injection point's value replaced by value.
+func (p injectionPoint) inject(params webscan.ParsedParams, value string) (webscan.ParsedParams, error) {
+	return p.injectValue(params, value)
+}
+
+// injectValue returns a copy of params with the injection point's value replaced by value. Values other than strings
+// are only supported for JSON body injection points, where they are written into the document as JSON.
+func (p injectionPoint) injectValue(params webscan.ParsedParams, value interface{}) (webscan.ParsedParams, error) {
+	injected := cloneParams(params)
+	if p.Location == webscan.ParamLocationBody {
+		body, err := setJSONValue(params.BodyParams, p.jsonPath, value)
+		if err != nil {
+			return injected, err
+		}
+		injected.BodyParams = body
+		return injected, nil
+	}
+
+	stringValue, ok := value.(string)
+	if !ok {
+		return injected, fmt.Errorf("cannot inject a %T value into a %s parameter", value, p.Location)
+	}
+	switch p.Location {
+	case webscan.ParamLocationPath:
+		injected.PathParams[p.Name] = stringValue
+	case webscan.ParamLocationQuery:
+		injected.QueryParams[p.Name] = stringValue
+	case webscan.ParamLocationHeader:
+		injected.HeaderParams[p.Name] = stringValue
+	case webscan.ParamLocationForm:
+		injected.FormParams[p.Name] = stringValue
+	case webscan.ParamLocationMultipart:
+		injected.MultipartParams[p.Name] = stringValue
+	default:
+		return injected, fmt.Errorf("unsupported parameter location: %s", p.Location)
+	}
+	return injected, nil
+}
+
+// injectOperator returns a copy of params where the injection point's value is replaced by a query operator applied to
+// operand. JSON body values become an operator object such as {"$ne": "x"}, while query and form parameters are renamed
+// using the bracket syntax that qs-style parsers turn into the same object, such as name[$ne]=x.
+func (p injectionPoint) injectOperator(params webscan.ParsedParams, operator string, operand string) (webscan.ParsedParams, error) {
+	if p.Location == webscan.ParamLocationBody {
+		return p.injectValue(params, map[string]interface{}{operator: operand})
+	}
+
+	injected := cloneParams(params)
+	var target map[string]string
+	switch p.Location {
+	case webscan.ParamLocationQuery:
+		target = injected.QueryParams
+	case webscan.ParamLocationForm:
+		target = injected.FormParams
+	default:
+		return injected, fmt.Errorf("cannot inject an operator into a %s parameter", p.Location)
+	}
+	delete(target, p.Name)
+	target[p.Name+"["+operator+"]"] = operand
+	return injected, nil
+}
+
+func cloneParams(params webscan.ParsedParams) webscan.ParsedParams {
+	return webscan.ParsedParams{
+		PathParams:      cloneMap(params.PathParams),
+		QueryParams:     cloneMap(params.QueryParams),
+		HeaderParams:    cloneMap(params.HeaderParams),
+		BodyParams:      params.BodyParams,
+		FormParams:      cloneMap(params.FormParams),
+		MultipartParams: cloneMap(params.MultipartParams),
+	}
+}
+
+func cloneMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	cloned := make(map[string]string, len(m))
+	for key, value := range m {
+		cloned[key] = value
+	}
+	return cloned
+}
+
+// setJSONValue decodes the JSON document, replaces the leaf addressed by path with value and re-encodes it.
+func setJSONValue(document string, path []interface{}, value interface{}) (string, error) {
+	var root interface{}
+	if err := json.Unmarshal([]byte(document), &root); err != nil {
+		return "", fmt.Errorf("failed to parse body parameters: %v", err)
+	}
+	if len(path) == 0 {
+		return "", fmt.Errorf("empty JSON path")
+	}
+
+	parent := root
+	for i, element := range path {
+		last := i == len(path)-1
+		switch e := element.(type) {
+		case string:
+			object, ok := parent.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("JSON path %s does not address an object", jsonPathName(path[:i+1]))
+			}
+			if last {
+				object[e] = value
+			} else {
+				parent = object[e]
+			}
+		case int:
+			array, ok := parent.([]interface{})
+			if !ok || e >= len(array) {
+				return "", fmt.Errorf("JSON path %s does not address an array element", jsonPathName(path[:i+1]))
+			}
+			if last {
+				array[e] = value
+			} else {
+				parent = array[e]
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode body parameters: %v", err)
+	}
+	return string(encoded), nil
+}
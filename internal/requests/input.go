@@ -0,0 +1,273 @@
+package requests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+// jsonParamFields are the RequestParams fields that hold a JSON object encoded as a string.
+var jsonParamFields = map[string]bool{
+	"pathParams":      true,
+	"queryParams":     true,
+	"headerParams":    true,
+	"formParams":      true,
+	"multipartParams": true,
+}
+
+// LoadRequestsFile reads a JSONL file with one RequestParams object per line. Blank lines and lines starting with #
+// are skipped. Besides the JSON encoded strings RequestParams uses, parameters may also be given as plain JSON objects.
+func LoadRequestsFile(path string) ([]webscan.RequestParams, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open requests file %s: %v", path, err)
+	}
+	defer file.Close()
+
+	batch := []webscan.RequestParams{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		params, err := parseRequestLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse line %d of %s: %v", lineNumber, path, err)
+		}
+		batch = append(batch, params)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read requests file %s: %v", path, err)
+	}
+	return batch, nil
+}
+
+func parseRequestLine(line []byte) (webscan.RequestParams, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return webscan.RequestParams{}, err
+	}
+
+	// Re-encode object and array values as the JSON strings RequestParams expects
+	for name, value := range fields {
+		trimmed := bytes.TrimSpace(value)
+		isStructured := len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+		if isStructured && (jsonParamFields[name] || name == "bodyParams") {
+			encoded, err := json.Marshal(string(trimmed))
+			if err != nil {
+				return webscan.RequestParams{}, err
+			}
+			fields[name] = encoded
+		}
+	}
+
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return webscan.RequestParams{}, err
+	}
+	var params webscan.RequestParams
+	if err := json.Unmarshal(normalized, &params); err != nil {
+		return webscan.RequestParams{}, err
+	}
+	return params, nil
+}
+
+// burpItems is the XML document Burp Suite writes when items are saved from the proxy history.
+type burpItems struct {
+	Items []struct {
+		Protocol string `xml:"protocol"`
+		Host     string `xml:"host"`
+		Port     string `xml:"port"`
+		Request  struct {
+			Base64 string `xml:"base64,attr"`
+			Raw    string `xml:",chardata"`
+		} `xml:"request"`
+	} `xml:"item"`
+}
+
+// LoadRawRequestFile reads HTTP requests in wire format, such as those copied from Burp Suite or a proxy log. A file
+// may contain several requests one after another, or be a Burp Suite XML export of saved items. Requests are sent to
+// baseURL when it is provided, otherwise to the scheme and host recorded in the file.
+func LoadRawRequestFile(path string, baseURL string) ([]webscan.RequestParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raw request file %s: %v", path, err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<items")) {
+		return parseBurpItems(trimmed, baseURL)
+	}
+
+	batch := []webscan.RequestParams{}
+	// Terminate the headers of a final request that has no body
+	reader := bufio.NewReader(bytes.NewReader(normalizeLineEndings(append(trimmed, "\n\n"...))))
+	for {
+		// Separate consecutive requests by any number of blank lines
+		for {
+			next, err := reader.Peek(1)
+			if err != nil || (next[0] != '\n' && next[0] != '\r') {
+				break
+			}
+			_, _ = reader.ReadByte()
+		}
+		if _, err := reader.Peek(1); err == io.EOF {
+			break
+		}
+
+		req, err := http.ReadRequest(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse request %d of %s: %v", len(batch)+1, path, err)
+		}
+		params, err := rawRequestParams(req, baseURL, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert request %d of %s: %v", len(batch)+1, path, err)
+		}
+		batch = append(batch, params)
+	}
+	return batch, nil
+}
+
+func parseBurpItems(data []byte, baseURL string) ([]webscan.RequestParams, error) {
+	var items burpItems
+	if err := xml.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse Burp Suite XML: %v", err)
+	}
+
+	batch := []webscan.RequestParams{}
+	for i, item := range items.Items {
+		raw := []byte(item.Request.Raw)
+		if item.Request.Base64 == "true" {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(item.Request.Raw))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode Burp Suite item %d: %v", i+1, err)
+			}
+			raw = decoded
+		}
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(normalizeLineEndings(raw))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Burp Suite item %d: %v", i+1, err)
+		}
+
+		itemBaseURL := ""
+		if item.Protocol != "" && item.Host != "" {
+			itemBaseURL = fmt.Sprintf("%s://%s", item.Protocol, item.Host)
+			if item.Port != "" && !(item.Protocol == "https" && item.Port == "443") && !(item.Protocol == "http" && item.Port == "80") {
+				itemBaseURL += ":" + item.Port
+			}
+		}
+		params, err := rawRequestParams(req, baseURL, itemBaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert Burp Suite item %d: %v", i+1, err)
+		}
+		batch = append(batch, params)
+	}
+	return batch, nil
+}
+
+// normalizeLineEndings converts bare LF line endings, common in hand written or copied requests, to the CRLF line
+// endings the HTTP parser accepts in either form.
+func normalizeLineEndings(data []byte) []byte {
+	return bytes.ReplaceAll(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), []byte("\n"), []byte("\r\n"))
+}
+
+// rawRequestParams converts a parsed wire-format request into RequestParams. The target is baseURL when provided, then
+// recordedBaseURL, and otherwise https on the request's Host header.
+func rawRequestParams(req *http.Request, baseURL string, recordedBaseURL string) (webscan.RequestParams, error) {
+	params := webscan.RequestParams{}
+	defer req.Body.Close()
+
+	target := baseURL
+	if target == "" {
+		target = recordedBaseURL
+	}
+	if target == "" {
+		if req.Host == "" {
+			return params, fmt.Errorf("request has no Host header and no base URL was provided")
+		}
+		target = "https://" + req.Host
+	}
+	path, method := req.URL.Path, req.Method
+	params.BaseUrl, params.Path, params.Method = &target, &path, &method
+
+	query := make(map[string]string)
+	for key, values := range req.URL.Query() {
+		query[key] = values[0]
+	}
+	headers := make(map[string]string)
+	for key, values := range req.Header {
+		switch http.CanonicalHeaderKey(key) {
+		case "Content-Type", "Content-Length", "Connection", "Accept-Encoding":
+			continue
+		}
+		headers[key] = strings.Join(values, ", ")
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return params, fmt.Errorf("failed to read request body: %v", err)
+	}
+	mediaType, mediaParams, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	form := make(map[string]string)
+	multipartForm := make(map[string]string)
+	switch {
+	case len(body) == 0:
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return params, fmt.Errorf("failed to parse form body: %v", err)
+		}
+		for key, value := range values {
+			form[key] = value[0]
+		}
+	case mediaType == "multipart/form-data":
+		reader := multipart.NewReader(bytes.NewReader(body), mediaParams["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return params, fmt.Errorf("failed to parse multipart body: %v", err)
+			}
+			value, err := io.ReadAll(part)
+			if err != nil {
+				return params, fmt.Errorf("failed to read multipart field %s: %v", part.FormName(), err)
+			}
+			multipartForm[part.FormName()] = string(value)
+		}
+	default:
+		params.BodyParams = string(body)
+	}
+
+	for _, field := range []struct {
+		target *string
+		values map[string]string
+	}{
+		{&params.QueryParams, query},
+		{&params.HeaderParams, headers},
+		{&params.FormParams, form},
+		{&params.MultipartParams, multipartForm},
+	} {
+		encoded, err := encodeParams(field.values)
+		if err != nil {
+			return params, err
+		}
+		*field.target = encoded
+	}
+	return params, nil
+}
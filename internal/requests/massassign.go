@@ -0,0 +1,426 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const (
+	massAssignmentTechniquePersisted = "persisted"
+	massAssignmentTechniqueReflected = "reflected"
+
+	// massAssignmentNumber is injected into numeric properties, chosen to be unlikely to match an existing value.
+	massAssignmentNumber = 31337
+)
+
+// privilegedProperties are undocumented properties that commonly control privileges, ownership or billing and are
+// injected into every write request in addition to those derived from the API specification.
+var privilegedProperties = []string{
+	"accountType", "account_type", "active", "admin", "approved", "balance", "credits", "emailVerified",
+	"email_verified", "isAdmin", "isStaff", "isVerified", "is_admin", "is_staff", "is_superuser", "ownerId",
+	"owner_id", "permissions", "plan", "role", "roles", "tier", "userId", "user_id", "verified",
+}
+
+var (
+	booleanPropertyPattern = regexp.MustCompile(`^(?:is|has|can)(?:[A-Z_]|$)|(?i:admin|verified|active|enabled|approved|confirmed|staff|superuser)`)
+	rolePropertyPattern    = regexp.MustCompile(`(?i)role|group|permission|scope|type`)
+	planPropertyPattern    = regexp.MustCompile(`(?i)tier|plan|subscription`)
+	numberPropertyPattern  = regexp.MustCompile(`(?i)balance|credit|amount|price|quota|limit|level|points|(?:^|[-_a-z])id$`)
+	createdIDProperties    = []string{"id", "_id", "uuid"}
+)
+
+// massAssignmentTarget describes, for a write route of an API specification, the properties the server returns but
+// does not accept and the route that reads the written resource back.
+type massAssignmentTarget struct {
+	Properties []string
+	// ReadPath is the path of the route that reads the resource back, empty when the specification has none.
+	ReadPath string
+	// IDParam is the path parameter of ReadPath that is filled with the ID of a created resource.
+	IDParam string
+}
+
+// massAssignmentCheck injects read-only and undocumented properties into write requests and reads the resource back
+// to see whether their values persisted. All candidate properties are injected at once, and one at a time when the
+// server rejects the combined request.
+type massAssignmentCheck struct{}
+
+func (c *massAssignmentCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	switch s.method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return nil, nil
+	}
+
+	location, sent := massAssignmentLocation(s.params)
+	if location == "" {
+		return nil, nil
+	}
+	values := make(map[string]interface{})
+	canary := newCanary()
+	for _, property := range massAssignmentCandidates(s.massAssignment) {
+		if !sent[property] {
+			values[property] = massAssignmentValue(property, canary)
+		}
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	before := c.readBack(ctx, s, s.baseline)
+	if before == nil {
+		before = parseJSONBody(s.baseline.Body)
+	}
+
+	params, err := injectProperties(s.params, location, values)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("%s probe failed: %v", webscan.VulnTypeMassassignment, err)}
+	}
+	resp, err := s.send(ctx, params)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("%s probe failed: %v", webscan.VulnTypeMassassignment, err)}
+	}
+	if isSuccess(resp.StatusCode) {
+		return c.evaluate(ctx, s, location, values, before, resp), nil
+	}
+
+	// The combined request was rejected, e.g. by strict validation of one property, so try each on its own
+	findings := []*webscan.VulnFinding{}
+	errs := []string{}
+	for _, property := range sortedKeys(values) {
+		single := map[string]interface{}{property: values[property]}
+		params, err := injectProperties(s.params, location, single)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s probe of %s failed: %v", webscan.VulnTypeMassassignment, property, err))
+			continue
+		}
+		resp, err := s.send(ctx, params)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s probe of %s failed: %v", webscan.VulnTypeMassassignment, property, err))
+			continue
+		}
+		if isSuccess(resp.StatusCode) {
+			findings = append(findings, c.evaluate(ctx, s, location, single, before, resp)...)
+		}
+	}
+	return findings, errs
+}
+
+// evaluate reads the resource written by resp back and reports every injected property whose value persisted. When the
+// resource cannot be read back, properties echoed by the write response itself are reported with low confidence.
+func (c *massAssignmentCheck) evaluate(ctx context.Context, s *scanner, location webscan.ParamLocation, values map[string]interface{}, before interface{}, resp *response) []*webscan.VulnFinding {
+	findings := []*webscan.VulnFinding{}
+	after := c.readBack(ctx, s, resp)
+	technique, source := massAssignmentTechniquePersisted, "read back"
+	if after == nil {
+		after, technique, source = parseJSONBody(resp.Body), massAssignmentTechniqueReflected, "write response"
+	}
+
+	for _, property := range sortedKeys(values) {
+		injected := values[property]
+		observed, ok := findProperty(after, property)
+		if !ok || !sameValue(observed, injected) {
+			continue
+		}
+		previous, known := findProperty(before, property)
+		if known && sameValue(previous, injected) {
+			continue
+		}
+
+		confidence := webscan.ConfidenceHigh
+		if technique == massAssignmentTechniqueReflected {
+			confidence = webscan.ConfidenceLow
+		} else if _, isBool := injected.(bool); isBool && !known {
+			// An injected boolean may match a default the resource had all along
+			confidence = webscan.ConfidenceMedium
+		}
+		payload := describeJSON(injected)
+		evidence := fmt.Sprintf("%s=%s was accepted and returned by the %s", property, payload, source)
+		if known {
+			evidence += fmt.Sprintf(", previously %s", describeJSON(previous))
+		}
+		point := injectionPoint{Location: location, Name: property}
+		findings = append(findings, newFinding(webscan.VulnTypeMassassignment, technique, point, payload, evidence, confidence, resp))
+	}
+	return findings
+}
+
+// readBack fetches the resource written by a request that returned resp. A created resource is located through the
+// Location header or the ID in the response and the read route of the specification, while an updated resource is
+// read from its own path. Nil is returned when the resource cannot be located or read.
+func (c *massAssignmentCheck) readBack(ctx context.Context, s *scanner, resp *response) interface{} {
+	path := ""
+	pathParams := cloneMap(s.params.PathParams)
+	if pathParams == nil {
+		pathParams = make(map[string]string)
+	}
+	target := s.massAssignment
+
+	switch {
+	case s.method == http.MethodPost && resp.Headers.Get("Location") != "":
+		location, err := url.Parse(resp.Headers.Get("Location"))
+		if err != nil {
+			return nil
+		}
+		path, pathParams = location.Path, nil
+	case s.method == http.MethodPost:
+		id := createdID(resp.Body)
+		if id == "" {
+			return nil
+		}
+		if target != nil && target.ReadPath != "" && target.IDParam != "" {
+			path = target.ReadPath
+			pathParams[target.IDParam] = id
+		} else {
+			path = strings.TrimSuffix(s.path, "/") + "/{createdId}"
+			pathParams["createdId"] = id
+		}
+	case target != nil && target.ReadPath != "":
+		path = target.ReadPath
+	default:
+		path = s.path
+	}
+
+	params := webscan.ParsedParams{PathParams: pathParams, HeaderParams: s.params.HeaderParams}
+	read, err := executeRequest(ctx, http.MethodGet, s.baseURL, path, params)
+	if err != nil || !isSuccess(read.StatusCode) {
+		return nil
+	}
+	return parseJSONBody(read.Body)
+}
+
+// newMassAssignmentTarget derives the mass assignment target of a write route from the routes of its specification:
+// the GET route for the same resource, or for POST the route of a single created resource, and the properties that
+// route returns but the write route does not accept.
+func newMassAssignmentTarget(route *webscan.Route, routes []*webscan.Route) *massAssignmentTarget {
+	method := strings.ToUpper(route.Method)
+	if method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch {
+		return nil
+	}
+
+	target := &massAssignmentTarget{}
+	var readRoute *webscan.Route
+	for _, candidate := range routes {
+		if candidate == nil || strings.ToUpper(candidate.Method) != http.MethodGet {
+			continue
+		}
+		if method != http.MethodPost && candidate.Path == route.Path {
+			readRoute = candidate
+			target.ReadPath = candidate.Path
+			break
+		}
+		if method == http.MethodPost {
+			prefix := strings.TrimSuffix(route.Path, "/") + "/{"
+			if strings.HasPrefix(candidate.Path, prefix) && strings.HasSuffix(candidate.Path, "}") && !strings.Contains(candidate.Path[len(prefix):], "/") {
+				readRoute = candidate
+				target.ReadPath = candidate.Path
+				target.IDParam = strings.TrimSuffix(candidate.Path[len(prefix):], "}")
+				break
+			}
+		}
+	}
+
+	accepted := make(map[string]bool)
+	collectSchemaProperties(route.RequestSchema, accepted, 0)
+	candidates := make(map[string]bool)
+	if readRoute != nil {
+		for status, properties := range readRoute.ResponseProperties {
+			if !strings.HasPrefix(status, "2") && status != "default" {
+				continue
+			}
+			for _, property := range properties {
+				candidates[property] = true
+			}
+		}
+	}
+	for _, property := range privilegedProperties {
+		candidates[property] = true
+	}
+	for property := range candidates {
+		if !accepted[property] {
+			target.Properties = append(target.Properties, property)
+		}
+	}
+	sort.Strings(target.Properties)
+	return target
+}
+
+// collectSchemaProperties adds the names of the properties accepted by the request schema, including those of its
+// allOf, oneOf and anyOf parts, to accepted.
+func collectSchemaProperties(schema *webscan.RequestSchema, accepted map[string]bool, depth int) {
+	if schema == nil || depth > maxSchemaDepth {
+		return
+	}
+	for _, property := range schema.Properties {
+		if property != nil {
+			accepted[property.Name] = true
+		}
+	}
+	for _, parts := range [][]*webscan.RequestSchema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, part := range parts {
+			collectSchemaProperties(part, accepted, depth+1)
+		}
+	}
+}
+
+// massAssignmentCandidates returns the properties to inject: those of the route's target when it was derived from an
+// API specification, and the common privileged properties otherwise.
+func massAssignmentCandidates(target *massAssignmentTarget) []string {
+	if target != nil {
+		return target.Properties
+	}
+	return privilegedProperties
+}
+
+// massAssignmentLocation returns where properties can be added to the request, a JSON object body or form parameters,
+// along with the properties the request already sends.
+func massAssignmentLocation(params webscan.ParsedParams) (webscan.ParamLocation, map[string]bool) {
+	sent := make(map[string]bool)
+	if params.BodyParams != "" {
+		var body map[string]interface{}
+		if err := json.Unmarshal([]byte(params.BodyParams), &body); err != nil || body == nil {
+			return "", nil
+		}
+		for key := range body {
+			sent[key] = true
+		}
+		return webscan.ParamLocationBody, sent
+	}
+	if len(params.FormParams) > 0 {
+		for key := range params.FormParams {
+			sent[key] = true
+		}
+		return webscan.ParamLocationForm, sent
+	}
+	if len(params.MultipartParams) == 0 {
+		// A write request without a body gets a JSON body holding only the injected properties
+		return webscan.ParamLocationBody, sent
+	}
+	return "", nil
+}
+
+// massAssignmentValue picks a value for the property from its name: true for flags, an administrative role, a premium
+// plan, a distinctive number for quantities and IDs, and the canary for anything else.
+func massAssignmentValue(property string, canary string) interface{} {
+	switch {
+	case booleanPropertyPattern.MatchString(property):
+		return true
+	case rolePropertyPattern.MatchString(property):
+		if strings.HasSuffix(property, "s") {
+			return []interface{}{"admin"}
+		}
+		return "admin"
+	case planPropertyPattern.MatchString(property):
+		return "enterprise"
+	case numberPropertyPattern.MatchString(property):
+		return massAssignmentNumber
+	default:
+		return canary
+	}
+}
+
+// injectProperties adds the properties to the request's JSON body or form parameters.
+func injectProperties(params webscan.ParsedParams, location webscan.ParamLocation, values map[string]interface{}) (webscan.ParsedParams, error) {
+	injected := cloneParams(params)
+	if location == webscan.ParamLocationForm {
+		for property, value := range values {
+			if list, ok := value.([]interface{}); ok && len(list) > 0 {
+				value = list[0]
+			}
+			injected.FormParams[property] = fmt.Sprint(value)
+		}
+		return injected, nil
+	}
+
+	body := make(map[string]interface{})
+	if params.BodyParams != "" {
+		if err := json.Unmarshal([]byte(params.BodyParams), &body); err != nil {
+			return params, fmt.Errorf("failed to parse JSON body: %v", err)
+		}
+	}
+	for property, value := range values {
+		body[property] = value
+	}
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return params, fmt.Errorf("failed to encode JSON body: %v", err)
+	}
+	injected.BodyParams = string(encoded)
+	return injected, nil
+}
+
+// findProperty searches a decoded JSON document breadth first for the named property, so that resources wrapped in an
+// envelope such as {"data": {...}} are found as well.
+func findProperty(document interface{}, name string) (interface{}, bool) {
+	queue := []interface{}{document}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		switch typed := node.(type) {
+		case map[string]interface{}:
+			if value, ok := typed[name]; ok {
+				return value, true
+			}
+			for _, key := range sortedKeys(typed) {
+				queue = append(queue, typed[key])
+			}
+		case []interface{}:
+			queue = append(queue, typed...)
+		}
+	}
+	return nil, false
+}
+
+// createdID returns the ID of the resource described by a response body, if it has one.
+func createdID(body []byte) string {
+	document := parseJSONBody(body)
+	for _, name := range createdIDProperties {
+		if value, ok := findProperty(document, name); ok && value != nil {
+			switch value.(type) {
+			case map[string]interface{}, []interface{}:
+				continue
+			}
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
+}
+
+// sameValue compares JSON values loosely, so that a number stored as a string or a single role stored as a list
+// still match.
+func sameValue(a interface{}, b interface{}) bool {
+	if fmt.Sprint(a) == fmt.Sprint(b) {
+		return true
+	}
+	if list, ok := a.([]interface{}); ok && len(list) == 1 {
+		return sameValue(list[0], b)
+	}
+	if list, ok := b.([]interface{}); ok && len(list) == 1 {
+		return sameValue(a, list[0])
+	}
+	return false
+}
+
+func parseJSONBody(body []byte) interface{} {
+	var document interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil
+	}
+	return document
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,207 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const (
+	nosqlTechniqueAuthBypass   = "authentication-bypass"
+	nosqlTechniqueDisclosure   = "data-disclosure"
+	nosqlTechniqueDifferential = "response-differential"
+	nosqlTechniqueError        = "error-based"
+)
+
+// nosqlErrorPattern matches the errors MongoDB, its drivers and Mongoose return when a query receives an operator it
+// did not expect.
+var nosqlErrorPattern = regexp.MustCompile(`MongoServerError|MongoError|MongoCursorException|MongoDB\\Driver\\Exception|BSONTypeError|CastError: Cast to \w+ failed|unknown operator: \$\w+|\$where.{0,40}(?:SyntaxError|ReferenceError)|Can't canonicalize query`)
+
+// nosqlOperator is a MongoDB query operator together with the operand it is applied to.
+type nosqlOperator struct {
+	Operator string
+	Operand  string
+}
+
+// nosqlOperatorPair pairs an operator that matches every document with one that matches none, so that a difference
+// between their responses can only come from the operator being evaluated by the database.
+type nosqlOperatorPair struct {
+	True  nosqlOperator
+	False nosqlOperator
+}
+
+func nosqlOperatorPairs(canary string) []nosqlOperatorPair {
+	return []nosqlOperatorPair{
+		{True: nosqlOperator{Operator: "$ne", Operand: canary}, False: nosqlOperator{Operator: "$eq", Operand: canary}},
+		{True: nosqlOperator{Operator: "$gt", Operand: ""}, False: nosqlOperator{Operator: "$lt", Operand: ""}},
+		{True: nosqlOperator{Operator: "$regex", Operand: ".*"}, False: nosqlOperator{Operator: "$regex", Operand: "^" + canary + "$"}},
+	}
+}
+
+// nosqlWherePayloads break out of a string that is concatenated into a $where JavaScript expression.
+var nosqlWherePayloads = []sqlBooleanPayload{
+	{True: "' || 'a'=='a", False: "' && 'a'=='b"},
+	{True: "\" || \"a\"==\"a", False: "\" && \"a\"==\"b"},
+	{True: " || 1==1", False: " && 1==2"},
+}
+
+// nosqlInjectionCheck tests JSON body, query and form parameters for NoSQL injection by replacing their values with
+// MongoDB query operators and by breaking out of $where expressions. Each payload that matches every document is sent
+// alongside one that matches none, and the responses are compared with the baseline to spot authentication bypasses
+// and disclosure of additional data.
+type nosqlInjectionCheck struct{}
+
+func (c *nosqlInjectionCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+		if point.Location != webscan.ParamLocationBody && point.Location != webscan.ParamLocationQuery && point.Location != webscan.ParamLocationForm {
+			continue
+		}
+
+		finding, errs := c.operators(ctx, s, point)
+		errors = append(errors, errs...)
+		if finding == nil {
+			finding, errs = c.where(ctx, s, point)
+			errors = append(errors, errs...)
+		}
+		if finding != nil {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings, errors
+}
+
+func (c *nosqlInjectionCheck) operators(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, pair := range nosqlOperatorPairs(newCanary()) {
+		send := func(operator nosqlOperator) (*response, error) {
+			params, err := point.injectOperator(s.params, operator.Operator, operator.Operand)
+			if err != nil {
+				return nil, err
+			}
+			return s.sendInjected(ctx, point, describeOperator(point, operator), params)
+		}
+		payload := describeOperator(point, pair.True) + " / " + describeOperator(point, pair.False)
+
+		finding, err := c.differential(s, point, payload, func() (*response, error) { return send(pair.True) }, func() (*response, error) { return send(pair.False) })
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeNosql, point, err))
+			continue
+		}
+		if finding != nil {
+			return finding, errors
+		}
+	}
+	return nil, errors
+}
+
+func (c *nosqlInjectionCheck) where(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, pair := range nosqlWherePayloads {
+		send := func(payload string) func() (*response, error) {
+			return func() (*response, error) { return s.sendPayload(ctx, point, point.Value+payload) }
+		}
+
+		finding, err := c.differential(s, point, pair.True+" / "+pair.False, send(pair.True), send(pair.False))
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeNosql, point, err))
+			continue
+		}
+		if finding != nil {
+			return finding, errors
+		}
+	}
+	return nil, errors
+}
+
+// differential sends the true and false variants of a payload and returns a finding when the true variant changes the
+// response in a way the false variant does not, or when either one makes the database report an operator error.
+func (c *nosqlInjectionCheck) differential(s *scanner, point injectionPoint, payload string, sendTrue, sendFalse func() (*response, error)) (*webscan.VulnFinding, error) {
+	trueResp, err := sendTrue()
+	if err != nil {
+		return nil, err
+	}
+	if finding := c.errorBased(s, point, payload, trueResp); finding != nil {
+		return finding, nil
+	}
+	if similarResponses(s.baseline, trueResp) {
+		return nil, nil
+	}
+
+	falseResp, err := sendFalse()
+	if err != nil {
+		return nil, err
+	}
+	if finding := c.errorBased(s, point, payload, falseResp); finding != nil {
+		return finding, nil
+	}
+	// A payload that changes the response regardless of its condition was most likely rejected by input validation
+	if similarResponses(trueResp, falseResp) {
+		return nil, nil
+	}
+
+	// Repeat the true condition to rule out a one-off fluctuation in the response
+	repeatResp, err := sendTrue()
+	if err != nil {
+		return nil, err
+	}
+	if !similarResponses(trueResp, repeatResp) {
+		return nil, nil
+	}
+
+	technique, confidence := nosqlTechniqueDifferential, webscan.ConfidenceLow
+	switch {
+	case isAuthFailure(s.baseline.StatusCode) && isAuthFailure(falseResp.StatusCode) && trueResp.StatusCode < http.StatusBadRequest:
+		technique, confidence = nosqlTechniqueAuthBypass, webscan.ConfidenceHigh
+	case trueResp.StatusCode < http.StatusBadRequest && len(trueResp.Body) > len(s.baseline.Body) && len(trueResp.Body) > len(falseResp.Body):
+		technique, confidence = nosqlTechniqueDisclosure, webscan.ConfidenceMedium
+	}
+
+	evidence := fmt.Sprintf(
+		"true condition changed the response from status %d, %d bytes, body sha256 %s to status %d, %d bytes, body sha256 %s while the false condition returned status %d, %d bytes, body sha256 %s",
+		s.baseline.StatusCode, len(s.baseline.Body), shortHash(s.baseline.Body),
+		trueResp.StatusCode, len(trueResp.Body), shortHash(trueResp.Body),
+		falseResp.StatusCode, len(falseResp.Body), shortHash(falseResp.Body),
+	)
+	return newFinding(webscan.VulnTypeNosql, technique, point, payload, evidence, confidence, trueResp), nil
+}
+
+func (c *nosqlInjectionCheck) errorBased(s *scanner, point injectionPoint, payload string, resp *response) *webscan.VulnFinding {
+	match := nosqlErrorPattern.Find(resp.Body)
+	if match == nil || nosqlErrorPattern.Match(s.baseline.Body) {
+		return nil
+	}
+	evidence := fmt.Sprintf("MongoDB error signature in response: %s", truncate(string(match), 200))
+	finding := newFinding(webscan.VulnTypeNosql, nosqlTechniqueError, point, payload, evidence, webscan.ConfidenceMedium, resp)
+	engine := "MongoDB"
+	finding.Engine = &engine
+	return finding
+}
+
+// describeOperator renders the operator the way it was sent for the injection point.
+func describeOperator(point injectionPoint, operator nosqlOperator) string {
+	if point.Location == webscan.ParamLocationBody {
+		encoded, _ := json.Marshal(map[string]string{operator.Operator: operator.Operand})
+		return string(encoded)
+	}
+	return fmt.Sprintf("%s[%s]=%s", point.Name, operator.Operator, operator.Operand)
+}
+
+func isAuthFailure(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+}
+
+func shortHash(body []byte) string {
+	return bodyHash(body)[:12]
+}
@@ -1,7 +1,10 @@
+// Package requests implements the `webscan app requests` command, which sends custom requests to a single API route and
+// optionally tests each of the request's parameters for injection style vulnerabilities.
 package requests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,11 +12,21 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
-func PerformRequestScan(baseURL, path, method string, params webscan.RequestParams, vulnTypes []string) webscan.RequestReport {
+// PerformRequestScan sends a single custom request to the target route and, when vulnTypes are provided, replays the
+// request with injected payloads to check each of its parameters for the requested vulnerability types.
+func PerformRequestScan(ctx context.Context, baseURL, path, method string, params webscan.RequestParams, vulnTypes []string) webscan.RequestReport {
+	return performRequestScan(ctx, baseURL, path, method, params, vulnTypes, nil)
+}
+
+// performRequestScan is PerformRequestScan with the mass assignment target of a route generated from an API
+// specification, nil for requests that were not.
+func performRequestScan(ctx context.Context, baseURL, path, method string, params webscan.RequestParams, vulnTypes []string, massAssignment *massAssignmentTarget) webscan.RequestReport {
 	report := webscan.RequestReport{
 		BaseUrl: baseURL,
 		Path:    path,
@@ -34,43 +47,75 @@ func PerformRequestScan(baseURL, path, method string, params webscan.RequestPara
 		return report
 	}
 
-	// Construct the URL
-	fullURL, err := constructURL(baseURL, path, parsedParams.PathParams, parsedParams.QueryParams)
+	// The credentials of the authentication profile become headers of the request, so that the AUTH check can remove
+	// or tamper with them like with credentials provided as header parameters. They are left out of the report.
+	reportedParams := parsedParams
+	var profileHeaders []string
+	parsedParams.HeaderParams, profileHeaders, err = withProfileHeaders(ctx, parsedParams.HeaderParams)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 		return report
 	}
+	ctx = httpclient.WithoutAuthentication(ctx)
 
-	// Prepare request body and content type
-	reqBody, contentType, err := prepareRequestBody(parsedParams)
+	// Create and send the request
+	resp, err := executeRequest(ctx, httpMethod, baseURL, path, parsedParams)
 	if err != nil {
 		report.Errors = append(report.Errors, err.Error())
 		return report
 	}
 
-	// Create and send the request
-	resp, err := sendRequest(httpMethod, fullURL.String(), reqBody, contentType, parsedParams.HeaderParams)
+	// Populate report
+	populateReport(&report, resp, reportedParams, vulnTypes)
+
+	// Replay the request with payloads for each requested vulnerability type
+	if len(report.VulnTypes) > 0 {
+		s := newScanner(httpMethod, baseURL, path, parsedParams, profileHeaders, resp)
+		s.massAssignment = massAssignment
+		findings, errs := s.runChecks(ctx, report.VulnTypes)
+		report.Findings = findings
+		report.Errors = append(report.Errors, errs...)
+	}
+
+	return report
+}
+
+// executeRequest builds the request described by the parsed parameters, sends it and reads the full response.
+func executeRequest(ctx context.Context, method, baseURL, path string, params webscan.ParsedParams) (*response, error) {
+	// Construct the URL
+	fullURL, err := constructURL(baseURL, path, params.PathParams, params.QueryParams)
 	if err != nil {
-		report.Errors = append(report.Errors, err.Error())
-		return report
+		return nil, err
+	}
+
+	// Prepare request body and content type
+	reqBody, contentType, err := prepareRequestBody(params)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := sendRequest(ctx, method, fullURL.String(), reqBody, contentType, params.HeaderParams)
+	if err != nil {
+		return nil, err
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			report.Errors = append(report.Errors, fmt.Sprintf("Error closing response body: %v", err))
-		}
-	}()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
+	duration := time.Since(start)
+	if cerr := resp.Body.Close(); cerr != nil && err == nil {
+		return nil, fmt.Errorf("error closing response body: %v", cerr)
+	}
 	if err != nil {
-		report.Errors = append(report.Errors, fmt.Sprintf("Failed to read response body: %v", err))
-		return report
+		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	// Populate report
-	populateReport(&report, resp, body, parsedParams, vulnTypes)
-
-	return report
+	return &response{
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Body:       body,
+		Duration:   duration,
+	}, nil
 }
 
 func isValidHTTPMethod(method string) bool {
@@ -146,6 +191,40 @@ func parseAllParams(params webscan.RequestParams) (webscan.ParsedParams, error)
 	return parsed, nil
 }
 
+// withProfileHeaders adds the headers of the authentication profile that the request does not set itself, and returns
+// the names of the added headers.
+func withProfileHeaders(ctx context.Context, headers map[string]string) (map[string]string, []string, error) {
+	authHeaders, err := httpclient.FromContext(ctx).AuthHeaders()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(authHeaders) == 0 {
+		return headers, nil, nil
+	}
+	merged := make(map[string]string, len(headers)+len(authHeaders))
+	for name, value := range headers {
+		merged[name] = value
+	}
+	added := []string{}
+	for name, values := range authHeaders {
+		if _, ok := headerValue(merged, name); !ok {
+			merged[name] = strings.Join(values, ", ")
+			added = append(added, name)
+		}
+	}
+	return merged, added, nil
+}
+
+// headerValue returns the value of the header with the canonical name, regardless of the case of the header names.
+func headerValue(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if http.CanonicalHeaderKey(key) == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
 func constructURL(baseURL, path string, pathParams, queryParams map[string]string) (*url.URL, error) {
 	fullURL, err := url.Parse(baseURL)
 	if err != nil {
@@ -156,7 +235,13 @@ func constructURL(baseURL, path string, pathParams, queryParams map[string]strin
 	for key, value := range pathParams {
 		endpoint = strings.ReplaceAll(endpoint, fmt.Sprintf("{%s}", key), url.PathEscape(value))
 	}
-	fullURL.Path = endpoint
+	// Keep percent-encoded sequences, including escaped path parameters, exactly as written instead of encoding them twice
+	if unescaped, err := url.PathUnescape(endpoint); err == nil && unescaped != endpoint {
+		fullURL.Path = unescaped
+		fullURL.RawPath = endpoint
+	} else {
+		fullURL.Path = endpoint
+	}
 
 	q := fullURL.Query()
 	for key, value := range queryParams {
@@ -200,8 +285,8 @@ func prepareRequestBody(params webscan.ParsedParams) (io.Reader, string, error)
 	return nil, "", nil
 }
 
-func sendRequest(method, url string, body io.Reader, contentType string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(method, url, body)
+func sendRequest(ctx context.Context, method, url string, body io.Reader, contentType string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
@@ -213,7 +298,7 @@ func sendRequest(method, url string, body io.Reader, contentType string, headers
 		req.Header.Set("Content-Type", contentType)
 	}
 
-	client := &http.Client{}
+	client := httpclient.FromContext(ctx).Client()
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to perform request: %v", err)
@@ -222,11 +307,11 @@ func sendRequest(method, url string, body io.Reader, contentType string, headers
 	return resp, nil
 }
 
-func populateReport(report *webscan.RequestReport, resp *http.Response, body []byte, params webscan.ParsedParams, vulnTypes []string) {
+func populateReport(report *webscan.RequestReport, resp *response, params webscan.ParsedParams, vulnTypes []string) {
 	report.StatusCode = resp.StatusCode
-	report.ResponseBody = string(body)
+	report.ResponseBody = string(resp.Body)
 	report.ResponseHeaders = make(map[string]string)
-	for key, values := range resp.Header {
+	for key, values := range resp.Headers {
 		report.ResponseHeaders[key] = strings.Join(values, ", ")
 	}
 
@@ -251,7 +336,7 @@ func populateReport(report *webscan.RequestReport, resp *http.Response, body []b
 	if len(vulnTypes) > 0 {
 		report.VulnTypes = make([]webscan.VulnType, 0, len(vulnTypes))
 		for _, vt := range vulnTypes {
-			if vulnType, err := webscan.NewVulnTypeFromString(vt); err == nil {
+			if vulnType, err := webscan.NewVulnTypeFromString(strings.ToUpper(vt)); err == nil {
 				report.VulnTypes = append(report.VulnTypes, vulnType)
 			} else {
 				report.Errors = append(report.Errors, fmt.Sprintf("Invalid vulnerability type: %s", vt))
@@ -0,0 +1,184 @@
+package requests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+// PerformRoutesScan builds a request for every route of a RoutesReport produced by `webscan app enumerate`, sends it
+// with the provided headers using up to concurrency workers and, when vulnTypes are provided, checks it for the
+// requested vulnerability types. The results are consolidated into a single report listing the reachable, erroring and
+// unauthenticated routes.
+func PerformRoutesScan(ctx context.Context, routesReport webscan.RoutesReport, baseURL string, headerParams string, vulnTypes []string, concurrency int) webscan.RequestBatchReport {
+	if baseURL == "" {
+		baseURL = routesReport.BaseEndpointUrl
+	}
+	report := webscan.RequestBatchReport{
+		Target:  routesReport.Target,
+		BaseUrl: baseURL,
+	}
+	if baseURL == "" {
+		report.Errors = append(report.Errors, "routes report has no base endpoint URL, provide one with --baseUrl")
+		return report
+	}
+	if _, err := parseJSONParams(headerParams); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to parse header parameters: %v", err))
+		return report
+	}
+
+	batch, errs := routesBatch(routesReport, baseURL, headerParams)
+	report.Errors = append(report.Errors, errs...)
+	runBatch(ctx, &report, batch, vulnTypes, concurrency)
+	return report
+}
+
+// RoutesReportRequests builds a request for every route of a RoutesReport, sent to baseURL when provided and to the
+// report's base endpoint URL otherwise. Routes for which no request could be built are described in the returned errors.
+func RoutesReportRequests(routesReport webscan.RoutesReport, baseURL string) ([]webscan.RequestParams, []string) {
+	if baseURL == "" {
+		baseURL = routesReport.BaseEndpointUrl
+	}
+	batch, errs := routesBatch(routesReport, baseURL, "")
+	requests := make([]webscan.RequestParams, 0, len(batch))
+	for _, request := range batch {
+		requests = append(requests, request.Params)
+	}
+	return requests, errs
+}
+
+func routesBatch(routesReport webscan.RoutesReport, baseURL string, headerParams string) ([]batchRequest, []string) {
+	globalSecurity := false
+	for _, requirement := range routesReport.Security {
+		if requirement != nil && len(requirement.Schemes) > 0 {
+			globalSecurity = true
+		}
+	}
+
+	batch := []batchRequest{}
+	errs := []string{}
+	for _, route := range routesReport.Routes {
+		if route == nil || route.Path == "" || route.Method == "" {
+			continue
+		}
+		params, err := routeRequestParams(route, headerParams)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("failed to build request for %s %s: %v", strings.ToUpper(route.Method), route.Path, err))
+			continue
+		}
+		path, method := route.Path, route.Method
+		params.BaseUrl, params.Path, params.Method = &baseURL, &path, &method
+		securityRequired := globalSecurity || (route.Security != nil && len(route.Security.Schemes) > 0)
+		batch = append(batch, batchRequest{
+			Params:           params,
+			SecurityRequired: &securityRequired,
+			MassAssignment:   newMassAssignmentTarget(route, routesReport.Routes),
+		})
+	}
+	return batch, errs
+}
+
+// RouteCaptureRequests builds a request for every route of a RouteCaptureReport produced by `webscan routecapture`,
+// using the first example value captured for each query and body parameter. Routes whose URL cannot be parsed are
+// described in the returned errors.
+func RouteCaptureRequests(captureReport webscan.RouteCaptureReport) ([]webscan.RequestParams, []string) {
+	requests := []webscan.RequestParams{}
+	errs := []string{}
+	for _, route := range captureReport.Routes {
+		if route == nil {
+			continue
+		}
+		routeURL, err := url.Parse(route.Url)
+		if err != nil || routeURL.Host == "" {
+			errs = append(errs, fmt.Sprintf("failed to parse captured route URL %s", route.Url))
+			continue
+		}
+
+		baseURL := fmt.Sprintf("%s://%s", routeURL.Scheme, routeURL.Host)
+		path := routeURL.Path
+		if route.Path != nil && *route.Path != "" {
+			path = *route.Path
+		}
+		if path == "" {
+			path = "/"
+		}
+		method := string(webscan.HttpMethodGet)
+		if route.Method != nil {
+			method = string(*route.Method)
+		}
+
+		query := make(map[string]string)
+		for key, values := range routeURL.Query() {
+			query[key] = values[0]
+		}
+		for _, param := range route.QueryParams {
+			if param == nil {
+				continue
+			}
+			if _, ok := query[param.Name]; !ok {
+				query[param.Name] = firstExample(param.ExampleValues, param.Name)
+			}
+		}
+		body := make(map[string]string)
+		for _, param := range route.BodyParams {
+			if param != nil {
+				body[param.Name] = firstExample(param.ExampleValues, param.Name)
+			}
+		}
+
+		params := webscan.RequestParams{BaseUrl: &baseURL, Path: &path, Method: &method}
+		if params.QueryParams, err = encodeParams(query); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if len(body) > 0 {
+			if params.BodyParams, err = encodeParams(body); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+		}
+		requests = append(requests, params)
+	}
+	return requests, errs
+}
+
+func firstExample(examples []string, name string) string {
+	if len(examples) > 0 {
+		return examples[0]
+	}
+	return valueForName(name)
+}
+
+// requestOutcome classifies the response to a request by its status code.
+func requestOutcome(report *webscan.RequestReport) webscan.RequestOutcome {
+	switch {
+	case report.StatusCode == 0:
+		return webscan.RequestOutcomeFailed
+	case report.StatusCode == http.StatusUnauthorized || report.StatusCode == http.StatusForbidden:
+		return webscan.RequestOutcomeUnauthorized
+	case report.StatusCode >= http.StatusInternalServerError:
+		return webscan.RequestOutcomeServerError
+	case report.StatusCode >= http.StatusBadRequest:
+		return webscan.RequestOutcomeClientError
+	default:
+		return webscan.RequestOutcomeReachable
+	}
+}
+
+// summarizeResult adds the result's route to the summary lists of the batch report. Routes that were reachable
+// without any credentials being sent are listed as unauthenticated.
+func summarizeResult(report *webscan.RequestBatchReport, result *webscan.RequestResult) {
+	switch result.Outcome {
+	case webscan.RequestOutcomeReachable:
+		report.Reachable = append(report.Reachable, result.Route)
+		if !result.CredentialsSent {
+			report.Unauthenticated = append(report.Unauthenticated, result.Route)
+		}
+	case webscan.RequestOutcomeServerError, webscan.RequestOutcomeFailed:
+		report.Erroring = append(report.Erroring, result.Route)
+	}
+}
@@ -0,0 +1,256 @@
+package requests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// response captures the parts of an HTTP response that the report and the vulnerability checks rely on.
+type response struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+	Duration   time.Duration
+}
+
+// vulnCheck is implemented by every vulnerability check that can be selected with the --vulnType flag. A check replays
+// the baseline request through the scanner with its own payloads and returns the findings it was able to confirm.
+type vulnCheck interface {
+	run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string)
+}
+
+// vulnChecks maps each supported VulnType to the check that implements it. VulnTypes that are aliases of one another
+// are collapsed by canonicalVulnType before the lookup.
+var vulnChecks = map[webscan.VulnType]vulnCheck{
+	webscan.VulnTypeSql:            &sqlInjectionCheck{},
+	webscan.VulnTypeXss:            &xssCheck{},
+	webscan.VulnTypeCommand:        &commandInjectionCheck{},
+	webscan.VulnTypeTemplate:       &templateInjectionCheck{},
+	webscan.VulnTypeNosql:          &nosqlInjectionCheck{},
+	webscan.VulnTypeAuth:           &authBypassCheck{},
+	webscan.VulnTypeSensitiveerror: &sensitiveErrorCheck{},
+	webscan.VulnTypeMassassignment: &massAssignmentCheck{},
+}
+
+func canonicalVulnType(vulnType webscan.VulnType) webscan.VulnType {
+	if vulnType == webscan.VulnTypeSqlinjection {
+		return webscan.VulnTypeSql
+	}
+	return vulnType
+}
+
+// scanner replays a single request with modified parameters so that vulnerability checks can compare the responses
+// to the original baseline response. Every response to an injected payload is also classified for verbose errors.
+type scanner struct {
+	method   string
+	baseURL  string
+	path     string
+	params   webscan.ParsedParams
+	baseline *response
+	points   []injectionPoint
+	observer *errorObserver
+	// massAssignment is set when the request was generated from an API specification
+	massAssignment *massAssignmentTarget
+}
+
+// newScanner creates the scanner of a request. The headers of the authentication profile are not injection points, so
+// that the payloads of the checks are sent authenticated, and only the AUTH check tampers with them.
+func newScanner(method, baseURL, path string, params webscan.ParsedParams, profileHeaders []string, baseline *response) *scanner {
+	return &scanner{
+		method:   method,
+		baseURL:  baseURL,
+		path:     path,
+		params:   params,
+		baseline: baseline,
+		points:   collectInjectionPoints(params, profileHeaders),
+		observer: newErrorObserver(baseline),
+	}
+}
+
+// runChecks runs the check for every requested VulnType once, in the order they were requested, followed by the
+// verbose errors that their payloads provoked.
+func (s *scanner) runChecks(ctx context.Context, vulnTypes []webscan.VulnType) ([]*webscan.VulnFinding, []string) {
+	log := svc1log.FromContext(ctx)
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	ran := make(map[webscan.VulnType]bool)
+	for _, vulnType := range vulnTypes {
+		vulnType = canonicalVulnType(vulnType)
+		if ran[vulnType] {
+			continue
+		}
+		ran[vulnType] = true
+
+		check, ok := vulnChecks[vulnType]
+		if !ok {
+			log.Warn("No check is implemented for vulnerability type", svc1log.SafeParam("vulnType", vulnType))
+			continue
+		}
+		log.Debug("Running vulnerability check", svc1log.SafeParam("vulnType", vulnType), svc1log.SafeParam("injectionPoints", len(s.points)))
+		checkFindings, checkErrors := check.run(ctx, s)
+		findings = append(findings, checkFindings...)
+		errors = append(errors, checkErrors...)
+	}
+	findings = append(findings, s.observer.findings...)
+
+	return findings, errors
+}
+
+// send replays the request with the provided parameters.
+func (s *scanner) send(ctx context.Context, params webscan.ParsedParams) (*response, error) {
+	return executeRequest(ctx, s.method, s.baseURL, s.path, params)
+}
+
+// sendPayload replays the request with value substituted for the original value of the injection point.
+func (s *scanner) sendPayload(ctx context.Context, point injectionPoint, value string) (*response, error) {
+	params, err := point.inject(s.params, value)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendInjected(ctx, point, value, params)
+}
+
+// sendInjected replays the request with params, which carry payload injected into the injection point, and records any
+// verbose error the payload provoked.
+func (s *scanner) sendInjected(ctx context.Context, point injectionPoint, payload string, params webscan.ParsedParams) (*response, error) {
+	resp, err := s.send(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	s.observer.observe(point, payload, resp)
+	return resp, nil
+}
+
+// newFinding creates a finding for the injection point, recording the status code, timing, size and body hash of the
+// response that confirmed it.
+func newFinding(vulnType webscan.VulnType, technique string, point injectionPoint, payload string, evidence string, confidence webscan.Confidence, resp *response) *webscan.VulnFinding {
+	finding := webscan.VulnFinding{
+		VulnType:   vulnType,
+		Technique:  technique,
+		Location:   point.Location,
+		Parameter:  point.Name,
+		Payload:    payload,
+		Evidence:   evidence,
+		Confidence: confidence,
+	}
+	if resp != nil {
+		statusCode := resp.StatusCode
+		responseTime := int(resp.Duration.Milliseconds())
+		responseSize := len(resp.Body)
+		responseHash := bodyHash(resp.Body)
+		finding.StatusCode = &statusCode
+		finding.ResponseTime = &responseTime
+		finding.ResponseSize = &responseSize
+		finding.ResponseHash = &responseHash
+	}
+	return &finding
+}
+
+// bodyHash returns the hex encoded SHA-256 digest of a response body.
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// sleepSeconds is the delay requested by time-based payloads. A response is only considered delayed when it takes at
+// least delayRatio of this delay longer than the baseline request.
+const (
+	sleepSeconds = 5
+	delayRatio   = 0.8
+)
+
+// delayEvidence holds the responses that confirmed a time-based injection: the delayed response, a repeat of it and
+// a control request that used the same syntax without the delay.
+type delayEvidence struct {
+	Delayed *response
+	Repeat  *response
+	Control *response
+}
+
+// confirmDelay sends the delayed payload and, when the response is slow enough, confirms the delay by checking that
+// the control payload comes back quickly and that the delay can be reproduced. It returns nil when the delay could
+// not be confirmed, which most likely means it was caused by the network or a slow server.
+func (s *scanner) confirmDelay(ctx context.Context, point injectionPoint, delayed string, control string) (*delayEvidence, error) {
+	threshold := s.baseline.Duration + time.Duration(float64(sleepSeconds*time.Second)*delayRatio)
+
+	delayedResp, err := s.sendPayload(ctx, point, delayed)
+	if err != nil || delayedResp.Duration < threshold {
+		return nil, err
+	}
+	controlResp, err := s.sendPayload(ctx, point, control)
+	if err != nil || controlResp.Duration >= threshold {
+		return nil, err
+	}
+	repeatResp, err := s.sendPayload(ctx, point, delayed)
+	if err != nil || repeatResp.Duration < threshold {
+		return nil, err
+	}
+	return &delayEvidence{Delayed: delayedResp, Repeat: repeatResp, Control: controlResp}, nil
+}
+
+func (d *delayEvidence) describe(baseline *response) string {
+	return fmt.Sprintf(
+		"response delayed by %s and %s with a %ds delay against %s for the baseline and %s for the control payload",
+		d.Delayed.Duration.Round(time.Millisecond), d.Repeat.Duration.Round(time.Millisecond), sleepSeconds,
+		baseline.Duration.Round(time.Millisecond), d.Control.Duration.Round(time.Millisecond),
+	)
+}
+
+func probeError(vulnType webscan.VulnType, point injectionPoint, err error) string {
+	return fmt.Sprintf("%s probe of %s parameter %s failed: %v", vulnType, point.Location, point.Name, err)
+}
+
+// similarityThreshold is the minimum similarity score for two response bodies to be considered the same page.
+const similarityThreshold = 0.95
+
+// similarity returns a score between 0 and 1 describing how alike two response bodies are. The bodies are compared as
+// multisets of lines so that small dynamic fragments (timestamps, tokens, reflected input) only lower the score by the
+// share of lines they touch.
+func similarity(a, b []byte) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	aLines := strings.Split(string(a), "\n")
+	bLines := strings.Split(string(b), "\n")
+
+	counts := make(map[string]int, len(aLines))
+	for _, line := range aLines {
+		counts[line]++
+	}
+	common := 0
+	for _, line := range bLines {
+		if counts[line] > 0 {
+			counts[line]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(aLines)+len(bLines))
+}
+
+// similarResponses reports whether two responses share a status code and nearly identical bodies once the payloads
+// that may have been reflected into them are removed.
+func similarResponses(a, b *response, reflections ...string) bool {
+	if a.StatusCode != b.StatusCode {
+		return false
+	}
+	return similarity(stripReflections(a.Body, reflections), stripReflections(b.Body, reflections)) >= similarityThreshold
+}
+
+func stripReflections(body []byte, reflections []string) []byte {
+	stripped := string(body)
+	for _, reflection := range reflections {
+		if reflection != "" {
+			stripped = strings.ReplaceAll(stripped, reflection, "")
+		}
+	}
+	return []byte(stripped)
+}
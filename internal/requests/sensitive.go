@@ -0,0 +1,212 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+// Categories of sensitive information, reported as the technique of SENSITIVEERROR findings.
+const (
+	sensitiveCategoryDebugPage  = "debug-page"
+	sensitiveCategoryStackTrace = "stack-trace"
+	sensitiveCategorySQLError   = "sql-error"
+	sensitiveCategoryFilePath   = "file-path"
+	sensitiveCategoryInternalIP = "internal-ip"
+	sensitiveCategoryVersion    = "version-banner"
+)
+
+// errorSignature identifies one kind of sensitive information in a response. Body signatures are matched against the
+// response body, header signatures against the value of the named response header. When Framework is empty the
+// framework is taken from the first capture group of the pattern.
+type errorSignature struct {
+	Name       string
+	Category   string
+	Framework  string
+	Header     string
+	Pattern    *regexp.Regexp
+	Confidence webscan.Confidence
+}
+
+var errorSignatures = []errorSignature{
+	// Framework debug pages
+	{Name: "django-debug", Category: sensitiveCategoryDebugPage, Framework: "Django", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`You're seeing this error because you have <code>DEBUG = True</code>|<th>Django Version:</th>`)},
+	{Name: "werkzeug-debugger", Category: sensitiveCategoryDebugPage, Framework: "Flask/Werkzeug", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`The debugger caught an exception in your WSGI application|<title>[^<]*// Werkzeug Debugger</title>`)},
+	{Name: "rails-debug", Category: sensitiveCategoryDebugPage, Framework: "Ruby on Rails", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`Action Controller: Exception caught|<h1>Routing Error</h1>|ActiveRecord::\w+(?:Error|Invalid|NotFound)\b`)},
+	{Name: "spring-whitelabel", Category: sensitiveCategoryDebugPage, Framework: "Spring Boot", Confidence: webscan.ConfidenceMedium, Pattern: regexp.MustCompile(`Whitelabel Error Page`)},
+	{Name: "aspnet-ysod", Category: sensitiveCategoryDebugPage, Framework: "ASP.NET", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`Server Error in '[^']*' Application|<b>\s*Stack Trace:\s*</b>|\[HttpException \(0x[0-9a-fA-F]+\)|ASP\.NET is configured to show verbose error messages`)},
+	{Name: "laravel-debug", Category: sensitiveCategoryDebugPage, Framework: "Laravel", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`Whoops! There was an error\.|Illuminate\\[A-Z]\w+(?:\\\w+)+Exception`)},
+	{Name: "symfony-debug", Category: sensitiveCategoryDebugPage, Framework: "Symfony", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`Symfony\\Component\\\w+(?:\\\w+)*Exception|<abbr title="Symfony\\`)},
+
+	// Stack traces and language level errors
+	{Name: "php-error", Category: sensitiveCategoryStackTrace, Framework: "PHP", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`(?:<b>)?(?:Fatal error|Parse error|Warning|Notice|Deprecated)(?:</b>)?:\s+.{1,300}? in (?:<b>)?[^<\s]+\.php(?:</b>)? on line (?:<b>)?\d+|PHP (?:Fatal error|Parse error|Warning):`)},
+	{Name: "go-panic", Category: sensitiveCategoryStackTrace, Framework: "Go", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`panic: .+\n+goroutine \d+ \[running\]:|goroutine \d+ \[running\]:\n|http: panic serving`)},
+	{Name: "python-traceback", Category: sensitiveCategoryStackTrace, Framework: "Python", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`Traceback \(most recent call last\):|File "[^"]+\.py", line \d+, in \w+`)},
+	{Name: "java-stack-trace", Category: sensitiveCategoryStackTrace, Framework: "Java", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`(?:java|javax|jakarta)\.[\w.]+(?:Exception|Error)(?::[^\n<]*)?\s+at [\w$.]+\([\w$]+\.java:\d+\)|\bat (?:org\.springframework|org\.apache|java\.base)[\w$.]+\([\w$]+\.java:\d+\)`)},
+	{Name: "dotnet-stack-trace", Category: sensitiveCategoryStackTrace, Framework: ".NET", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`System\.[\w.]+Exception: [^\n<]*|\bat [\w.` + "`" + `]+\([^)]*\) in [A-Z]:\\[^\n<]+:line \d+`)},
+	{Name: "node-stack-trace", Category: sensitiveCategoryStackTrace, Framework: "Node.js", Confidence: webscan.ConfidenceHigh, Pattern: regexp.MustCompile(`(?:Error|TypeError|ReferenceError|SyntaxError): [^\n<]*\n\s+at [^\n]+\((?:/|[A-Z]:\\|node:)[^)]+:\d+:\d+\)`)},
+	{Name: "ruby-stack-trace", Category: sensitiveCategoryStackTrace, Framework: "Ruby", Confidence: webscan.ConfidenceMedium, Pattern: regexp.MustCompile(`/gems/[\w.-]+/lib/[\w/.-]+\.rb:\d+:in\b`)},
+
+	// File system paths of source files
+	{Name: "unix-path", Category: sensitiveCategoryFilePath, Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`(?:/(?:home|var|usr|opt|srv|app|www|root)(?:/[\w.@-]+)+\.(?:py|rb|php|java|js|ts|go|cs|jsp|pl))\b`)},
+	{Name: "windows-path", Category: sensitiveCategoryFilePath, Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`\b[A-Z]:\\(?:[\w .-]+\\)+[\w .-]+\.(?:cs|vb|aspx|ascx|php|java|py|js|dll|config)\b`)},
+
+	// Private network addresses
+	{Name: "internal-ip", Category: sensitiveCategoryInternalIP, Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`\b(?:10(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){3}|172\.(?:1[6-9]|2\d|3[01])(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){2}|192\.168(?:\.(?:25[0-5]|2[0-4]\d|1?\d?\d)){2})\b`)},
+
+	// Version banners in response headers
+	{Name: "server-banner", Category: sensitiveCategoryVersion, Header: "Server", Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`([A-Za-z][\w.-]*)/\d+(?:\.\d+)+`)},
+	{Name: "powered-by-banner", Category: sensitiveCategoryVersion, Header: "X-Powered-By", Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`([A-Za-z][\w. -]*?)[/ ]\d+(?:\.\d+)+`)},
+	{Name: "aspnet-version", Category: sensitiveCategoryVersion, Header: "X-AspNet-Version", Framework: "ASP.NET", Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`\d+(?:\.\d+)+`)},
+	{Name: "aspnetmvc-version", Category: sensitiveCategoryVersion, Header: "X-AspNetMvc-Version", Framework: "ASP.NET MVC", Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`\d+(?:\.\d+)+`)},
+	{Name: "generator-banner", Category: sensitiveCategoryVersion, Header: "X-Generator", Confidence: webscan.ConfidenceLow, Pattern: regexp.MustCompile(`([A-Za-z][\w. -]*?) \d+(?:\.\d+)*`)},
+}
+
+// errorMatch is a signature that matched a response, together with where and what it matched.
+type errorMatch struct {
+	Signature errorSignature
+	Framework string
+	Header    string
+	Snippet   string
+}
+
+// classifyResponse runs every signature, including the SQL error signatures, against the response and returns one
+// match per signature that was found.
+func classifyResponse(resp *response) []errorMatch {
+	matches := []errorMatch{}
+	for _, signature := range errorSignatures {
+		if signature.Header != "" {
+			for _, value := range resp.Headers.Values(signature.Header) {
+				if match := signature.Pattern.FindStringSubmatch(value); match != nil {
+					matches = append(matches, newErrorMatch(signature, http.CanonicalHeaderKey(signature.Header), match))
+					break
+				}
+			}
+			continue
+		}
+		if match := signature.Pattern.FindSubmatch(resp.Body); match != nil {
+			groups := make([]string, len(match))
+			for i, group := range match {
+				groups[i] = string(group)
+			}
+			matches = append(matches, newErrorMatch(signature, "", groups))
+		}
+	}
+
+	if dbms, match := matchSQLError(resp.Body); match != "" {
+		signature := errorSignature{Name: "sql-error-" + strings.ReplaceAll(strings.ToLower(dbms), " ", "-"), Category: sensitiveCategorySQLError, Framework: dbms, Confidence: webscan.ConfidenceHigh}
+		matches = append(matches, newErrorMatch(signature, "", []string{match}))
+	}
+	return matches
+}
+
+func newErrorMatch(signature errorSignature, header string, groups []string) errorMatch {
+	framework := signature.Framework
+	if framework == "" && len(groups) > 1 {
+		framework = strings.TrimSpace(groups[1])
+	}
+	return errorMatch{Signature: signature, Framework: framework, Header: header, Snippet: truncate(strings.TrimSpace(groups[0]), 200)}
+}
+
+// newErrorFinding creates a SENSITIVEERROR finding for a signature match in the response to the payload sent to point.
+func newErrorFinding(match errorMatch, point injectionPoint, payload string, evidence string, resp *response) *webscan.VulnFinding {
+	finding := newFinding(webscan.VulnTypeSensitiveerror, match.Signature.Category, point, payload, evidence, match.Signature.Confidence, resp)
+	if match.Framework != "" {
+		framework := match.Framework
+		finding.Framework = &framework
+	}
+	snippet := match.Snippet
+	finding.Snippet = &snippet
+	return finding
+}
+
+// sensitiveErrorPayloads mix characters that break the syntax of common interpreters and template languages, in the
+// hope that the application fails with a verbose error.
+var sensitiveErrorPayloads = []string{"'\"`<>{{${%}}\\", "%ff%00", "[]"}
+
+// sensitiveErrorCheck classifies the baseline response for leaked stack traces, debug pages, SQL errors, file paths,
+// internal addresses and version banners, then sends malformed values to every injection point. Verbose errors caused
+// by those values, like those caused by the payloads of any other check, are recorded by the scanner.
+type sensitiveErrorCheck struct{}
+
+func (c *sensitiveErrorCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	for _, match := range classifyResponse(s.baseline) {
+		location, name, where := webscan.ParamLocationBody, "", "response body"
+		if match.Header != "" {
+			location, name, where = webscan.ParamLocationHeader, match.Header, match.Header+" response header"
+		}
+		evidence := fmt.Sprintf("%s signature %s found in the %s of the unmodified request: %s", match.Signature.Category, match.Signature.Name, where, match.Snippet)
+		findings = append(findings, newErrorFinding(match, injectionPoint{Location: location, Name: name}, "", evidence, s.baseline))
+	}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+		for _, payload := range sensitiveErrorPayloads {
+			if _, err := s.sendPayload(ctx, point, point.Value+payload); err != nil {
+				errors = append(errors, probeError(webscan.VulnTypeSensitiveerror, point, err))
+			}
+		}
+		// Values of an unexpected type are a common source of unhandled exceptions in JSON APIs
+		if point.Location == webscan.ParamLocationBody {
+			for _, value := range []interface{}{map[string]interface{}{}, []interface{}{}} {
+				params, err := point.injectValue(s.params, value)
+				if err == nil {
+					_, err = s.sendInjected(ctx, point, describeJSON(value), params)
+				}
+				if err != nil {
+					errors = append(errors, probeError(webscan.VulnTypeSensitiveerror, point, err))
+				}
+			}
+		}
+	}
+
+	return findings, errors
+}
+
+// errorObserver records the verbose errors that injected payloads provoke. Only signatures that are absent from the
+// baseline response are recorded, once per injection point.
+type errorObserver struct {
+	baseline map[string]bool
+	seen     map[string]bool
+	findings []*webscan.VulnFinding
+}
+
+func newErrorObserver(baseline *response) *errorObserver {
+	observer := &errorObserver{baseline: make(map[string]bool), seen: make(map[string]bool)}
+	for _, match := range classifyResponse(baseline) {
+		observer.baseline[match.Signature.Name] = true
+	}
+	return observer
+}
+
+func (o *errorObserver) observe(point injectionPoint, payload string, resp *response) {
+	for _, match := range classifyResponse(resp) {
+		// Banners and addresses do not depend on the input, so they are only reported from the baseline
+		if match.Signature.Category == sensitiveCategoryVersion || match.Signature.Category == sensitiveCategoryInternalIP {
+			continue
+		}
+		key := strings.Join([]string{match.Signature.Name, string(point.Location), point.Name}, "\x00")
+		if o.baseline[match.Signature.Name] || o.seen[key] {
+			continue
+		}
+		o.seen[key] = true
+		evidence := fmt.Sprintf("payload produced a %s (%s) that is not present in the baseline response: %s", match.Signature.Category, match.Signature.Name, match.Snippet)
+		o.findings = append(o.findings, newErrorFinding(match, point, payload, evidence, resp))
+	}
+}
+
+func describeJSON(value interface{}) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
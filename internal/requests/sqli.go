@@ -0,0 +1,219 @@
+package requests
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const (
+	sqlTechniqueError   = "error-based"
+	sqlTechniqueBoolean = "boolean-based"
+	sqlTechniqueTime    = "time-based"
+)
+
+// sqlErrorSignature matches the error messages a particular database engine, or the driver in front of it, is known
+// to leak when a query fails to parse.
+type sqlErrorSignature struct {
+	DBMS    string
+	Pattern *regexp.Regexp
+}
+
+var sqlErrorSignatures = []sqlErrorSignature{
+	{DBMS: "MySQL", Pattern: regexp.MustCompile(`(?i)SQL syntax.*?MySQL|Warning.*?\Wmysqli?_|MySQLSyntaxErrorException|valid MySQL result|check the manual that (?:corresponds|fits) to your (?:MySQL|MariaDB) server version|com\.mysql\.jdbc`)},
+	{DBMS: "PostgreSQL", Pattern: regexp.MustCompile(`(?i)PostgreSQL.*?ERROR|Warning.*?\Wpg_|valid PostgreSQL result|Npgsql\.|PG::SyntaxError:|org\.postgresql\.util\.PSQLException|ERROR:\s+syntax error at or near|unterminated quoted string at or near`)},
+	{DBMS: "Microsoft SQL Server", Pattern: regexp.MustCompile(`(?i)Driver.*? SQL[\-_ ]*Server|OLE DB.*? SQL Server|\bSQL Server[^<"]+Driver|Warning.*?\W(?:mssql|sqlsrv)_|System\.Data\.SqlClient\.SqlException|Unclosed quotation mark after the character string|Microsoft SQL Native Client error`)},
+	{DBMS: "Oracle", Pattern: regexp.MustCompile(`\bORA-\d{5}|Oracle error|Oracle.*?Driver|Warning.*?\W(?:oci|ora)_|quoted string not properly terminated|SQL command not properly ended`)},
+	{DBMS: "SQLite", Pattern: regexp.MustCompile(`(?i)SQLite/JDBCDriver|SQLite\.Exception|(?:Microsoft|System)\.Data\.SQLite\.SQLiteException|Warning.*?\W(?:sqlite_|SQLite3::)|\[SQLITE_ERROR\]|SQLite error \d+:|sqlite3\.OperationalError:|SQLite3::SQLException|org\.sqlite\.JDBC|unrecognized token:`)},
+	{DBMS: "IBM DB2", Pattern: regexp.MustCompile(`(?i)CLI Driver.*?DB2|DB2 SQL error|\bdb2_\w+\(|SQLCODE[=:\d, -]+SQLSTATE|com\.ibm\.db2\.jcc`)},
+	{DBMS: "Unknown", Pattern: regexp.MustCompile(`(?i)SQLSTATE\[\w+\]|java\.sql\.SQLException|PDOException|ODBC (?:SQL Server )?Driver|unterminated string literal|syntax error at end of input`)},
+}
+
+// sqlErrorPayloads break out of the surrounding string or expression to make the query fail to parse.
+var sqlErrorPayloads = []string{"'", "\"", "')", "'))", "`", "\\", "';", "1'\"("}
+
+// sqlBooleanPayload pairs a condition that leaves the query result unchanged with one that empties it.
+type sqlBooleanPayload struct {
+	True  string
+	False string
+}
+
+var sqlBooleanPayloads = []sqlBooleanPayload{
+	{True: "' AND '1'='1", False: "' AND '1'='2"},
+	{True: "\" AND \"1\"=\"1", False: "\" AND \"1\"=\"2"},
+	{True: " AND 1=1", False: " AND 1=2"},
+	{True: "' AND 1=1-- -", False: "' AND 1=2-- -"},
+	{True: ") AND (1=1", False: ") AND (1=2"},
+}
+
+// sqlTimePayload asks a specific database engine to sleep; Template takes the delay in seconds.
+type sqlTimePayload struct {
+	DBMS     string
+	Template string
+}
+
+var sqlTimePayloads = []sqlTimePayload{
+	{DBMS: "MySQL", Template: "' AND SLEEP(%d)-- -"},
+	{DBMS: "MySQL", Template: " AND SLEEP(%d)"},
+	{DBMS: "PostgreSQL", Template: "'||pg_sleep(%d)--"},
+	{DBMS: "PostgreSQL", Template: ";SELECT pg_sleep(%d)--"},
+	{DBMS: "Microsoft SQL Server", Template: "';WAITFOR DELAY '0:0:%d'--"},
+	{DBMS: "Microsoft SQL Server", Template: ";WAITFOR DELAY '0:0:%d'--"},
+	{DBMS: "Oracle", Template: "' AND 1=DBMS_PIPE.RECEIVE_MESSAGE('a',%d)-- "},
+}
+
+// sqlInjectionCheck tests every injection point for error-based, boolean-based and time-based SQL injection.
+type sqlInjectionCheck struct{}
+
+func (c *sqlInjectionCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	// Boolean-based detection relies on the page being stable, so confirm that replaying the unmodified request yields
+	// the same response before trusting any differences
+	stable := false
+	if replay, err := s.send(ctx, s.params); err == nil {
+		stable = similarResponses(s.baseline, replay)
+	} else {
+		errors = append(errors, fmt.Sprintf("failed to replay baseline request: %v", err))
+	}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+
+		pointFindings := []*webscan.VulnFinding{}
+		finding, errs := c.errorBased(ctx, s, point)
+		errors = append(errors, errs...)
+		if finding != nil {
+			pointFindings = append(pointFindings, finding)
+		}
+		if stable {
+			finding, errs := c.booleanBased(ctx, s, point)
+			errors = append(errors, errs...)
+			if finding != nil {
+				pointFindings = append(pointFindings, finding)
+			}
+		}
+		// Time-based payloads are slow, so only fall back to them when nothing cheaper confirmed the injection
+		if len(pointFindings) == 0 {
+			finding, errs := c.timeBased(ctx, s, point)
+			errors = append(errors, errs...)
+			if finding != nil {
+				pointFindings = append(pointFindings, finding)
+			}
+		}
+		findings = append(findings, pointFindings...)
+	}
+
+	return findings, errors
+}
+
+func (c *sqlInjectionCheck) errorBased(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, payload := range sqlErrorPayloads {
+		resp, err := s.sendPayload(ctx, point, point.Value+payload)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeSql, point, err))
+			continue
+		}
+		dbms, match := matchSQLError(resp.Body)
+		if match == "" {
+			continue
+		}
+		// An error that is already present in the baseline response says nothing about the injected payload
+		if _, baselineMatch := matchSQLError(s.baseline.Body); baselineMatch != "" {
+			continue
+		}
+		evidence := fmt.Sprintf("%s error signature in response: %s", dbms, truncate(match, 200))
+		finding := newFinding(webscan.VulnTypeSql, sqlTechniqueError, point, payload, evidence, webscan.ConfidenceHigh, resp)
+		if dbms != "Unknown" {
+			finding.Engine = &dbms
+		}
+		return finding, errors
+	}
+	return nil, errors
+}
+
+func (c *sqlInjectionCheck) booleanBased(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, payload := range sqlBooleanPayloads {
+		trueResp, err := s.sendPayload(ctx, point, point.Value+payload.True)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeSql, point, err))
+			continue
+		}
+		if !similarResponses(s.baseline, trueResp, payload.True) {
+			continue
+		}
+
+		falseResp, err := s.sendPayload(ctx, point, point.Value+payload.False)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeSql, point, err))
+			continue
+		}
+		if similarResponses(s.baseline, falseResp, payload.False) {
+			continue
+		}
+
+		// Repeat the false condition to rule out a one-off fluctuation in the response
+		confirmResp, err := s.sendPayload(ctx, point, point.Value+payload.False)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeSql, point, err))
+			continue
+		}
+		if !similarResponses(falseResp, confirmResp, payload.False) {
+			continue
+		}
+
+		evidence := fmt.Sprintf(
+			"true condition matched the baseline (status %d, %d bytes) while false condition %q differed (status %d, %d bytes, similarity %.2f)",
+			trueResp.StatusCode, len(trueResp.Body), payload.False, falseResp.StatusCode, len(falseResp.Body),
+			similarity(stripReflections(s.baseline.Body, []string{payload.False}), stripReflections(falseResp.Body, []string{payload.False})),
+		)
+		return newFinding(webscan.VulnTypeSql, sqlTechniqueBoolean, point, payload.True+" / "+payload.False, evidence, webscan.ConfidenceMedium, falseResp), errors
+	}
+	return nil, errors
+}
+
+func (c *sqlInjectionCheck) timeBased(ctx context.Context, s *scanner, point injectionPoint) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, payload := range sqlTimePayloads {
+		sleepPayload := fmt.Sprintf(payload.Template, sleepSeconds)
+		delay, err := s.confirmDelay(ctx, point, point.Value+sleepPayload, point.Value+fmt.Sprintf(payload.Template, 0))
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeSql, point, err))
+			continue
+		}
+		if delay == nil {
+			continue
+		}
+
+		finding := newFinding(webscan.VulnTypeSql, sqlTechniqueTime, point, sleepPayload, delay.describe(s.baseline), webscan.ConfidenceHigh, delay.Delayed)
+		dbms := payload.DBMS
+		finding.Engine = &dbms
+		return finding, errors
+	}
+	return nil, errors
+}
+
+// matchSQLError returns the database engine and the matched text of the first SQL error signature found in body.
+func matchSQLError(body []byte) (string, string) {
+	for _, signature := range sqlErrorSignatures {
+		if match := signature.Pattern.Find(body); match != nil {
+			return signature.DBMS, string(match)
+		}
+	}
+	return "", ""
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length] + "..."
+}
@@ -0,0 +1,165 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+)
+
+const templateTechniqueEvaluation = "expression-evaluation"
+
+// templateFingerprint identifies a single template engine among those that share a probe's syntax. Payload takes a
+// lowercase canary and upper-cases it using a construct only that engine understands.
+type templateFingerprint struct {
+	Engine  string
+	Payload string
+}
+
+// templateProbe is an expression written in the syntax of one family of template engines. Build returns a payload and
+// the output it renders to when evaluated; the output never appears in the payload itself. Engine names the family
+// when none of the fingerprints narrow it down further.
+type templateProbe struct {
+	Engine       string
+	Build        func(a, b int) (string, string)
+	Fingerprints []templateFingerprint
+}
+
+func arithmeticProbe(format string) func(a, b int) (string, string) {
+	return func(a, b int) (string, string) {
+		return fmt.Sprintf(format, a, b), strconv.Itoa(a * b)
+	}
+}
+
+var templateProbes = []templateProbe{
+	{
+		Engine: "Jinja2/Twig/Nunjucks",
+		Build:  arithmeticProbe("{{%d*%d}}"),
+		Fingerprints: []templateFingerprint{
+			{Engine: "Jinja2", Payload: "{{'%s'.upper()}}"},
+			{Engine: "Nunjucks", Payload: "{{'%s'.toUpperCase()}}"},
+			{Engine: "Twig", Payload: "{{'%s'|upper}}"},
+		},
+	},
+	{
+		Engine: "Freemarker/Mako/Expression Language",
+		Build:  arithmeticProbe("${%d*%d}"),
+		Fingerprints: []templateFingerprint{
+			{Engine: "Freemarker", Payload: "${'%s'?upper_case}"},
+			{Engine: "Mako", Payload: "${'%s'.upper()}"},
+			{Engine: "Expression Language (JSP/Spring/Thymeleaf)", Payload: "${'%s'.toUpperCase()}"},
+		},
+	},
+	{
+		Engine: "ERB/EJS",
+		Build:  arithmeticProbe("<%%= %d*%d %%>"),
+		Fingerprints: []templateFingerprint{
+			{Engine: "ERB", Payload: "<%%= '%s'.upcase %%>"},
+			{Engine: "EJS", Payload: "<%%= '%s'.toUpperCase() %%>"},
+		},
+	},
+	{
+		Engine: "Slim/Haml/Pug",
+		Build:  arithmeticProbe("#{%d*%d}"),
+		Fingerprints: []templateFingerprint{
+			{Engine: "Slim/Haml (Ruby)", Payload: "#{'%s'.upcase}"},
+			{Engine: "Pug", Payload: "#{'%s'.toUpperCase()}"},
+		},
+	},
+	{
+		Engine: "Velocity",
+		Build:  arithmeticProbe("#set($w=%d*%d)${w}"),
+	},
+	{
+		Engine: "Smarty",
+		Build:  arithmeticProbe("{%d*%d}"),
+	},
+	{
+		Engine: "Razor",
+		Build:  arithmeticProbe("@(%d*%d)"),
+	},
+	{
+		// Go templates have no arithmetic operators, so concatenate two strings with the print builtin instead
+		Engine: "Go text/template",
+		Build: func(a, b int) (string, string) {
+			first, second := "wsc"+strconv.Itoa(a), "wsc"+strconv.Itoa(b)
+			return fmt.Sprintf("{{print %q %q}}", first, second), first + second
+		},
+	},
+}
+
+// templateInjectionCheck tests every injection point for server-side template injection by sending an expression in
+// the syntax of each template engine family and looking for its evaluated result. When an expression is evaluated,
+// engine specific constructs are used to identify the engine.
+type templateInjectionCheck struct{}
+
+func (c *templateInjectionCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+
+		for _, probe := range templateProbes {
+			a, b := 1000+rand.Intn(9000), 1000+rand.Intn(9000)
+			payload, expected := probe.Build(a, b)
+			if bytes.Contains(s.baseline.Body, []byte(expected)) {
+				continue
+			}
+
+			resp, err := s.sendPayload(ctx, point, point.Value+payload)
+			if err != nil {
+				errors = append(errors, probeError(webscan.VulnTypeTemplate, point, err))
+				continue
+			}
+			index := bytes.Index(resp.Body, []byte(expected))
+			if index < 0 {
+				continue
+			}
+
+			engine, fingerprint, errs := c.identifyEngine(ctx, s, point, probe)
+			errors = append(errors, errs...)
+
+			// Probes without fingerprints use syntax that only one engine understands
+			confidence := webscan.ConfidenceHigh
+			evidence := fmt.Sprintf("expression %s rendered as %s: %s", payload, expected, strings.TrimSpace(excerpt(resp.Body, index, len(expected), 40)))
+			if fingerprint != "" {
+				evidence += fmt.Sprintf("; engine identified by %s", fingerprint)
+			} else if len(probe.Fingerprints) > 0 {
+				confidence = webscan.ConfidenceMedium
+			}
+			finding := newFinding(webscan.VulnTypeTemplate, templateTechniqueEvaluation, point, payload, evidence, confidence, resp)
+			finding.Engine = &engine
+			findings = append(findings, finding)
+			break
+		}
+	}
+
+	return findings, errors
+}
+
+// identifyEngine sends the probe's fingerprints and returns the first engine that evaluated its construct, along with
+// the fingerprint payload that identified it. When no fingerprint matches, the probe's engine family is returned.
+func (c *templateInjectionCheck) identifyEngine(ctx context.Context, s *scanner, point injectionPoint, probe templateProbe) (string, string, []string) {
+	errors := []string{}
+	for _, fingerprint := range probe.Fingerprints {
+		canary := newCanary()
+		payload := fmt.Sprintf(fingerprint.Payload, canary)
+		resp, err := s.sendPayload(ctx, point, point.Value+payload)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeTemplate, point, err))
+			continue
+		}
+		if bytes.Contains(resp.Body, []byte(strings.ToUpper(canary))) {
+			return fingerprint.Engine, payload, errors
+		}
+	}
+	return probe.Engine, "", errors
+}
@@ -0,0 +1,270 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+)
+
+const xssTechniqueReflected = "reflected"
+
+// urlAttributes are the attributes whose values are interpreted as URLs, where a javascript: URI executes on use.
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"data":       true,
+	"poster":     true,
+}
+
+// xssReflection describes where in the page a probe was reflected. Quote is the character delimiting the attribute
+// value for attribute reflections, or the string delimiter enclosing the reflection inside a script.
+type xssReflection struct {
+	Context webscan.ReflectionContext
+	Quote   string
+}
+
+// xssCheck tests every injection point for reflected cross-site scripting. A harmless canary is sent first to find
+// where the parameter is reflected, and payloads written for each of those contexts are then sent to confirm the
+// reflection is not encoded.
+type xssCheck struct{}
+
+func (c *xssCheck) run(ctx context.Context, s *scanner) ([]*webscan.VulnFinding, []string) {
+	findings := []*webscan.VulnFinding{}
+	errors := []string{}
+
+	for _, point := range s.points {
+		if ctx.Err() != nil {
+			errors = append(errors, ctx.Err().Error())
+			break
+		}
+
+		canary := newCanary()
+		resp, err := s.sendPayload(ctx, point, canary)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeXss, point, err))
+			continue
+		}
+		reflections := findReflections(resp.Body, canary)
+
+		for _, reflection := range reflections {
+			finding, errs := c.confirm(ctx, s, point, reflection)
+			errors = append(errors, errs...)
+			if finding != nil {
+				findings = append(findings, finding)
+				break
+			}
+		}
+	}
+
+	return findings, errors
+}
+
+// confirm sends the payloads for the reflection context and returns a finding for the first one reflected verbatim.
+func (c *xssCheck) confirm(ctx context.Context, s *scanner, point injectionPoint, reflection xssReflection) (*webscan.VulnFinding, []string) {
+	errors := []string{}
+	for _, template := range xssPayloads(reflection) {
+		marker := newCanary()
+		payload := fmt.Sprintf(template, marker)
+		resp, err := s.sendPayload(ctx, point, payload)
+		if err != nil {
+			errors = append(errors, probeError(webscan.VulnTypeXss, point, err))
+			continue
+		}
+		index := bytes.Index(resp.Body, []byte(payload))
+		if index < 0 {
+			continue
+		}
+
+		mediaType := responseMediaType(resp)
+		confidence := webscan.ConfidenceHigh
+		switch {
+		case mediaType == "":
+			confidence = webscan.ConfidenceMedium
+		case mediaType != "text/html" && mediaType != "application/xhtml+xml":
+			confidence = webscan.ConfidenceLow
+		}
+
+		evidence := fmt.Sprintf("payload reflected unencoded in %s context of a %s response: %s", reflection.Context, mediaTypeOrUnknown(mediaType), excerpt(resp.Body, index, len(payload), 60))
+		finding := newFinding(webscan.VulnTypeXss, xssTechniqueReflected, point, payload, evidence, confidence, resp)
+		finding.Context = reflection.Context.Ptr()
+		return finding, errors
+	}
+	return nil, errors
+}
+
+// xssPayloads returns the payload templates that escape the given reflection context; each template takes a unique
+// marker so that the reflection of that exact payload can be found in the response.
+func xssPayloads(reflection xssReflection) []string {
+	switch reflection.Context {
+	case webscan.ReflectionContextHtmlAttribute:
+		switch reflection.Quote {
+		case `"`:
+			return []string{`"><svg onload=alert('%s')>`, `" autofocus onfocus="alert('%s')" x="`}
+		case `'`:
+			return []string{`'><svg onload=alert("%s")>`, `' autofocus onfocus='alert("%s")' x='`}
+		default:
+			return []string{`><svg onload=alert('%s')>`, ` autofocus onfocus=alert('%s') `}
+		}
+	case webscan.ReflectionContextUrl:
+		return []string{`javascript:alert('%s')`, `"><svg onload=alert('%s')>`}
+	case webscan.ReflectionContextScript:
+		payloads := []string{`</script><svg onload=alert('%s')>`}
+		switch reflection.Quote {
+		case `"`:
+			payloads = append(payloads, `";alert('%s');//`)
+		case `'`:
+			payloads = append(payloads, `';alert("%s");//`)
+		case "`":
+			payloads = append(payloads, "${alert('%s')}")
+		default:
+			payloads = append(payloads, `;alert('%s');//`)
+		}
+		return payloads
+	case webscan.ReflectionContextHtmlComment:
+		return []string{`--><svg onload=alert('%s')>`}
+	default:
+		return []string{`<svg onload=alert('%s')>`, `<img src=x onerror=alert('%s')>`}
+	}
+}
+
+// findReflections tokenizes the response body as HTML and reports the context of every reflection of the canary.
+func findReflections(body []byte, canary string) []xssReflection {
+	if !bytes.Contains(body, []byte(canary)) {
+		return nil
+	}
+
+	reflections := []xssReflection{}
+	seen := make(map[xssReflection]bool)
+	add := func(reflection xssReflection) {
+		if !seen[reflection] {
+			seen[reflection] = true
+			reflections = append(reflections, reflection)
+		}
+	}
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	rawText := ""
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			break
+		}
+		raw := string(tokenizer.Raw())
+		if !strings.Contains(raw, canary) {
+			if tokenType == html.StartTagToken {
+				name, _ := tokenizer.TagName()
+				rawText = string(name)
+			} else if tokenType == html.EndTagToken {
+				rawText = ""
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			for _, attr := range token.Attr {
+				if !strings.Contains(attr.Val, canary) {
+					continue
+				}
+				if urlAttributes[strings.ToLower(attr.Key)] && strings.HasPrefix(attr.Val, canary) {
+					add(xssReflection{Context: webscan.ReflectionContextUrl, Quote: attributeQuote(raw, attr.Key)})
+				} else {
+					add(xssReflection{Context: webscan.ReflectionContextHtmlAttribute, Quote: attributeQuote(raw, attr.Key)})
+				}
+			}
+			if token.Type == html.StartTagToken {
+				rawText = token.Data
+			}
+		case html.CommentToken:
+			add(xssReflection{Context: webscan.ReflectionContextHtmlComment})
+		case html.TextToken:
+			if rawText == "script" {
+				add(xssReflection{Context: webscan.ReflectionContextScript, Quote: scriptQuote(raw, canary)})
+			} else {
+				add(xssReflection{Context: webscan.ReflectionContextHtmlBody})
+			}
+		default:
+			add(xssReflection{Context: webscan.ReflectionContextHtmlBody})
+		}
+	}
+	return reflections
+}
+
+// attributeQuote returns the quote character that delimits the value of the named attribute in the raw tag.
+func attributeQuote(rawTag string, key string) string {
+	lower := strings.ToLower(rawTag)
+	index := strings.Index(lower, strings.ToLower(key)+"=")
+	if index < 0 {
+		return ""
+	}
+	rest := strings.TrimLeft(rawTag[index+len(key)+1:], " \t\n")
+	if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+		return rest[:1]
+	}
+	return ""
+}
+
+// scriptQuote determines which string literal, if any, encloses the canary by counting the unescaped quote characters
+// that precede it in the script.
+func scriptQuote(script string, canary string) string {
+	prefix := script[:strings.Index(script, canary)]
+	open := ""
+	for i := 0; i < len(prefix); i++ {
+		ch := prefix[i]
+		if ch == '\\' {
+			i++
+			continue
+		}
+		if ch != '"' && ch != '\'' && ch != '`' {
+			continue
+		}
+		if open == "" {
+			open = string(ch)
+		} else if open == string(ch) {
+			open = ""
+		}
+	}
+	return open
+}
+
+func responseMediaType(resp *response) string {
+	mediaType, _, err := mime.ParseMediaType(resp.Headers.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+func mediaTypeOrUnknown(mediaType string) string {
+	if mediaType == "" {
+		return "unknown content type"
+	}
+	return mediaType
+}
+
+// newCanary returns a short random alphanumeric string that is unlikely to appear in a response by chance.
+func newCanary() string {
+	return "wsc" + strings.ReplaceAll(uuid.NewString(), "-", "")[:10]
+}
+
+// excerpt returns the matched bytes of body together with up to padding bytes of surrounding context.
+func excerpt(body []byte, index int, length int, padding int) string {
+	start := index - padding
+	if start < 0 {
+		start = 0
+	}
+	end := index + length + padding
+	if end > len(body) {
+		end = len(body)
+	}
+	return string(body[start:end])
+}
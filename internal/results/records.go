@@ -0,0 +1,333 @@
+package results
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/pipeline"
+	"github.com/Method-Security/webscan/internal/spider"
+	"github.com/Method-Security/webscan/internal/vuln"
+	"github.com/Method-Security/webscan/internal/webserver"
+)
+
+// webServerSeverities are the severities of the findings of the modules of `webscan webserver`.
+var webServerSeverities = map[webscan.ModuleName]string{
+	webscan.ModuleNameBufferOverflowContentHeader:  "high",
+	webscan.ModuleNameCrlfInjection:                "medium",
+	webscan.ModuleNamePathTraversal:                "high",
+	webscan.ModuleNameRceModFile:                   "critical",
+	webscan.ModuleNameReverseProxyMisconfiguration: "medium",
+	webscan.ModuleNameXPoweredByHeaderGrab:         "info",
+}
+
+// requestSeverities are the severities of the findings of the vulnerability checks of `webscan app requests`.
+var requestSeverities = map[webscan.VulnType]string{
+	webscan.VulnTypeSql:            "high",
+	webscan.VulnTypeSqlinjection:   "high",
+	webscan.VulnTypeXss:            "medium",
+	webscan.VulnTypeCommand:        "critical",
+	webscan.VulnTypeTemplate:       "critical",
+	webscan.VulnTypeNosql:          "high",
+	webscan.VulnTypeAuth:           "high",
+	webscan.VulnTypeSensitiveerror: "low",
+	webscan.VulnTypeMassassignment: "medium",
+}
+
+// authzSeverity is the severity of the findings of `webscan app authz`.
+const authzSeverity = "high"
+
+// Records returns the records of the report of a command. Reports that hold none of the recorded items, such as diff
+// reports, have no records.
+func Records(content interface{}) []*webscan.ResultRecord {
+	b := &builder{records: []*webscan.ResultRecord{}}
+	b.addReport(content)
+	return b.records
+}
+
+type builder struct {
+	records []*webscan.ResultRecord
+}
+
+func (b *builder) add(recordType webscan.ResultRecordType, target string, data interface{}) *webscan.ResultRecord {
+	record := &webscan.ResultRecord{Type: recordType, Target: target, Data: data}
+	b.records = append(b.records, record)
+	return record
+}
+
+func (b *builder) addTarget(target string) {
+	if target != "" {
+		b.add(webscan.ResultRecordTypeTarget, target, nil)
+	}
+}
+
+func (b *builder) addReport(content interface{}) {
+	switch report := content.(type) {
+	case vuln.VulnerabilityReport:
+		b.addVulnerabilityReport(&report)
+	case *vuln.VulnerabilityReport:
+		b.addVulnerabilityReport(report)
+	case webserver.ProbeReport:
+		b.addProbeReport(&report)
+	case *webserver.ProbeReport:
+		b.addProbeReport(report)
+	case spider.WebSpiderReport:
+		b.addSpiderReport(&report)
+	case *spider.WebSpiderReport:
+		b.addSpiderReport(report)
+	case webscan.WebServerReport:
+		b.addWebServerReport(&report)
+	case *webscan.WebServerReport:
+		b.addWebServerReport(report)
+	case webscan.FuzzPathReport:
+		b.addFuzzPathReport(&report)
+	case *webscan.FuzzPathReport:
+		b.addFuzzPathReport(report)
+	case webscan.FingerprintReport:
+		b.addFingerprintReport(&report)
+	case *webscan.FingerprintReport:
+		b.addFingerprintReport(report)
+	case webscan.RoutesReport:
+		b.addRoutesReport(&report)
+	case *webscan.RoutesReport:
+		b.addRoutesReport(report)
+	case webscan.RouteCaptureReport:
+		b.addRouteCaptureReport(&report)
+	case *webscan.RouteCaptureReport:
+		b.addRouteCaptureReport(report)
+	case webscan.PageScreenshotReport:
+		b.addScreenshotReport(&report)
+	case *webscan.PageScreenshotReport:
+		b.addScreenshotReport(report)
+	case webscan.PageCaptureReport:
+		b.addTarget(report.Target)
+	case *webscan.PageCaptureReport:
+		b.addTarget(report.Target)
+	case webscan.RequestReport:
+		b.addTarget(report.BaseUrl)
+		b.addRequestReport(report.BaseUrl, &report)
+	case *webscan.RequestReport:
+		b.addTarget(report.BaseUrl)
+		b.addRequestReport(report.BaseUrl, report)
+	case webscan.RequestBatchReport:
+		b.addRequestBatchReport(&report)
+	case *webscan.RequestBatchReport:
+		b.addRequestBatchReport(report)
+	case webscan.AuthzReport:
+		b.addAuthzReport(&report)
+	case *webscan.AuthzReport:
+		b.addAuthzReport(report)
+	case pipeline.Report:
+		b.addPipelineReport(&report)
+	case *pipeline.Report:
+		b.addPipelineReport(report)
+	}
+}
+
+func (b *builder) addVulnerabilityReport(report *vuln.VulnerabilityReport) {
+	b.addTarget(report.Target)
+	for _, finding := range report.Reports {
+		uri := finding.Context.FullPath
+		if uri == "" {
+			uri = finding.Context.URL
+		}
+		record := b.add(webscan.ResultRecordTypeFinding, report.Target, finding)
+		record.Module = stringPtr(finding.Context.TemplateID)
+		record.Severity = stringPtr(finding.Info.SeverityHolder.Severity.String())
+		record.Name = stringPtr(finding.Info.Name)
+		record.Url = stringPtr(uri)
+	}
+}
+
+func (b *builder) addProbeReport(report *webserver.ProbeReport) {
+	for _, target := range report.Targets {
+		b.addTarget(target)
+	}
+	for _, details := range report.URLs {
+		b.add(webscan.ResultRecordTypeUrl, originOf(details.URL), details).Url = stringPtr(details.URL)
+	}
+}
+
+func (b *builder) addSpiderReport(report *spider.WebSpiderReport) {
+	for _, target := range report.Targets {
+		b.addTarget(target)
+	}
+	for _, details := range report.Links {
+		b.add(webscan.ResultRecordTypeUrl, originOf(details.Link), details).Url = stringPtr(details.Link)
+	}
+}
+
+func (b *builder) addWebServerReport(report *webscan.WebServerReport) {
+	for _, server := range report.WebServers {
+		if server == nil {
+			continue
+		}
+		b.addTarget(server.Target)
+		for _, attempt := range server.Attempts {
+			if attempt == nil || !attempt.Finding {
+				continue
+			}
+			record := b.add(webscan.ResultRecordTypeFinding, server.Target, attempt)
+			record.Module = stringPtr(string(attempt.Name))
+			record.Name = stringPtr(fmt.Sprintf("%s %s %s", report.Server, report.Probe, attempt.Name))
+			record.Url = stringPtr(server.Target)
+			if severity, ok := webServerSeverities[attempt.Name]; ok {
+				record.Severity = stringPtr(severity)
+			}
+		}
+	}
+}
+
+func (b *builder) addFuzzPathReport(report *webscan.FuzzPathReport) {
+	b.addTarget(report.Target)
+	for _, details := range report.Urls {
+		if details != nil {
+			b.add(webscan.ResultRecordTypeUrl, report.Target, details).Url = stringPtr(details.Url)
+		}
+	}
+}
+
+func (b *builder) addFingerprintReport(report *webscan.FingerprintReport) {
+	b.addTarget(report.Target)
+	for _, tlsInfo := range []*webscan.TlsInfo{report.TlsInfo, report.RedirectTlsInfo} {
+		if tlsInfo == nil {
+			continue
+		}
+		for _, certificate := range tlsInfo.Certificates {
+			if certificate != nil {
+				b.add(webscan.ResultRecordTypeCertificate, report.Target, certificate).Name = certificate.SubjectCommonName
+			}
+		}
+	}
+}
+
+func (b *builder) addRoutesReport(report *webscan.RoutesReport) {
+	b.addTarget(report.Target)
+	for _, route := range report.Routes {
+		if route == nil {
+			continue
+		}
+		record := b.add(webscan.ResultRecordTypeRoute, report.Target, route)
+		record.Url = stringPtr(joinURL(report.BaseEndpointUrl, route.Path))
+		record.Method = stringPtr(strings.ToUpper(route.Method))
+		record.Module = stringPtr(string(report.AppType))
+	}
+}
+
+func (b *builder) addRouteCaptureReport(report *webscan.RouteCaptureReport) {
+	b.addTarget(report.Target)
+	for _, route := range report.Routes {
+		if route == nil {
+			continue
+		}
+		record := b.add(webscan.ResultRecordTypeRoute, report.Target, route)
+		record.Url = stringPtr(route.Url)
+		if route.Method != nil {
+			record.Method = stringPtr(string(*route.Method))
+		}
+	}
+	for _, u := range report.Urls {
+		b.add(webscan.ResultRecordTypeUrl, report.Target, nil).Url = stringPtr(u)
+	}
+}
+
+func (b *builder) addScreenshotReport(report *webscan.PageScreenshotReport) {
+	b.addTarget(report.Target)
+	if report.Screenshot != nil {
+		b.add(webscan.ResultRecordTypeScreenshot, report.Target, report.Screenshot).Url = stringPtr(report.Target)
+	}
+}
+
+func (b *builder) addRequestReport(target string, report *webscan.RequestReport) {
+	for _, finding := range report.Findings {
+		if finding == nil {
+			continue
+		}
+		record := b.add(webscan.ResultRecordTypeFinding, target, finding)
+		record.Module = stringPtr(string(finding.VulnType))
+		record.Name = stringPtr(fmt.Sprintf("%s (%s) in %s parameter %s", finding.VulnType, finding.Technique,
+			strings.ToLower(string(finding.Location)), finding.Parameter))
+		record.Url = stringPtr(joinURL(report.BaseUrl, report.Path))
+		record.Method = stringPtr(string(report.Method))
+		if severity, ok := requestSeverities[finding.VulnType]; ok {
+			record.Severity = stringPtr(severity)
+		}
+	}
+}
+
+func (b *builder) addRequestBatchReport(report *webscan.RequestBatchReport) {
+	b.addTarget(report.Target)
+	for _, result := range report.Results {
+		if result != nil && result.Report != nil {
+			b.addRequestReport(report.Target, result.Report)
+		}
+	}
+}
+
+func (b *builder) addAuthzReport(report *webscan.AuthzReport) {
+	b.addTarget(report.Target)
+	for _, finding := range report.Findings {
+		if finding == nil {
+			continue
+		}
+		method, path := splitRoute(finding.Route)
+		record := b.add(webscan.ResultRecordTypeFinding, report.Target, finding)
+		record.Module = stringPtr("BOLA")
+		record.Severity = stringPtr(authzSeverity)
+		record.Name = stringPtr(fmt.Sprintf("Broken object level authorization (%s)", finding.Technique))
+		record.Url = stringPtr(joinURL(report.BaseUrl, path))
+		record.Method = stringPtr(method)
+	}
+}
+
+// addPipelineReport adds the records of the reports of every stage of a pipeline run.
+func (b *builder) addPipelineReport(report *pipeline.Report) {
+	for _, stage := range report.Stages {
+		for _, stageReport := range stage.Reports {
+			b.addReport(stageReport)
+		}
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func joinURL(baseURL string, path string) string {
+	if path == "" {
+		return baseURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// splitRoute splits a route such as "GET /users/{id}" into its method and path.
+func splitRoute(route string) (string, string) {
+	if method, path, ok := strings.Cut(route, " "); ok {
+		return method, path
+	}
+	return "", route
+}
+
+// originOf returns the scheme and host of a URL, the target of the URLs of reports of several targets.
+func originOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// hostOf returns the host name of a target, which is either a URL or a host with an optional port, along with its host
+// and port.
+func hostOf(target string) (string, string) {
+	rawURL := target
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "//" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return target, target
+	}
+	return parsed.Hostname(), parsed.Host
+}
@@ -0,0 +1,212 @@
+// Package results keeps the history of webscan runs in a local bbolt database. Every run saved to the store records
+// the targets, URLs, routes, findings, certificates and screenshots of its report, which can then be queried across
+// runs, e.g. to find every host that ever exposed a path.
+package results
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	runsBucket    = []byte("runs")
+	recordsBucket = []byte("records")
+)
+
+// Store persists runs and their records in a bbolt database. Runs are stored as JSON by ID, which are time ordered
+// UUIDs, and the records of a run in a nested bucket keyed by their sequence number.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens the results database at path, creating it when it does not exist.
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results database %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{runsBucket, recordsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize results database %s: %v", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Save saves a run along with the records of its report. The ID of the run is set, as well as the run, command and
+// timestamp of the records.
+func (s *Store) Save(run *webscan.ResultRun, records []*webscan.ResultRecord) error {
+	run.Id = uuid.Must(uuid.NewV7()).String()
+	run.Records = len(records)
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode run: %v", err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(runsBucket).Put([]byte(run.Id), data); err != nil {
+			return err
+		}
+		bucket, err := tx.Bucket(recordsBucket).CreateBucket([]byte(run.Id))
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			record.Run, record.Command, record.Timestamp = run.Id, run.Command, run.StartedAt
+			data, err := json.Marshal(record)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s record of run %s: %v", record.Type, run.Id, err)
+			}
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(sequenceKey(seq), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Query selects records of the saved runs. Empty fields of a query select every record.
+type Query struct {
+	Types      []webscan.ResultRecordType
+	Targets    []string
+	Severities []string
+	Modules    []string
+	// URL selects the records whose URL contains it
+	URL string
+	// Since and Until select the records of the runs that started in their range
+	Since *time.Time
+	Until *time.Time
+	// Limit is the maximum number of records returned, 0 for no limit
+	Limit int
+}
+
+// Query returns the records selected by the query, those of the most recently saved runs first, along with their runs.
+func (s *Store) Query(query Query) (*webscan.ResultsQueryReport, error) {
+	report := &webscan.ResultsQueryReport{Records: []*webscan.ResultRecord{}, Runs: []*webscan.ResultRun{}}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(runsBucket).Cursor()
+		for key, data := cursor.Last(); key != nil; key, data = cursor.Prev() {
+			run := &webscan.ResultRun{}
+			if err := json.Unmarshal(data, run); err != nil {
+				return fmt.Errorf("failed to decode run %s: %v", key, err)
+			}
+			if (query.Since != nil && run.StartedAt.Before(*query.Since)) || (query.Until != nil && run.StartedAt.After(*query.Until)) {
+				continue
+			}
+
+			bucket := tx.Bucket(recordsBucket).Bucket(key)
+			if bucket == nil {
+				continue
+			}
+			matched := false
+			err := bucket.ForEach(func(_, data []byte) error {
+				record := &webscan.ResultRecord{}
+				if err := json.Unmarshal(data, record); err != nil {
+					return fmt.Errorf("failed to decode record of run %s: %v", key, err)
+				}
+				if !query.matches(record) {
+					return nil
+				}
+				report.Total++
+				if query.Limit <= 0 || len(report.Records) < query.Limit {
+					report.Records = append(report.Records, record)
+					matched = true
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if matched {
+				report.Runs = append(report.Runs, run)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query results: %v", err)
+	}
+	return report, nil
+}
+
+// matches reports whether the query selects the record. Targets match case-insensitively by their host or URL, and
+// severities and modules case-insensitively.
+func (q Query) matches(record *webscan.ResultRecord) bool {
+	if len(q.Types) > 0 && !containsType(q.Types, record.Type) {
+		return false
+	}
+	if len(q.Targets) > 0 && !matchesTarget(q.Targets, record.Target) {
+		return false
+	}
+	if len(q.Severities) > 0 && (record.Severity == nil || !containsFold(q.Severities, *record.Severity)) {
+		return false
+	}
+	if len(q.Modules) > 0 && (record.Module == nil || !containsFold(q.Modules, *record.Module)) {
+		return false
+	}
+	if q.URL != "" && (record.Url == nil || !strings.Contains(*record.Url, q.URL)) {
+		return false
+	}
+	return true
+}
+
+func containsType(types []webscan.ResultRecordType, recordType webscan.ResultRecordType) bool {
+	for _, t := range types {
+		if t == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTarget reports whether the target is one of the targets, or has the host of one of them. Targets with a port
+// only match the host with that port.
+func matchesTarget(targets []string, target string) bool {
+	hostname, host := hostOf(target)
+	for _, t := range targets {
+		queryHostname, queryHost := hostOf(t)
+		if strings.EqualFold(t, target) || (queryHost != queryHostname && strings.EqualFold(queryHost, host)) ||
+			(queryHost == queryHostname && strings.EqualFold(queryHostname, hostname)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sequenceKey encodes the sequence number of a record as a key that sorts in sequence order.
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
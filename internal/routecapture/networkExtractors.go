@@ -7,7 +7,7 @@ import (
 
 	webscan "github.com/Method-Security/webscan/generated/go"
 	capture "github.com/Method-Security/webscan/internal/capture"
-	"github.com/go-rod/rod"
+	"github.com/Method-Security/webscan/internal/httpclient"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 )
@@ -20,6 +20,8 @@ func extractNetworkRoutes(ctx context.Context, b *capture.BrowserPageCapturer, t
 	log := svc1log.FromContext(ctx)
 
 	log.Info("Initiating network events capture with browser method", svc1log.SafeParam("target", target))
+	// The network events of the page are recorded apart from the page capture of the command
+	ctx = httpclient.WithModule(ctx, "routecapture network")
 	// Ensure the browser is initialized
 	if b.Browser == nil {
 		log.Debug("Initializing browser for network capture")
@@ -30,10 +32,7 @@ func extractNetworkRoutes(ctx context.Context, b *capture.BrowserPageCapturer, t
 	pageCtx, cancel := context.WithTimeout(ctx, time.Duration(b.TimeoutSeconds)*time.Second)
 	defer cancel()
 
-	var page *rod.Page
-	pageErr := rod.Try(func() {
-		page = b.Browser.MustPage(target).Context(pageCtx)
-	})
+	page, pageErr := capture.OpenPage(pageCtx, b.Browser, target)
 	if pageErr != nil {
 		log.Error("Failed to create page", svc1log.SafeParam("url", target), svc1log.SafeParam("error", pageErr))
 		errors = append(errors, pageErr.Error())
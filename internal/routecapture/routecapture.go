@@ -2,13 +2,13 @@ package routecapture
 
 import (
 	"context"
-	"net/http"
 	"strings"
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
 	"github.com/Method-Security/webscan/internal/browserbase"
 	capture "github.com/Method-Security/webscan/internal/capture"
+	"github.com/Method-Security/webscan/internal/httpclient"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
 )
@@ -26,9 +26,7 @@ func extractRoutes(ctx context.Context, target string, htmlContent string, baseU
 	}
 
 	// Initialize an HTTP client for getting javascript content
-	httpClient := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
-	}
+	httpClient := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(timeout) * time.Second))
 
 	// Extract routes from form elements
 	formRoutes, formUrls, formErrors := extractFormRoutes(doc, target, baseURLsOnly, captureStaticAssets)
@@ -91,7 +89,7 @@ func PerformRouteCapture(ctx context.Context, target string, captureMethod websc
 	switch captureMethod {
 	case webscan.PageCaptureMethodRequest:
 		log.Info("Initiating page capture with request method", svc1log.SafeParam("target", target))
-		capturer := capture.NewRequestPageCapturer(insecure, timeout)
+		capturer := capture.NewRequestPageCapturer(ctx, insecure, timeout)
 		result, err := capturer.Capture(ctx, target, &capture.Options{})
 		if err != nil {
 			report.Errors = append(report.Errors, err.Error())
@@ -0,0 +1,355 @@
+package sarif
+
+import (
+	"fmt"
+	"strings"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/pipeline"
+	"github.com/Method-Security/webscan/internal/vuln"
+	"github.com/projectdiscovery/nuclei/v3/pkg/model/types/severity"
+)
+
+// requestRules describes the vulnerability checks of `webscan app requests`.
+var requestRules = map[webscan.VulnType]ruleDefinition{
+	webscan.VulnTypeSql:            {Name: "SQL injection", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-89"}},
+	webscan.VulnTypeXss:            {Name: "Reflected cross-site scripting", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-79"}},
+	webscan.VulnTypeCommand:        {Name: "OS command injection", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-78"}},
+	webscan.VulnTypeTemplate:       {Name: "Server-side template injection", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-1336"}},
+	webscan.VulnTypeNosql:          {Name: "NoSQL injection", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-943"}},
+	webscan.VulnTypeAuth:           {Name: "Authentication bypass", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-287"}},
+	webscan.VulnTypeSensitiveerror: {Name: "Sensitive information in error responses", Level: LevelWarning, Tags: []string{"security", "external/cwe/cwe-209"}},
+	webscan.VulnTypeMassassignment: {Name: "Mass assignment", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-915"}},
+}
+
+var authzRule = ruleDefinition{
+	ID:          "authz/BOLA",
+	Name:        "Broken object level authorization",
+	Description: "An identity was able to access an object that belongs to another identity.",
+	Level:       LevelError,
+	Tags:        []string{"security", "external/cwe/cwe-639"},
+}
+
+var fuzzRule = ruleDefinition{
+	ID:          "fuzz/discovered-path",
+	Name:        "Discovered path",
+	Description: "A path from the wordlist responded with one of the accepted status codes.",
+	Level:       LevelNote,
+}
+
+// webServerRules describes the modules of `webscan webserver`.
+var webServerRules = map[webscan.ModuleName]ruleDefinition{
+	webscan.ModuleNameBufferOverflowContentHeader:  {Name: "Buffer overflow through the Content header", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-120"}},
+	webscan.ModuleNameCrlfInjection:                {Name: "CRLF injection", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-93"}},
+	webscan.ModuleNamePathTraversal:                {Name: "Path traversal", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-22"}},
+	webscan.ModuleNameRceModFile:                   {Name: "Remote code execution through a module file", Level: LevelError, Tags: []string{"security", "external/cwe/cwe-94"}},
+	webscan.ModuleNameReverseProxyMisconfiguration: {Name: "Reverse proxy misconfiguration", Level: LevelWarning, Tags: []string{"security"}},
+	webscan.ModuleNameXPoweredByHeaderGrab:         {Name: "Version disclosed in X-Powered-By header", Level: LevelNote, Tags: []string{"security", "external/cwe/cwe-200"}},
+}
+
+// Convert builds the SARIF log of a webscan report from the content, status and error message of a command's output
+// signal. Reports of commands that produce no findings, such as enumeration and capture reports, are not supported.
+func Convert(content interface{}, version string, status int, errorMessage *string) (*Log, error) {
+	b := newBuilder()
+	if errorMessage != nil && *errorMessage != "" {
+		b.addErrors([]string{*errorMessage})
+	}
+	switch report := content.(type) {
+	case nil:
+	case vuln.VulnerabilityReport:
+		b.addVulnerabilityReport(report)
+	case *vuln.VulnerabilityReport:
+		b.addVulnerabilityReport(*report)
+	case webscan.RequestReport:
+		b.addRequestReport(&report)
+	case *webscan.RequestReport:
+		b.addRequestReport(report)
+	case webscan.RequestBatchReport:
+		b.addRequestBatchReport(&report)
+	case *webscan.RequestBatchReport:
+		b.addRequestBatchReport(report)
+	case webscan.AuthzReport:
+		b.addAuthzReport(&report)
+	case *webscan.AuthzReport:
+		b.addAuthzReport(report)
+	case webscan.WebServerReport:
+		b.addWebServerReport(&report)
+	case *webscan.WebServerReport:
+		b.addWebServerReport(report)
+	case webscan.FuzzPathReport:
+		b.addFuzzPathReport(&report)
+	case *webscan.FuzzPathReport:
+		b.addFuzzPathReport(report)
+	case pipeline.Report:
+		b.addPipelineReport(&report)
+	case *pipeline.Report:
+		b.addPipelineReport(report)
+	default:
+		return nil, fmt.Errorf("sarif output is not supported for %T reports", content)
+	}
+	return b.log(version, status == 0), nil
+}
+
+func (b *builder) addVulnerabilityReport(report vuln.VulnerabilityReport) {
+	for _, result := range report.Reports {
+		rule := ruleDefinition{
+			ID:          "nuclei/" + result.Context.TemplateID,
+			Name:        result.Info.Name,
+			Description: strings.TrimSpace(result.Info.Description),
+			Level:       severityLevel(result.Info.SeverityHolder.Severity),
+			Tags:        append([]string{"security"}, result.Info.Tags.ToSlice()...),
+		}
+		if result.Info.Reference != nil {
+			if references := result.Info.Reference.ToSlice(); len(references) > 0 {
+				rule.HelpURI = references[0]
+			}
+		}
+
+		uri := result.Context.FullPath
+		if uri == "" {
+			uri = result.Context.URL
+		}
+		message := result.Info.Name
+		if len(result.Context.ExtractedResults) > 0 {
+			message += ": " + strings.Join(result.Context.ExtractedResults, ", ")
+		}
+		b.addResult(rule, finding{
+			Message: message,
+			URI:     uri,
+			Key:     result.ID,
+			Properties: map[string]interface{}{
+				"severity": result.Info.SeverityHolder.Severity.String(),
+				"host":     result.Context.Host,
+			},
+		})
+	}
+}
+
+func (b *builder) addRequestReport(report *webscan.RequestReport) {
+	uri := joinURL(report.BaseUrl, report.Path)
+	for _, vulnFinding := range report.Findings {
+		if vulnFinding == nil {
+			continue
+		}
+		vulnType := vulnFinding.VulnType
+		if vulnType == webscan.VulnTypeSqlinjection {
+			vulnType = webscan.VulnTypeSql
+		}
+		rule, ok := requestRules[vulnType]
+		if !ok {
+			rule = ruleDefinition{Name: string(vulnType), Level: LevelWarning}
+		}
+		rule.ID = "requests/" + string(vulnType)
+
+		b.addResult(rule, finding{
+			Level: confidenceLevel(rule.Level, vulnFinding.Confidence),
+			Message: fmt.Sprintf("%s (%s) in %s parameter %s: %s", rule.Name, vulnFinding.Technique,
+				strings.ToLower(string(vulnFinding.Location)), vulnFinding.Parameter, vulnFinding.Evidence),
+			URI: uri,
+			Key: strings.Join([]string{string(report.Method), string(vulnFinding.Location), vulnFinding.Parameter, vulnFinding.Technique}, " "),
+			Properties: map[string]interface{}{
+				"method":     string(report.Method),
+				"technique":  vulnFinding.Technique,
+				"location":   string(vulnFinding.Location),
+				"parameter":  vulnFinding.Parameter,
+				"payload":    vulnFinding.Payload,
+				"confidence": string(vulnFinding.Confidence),
+				"engine":     vulnFinding.Engine,
+				"framework":  vulnFinding.Framework,
+				"statusCode": vulnFinding.StatusCode,
+			},
+		})
+	}
+	b.addErrors(report.Errors)
+}
+
+func (b *builder) addRequestBatchReport(report *webscan.RequestBatchReport) {
+	for _, result := range report.Results {
+		if result != nil && result.Report != nil {
+			b.addRequestReport(result.Report)
+		}
+	}
+	b.addErrors(report.Errors)
+}
+
+func (b *builder) addAuthzReport(report *webscan.AuthzReport) {
+	for _, authzFinding := range report.Findings {
+		if authzFinding == nil {
+			continue
+		}
+		method, path := splitRoute(authzFinding.Route)
+		key := []string{method, string(authzFinding.Technique), authzFinding.Owner, authzFinding.Attacker}
+		if authzFinding.Parameter != nil && authzFinding.TestedValue != nil {
+			key = append(key, *authzFinding.Parameter, *authzFinding.TestedValue)
+		}
+		b.addResult(authzRule, finding{
+			Level:   confidenceLevel(authzRule.Level, authzFinding.Confidence),
+			Message: fmt.Sprintf("%s (%s): %s", authzRule.Name, authzFinding.Technique, authzFinding.Evidence),
+			URI:     joinURL(report.BaseUrl, path),
+			Key:     strings.Join(key, " "),
+			Properties: map[string]interface{}{
+				"method":        method,
+				"technique":     string(authzFinding.Technique),
+				"owner":         authzFinding.Owner,
+				"attacker":      authzFinding.Attacker,
+				"parameter":     authzFinding.Parameter,
+				"originalValue": authzFinding.OriginalValue,
+				"testedValue":   authzFinding.TestedValue,
+				"confidence":    string(authzFinding.Confidence),
+				"statusCode":    authzFinding.StatusCode,
+			},
+		})
+	}
+	b.addErrors(report.Errors)
+}
+
+func (b *builder) addWebServerReport(report *webscan.WebServerReport) {
+	for _, server := range report.WebServers {
+		if server == nil {
+			continue
+		}
+		for _, attempt := range server.Attempts {
+			if attempt == nil {
+				continue
+			}
+			rule, ok := webServerRules[attempt.Name]
+			if !ok {
+				rule = ruleDefinition{Name: string(attempt.Name), Level: LevelWarning}
+			}
+			rule.ID = "webserver/" + string(attempt.Name)
+			properties := map[string]interface{}{
+				"server": string(report.Server),
+				"probe":  string(report.Probe),
+			}
+
+			info := attempt.AttemptInfo
+			switch {
+			case info != nil && info.MultiplePathsAttempt != nil:
+				for _, path := range info.MultiplePathsAttempt.Paths {
+					if path == nil || !((path.Finding == nil && attempt.Finding) || (path.Finding != nil && *path.Finding)) {
+						continue
+					}
+					uri := joinURL(server.Target, path.Path)
+					if path.Request != nil && path.Request.Url != "" {
+						uri = path.Request.Url
+					}
+					b.addResult(rule, finding{
+						Message:    fmt.Sprintf("%s at %s", rule.Name, path.Path),
+						URI:        uri,
+						Key:        path.Path,
+						Properties: withStatusCode(properties, path.Response),
+					})
+				}
+			case !attempt.Finding:
+			case info != nil && info.VersionAttempt != nil:
+				message := rule.Name
+				uri := server.Target
+				if info.VersionAttempt.Request != nil && info.VersionAttempt.Request.Url != "" {
+					uri = info.VersionAttempt.Request.Url
+				}
+				if response := info.VersionAttempt.Response; response != nil && response.VersionNumber != nil {
+					message = fmt.Sprintf("%s: %s", rule.Name, *response.VersionNumber)
+					if response.VersionType != nil {
+						message = fmt.Sprintf("%s: %s %s", rule.Name, *response.VersionType, *response.VersionNumber)
+					}
+				}
+				b.addResult(rule, finding{Message: message, URI: uri, Properties: properties})
+			default:
+				uri := server.Target
+				var response *webscan.GeneralResponseInfo
+				if info != nil && info.GeneralAttempt != nil {
+					if info.GeneralAttempt.Request != nil && info.GeneralAttempt.Request.Url != "" {
+						uri = info.GeneralAttempt.Request.Url
+					}
+					response = info.GeneralAttempt.Response
+				}
+				b.addResult(rule, finding{Message: rule.Name, URI: uri, Properties: withStatusCode(properties, response)})
+			}
+		}
+	}
+	b.addErrors(report.Errors)
+}
+
+func (b *builder) addFuzzPathReport(report *webscan.FuzzPathReport) {
+	for _, url := range report.Urls {
+		if url == nil {
+			continue
+		}
+		b.addResult(fuzzRule, finding{
+			Message: fmt.Sprintf("%s responded with status %s (%d bytes)", url.Url, url.Status, url.Size),
+			URI:     url.Url,
+			Properties: map[string]interface{}{
+				"status": url.Status,
+				"size":   url.Size,
+			},
+		})
+	}
+	b.addErrors(report.Errors)
+}
+
+// addPipelineReport adds the findings of the reports of every stage of a pipeline run, along with its errors.
+func (b *builder) addPipelineReport(report *pipeline.Report) {
+	for _, stage := range report.Stages {
+		for _, stageReport := range stage.Reports {
+			switch stageReport := stageReport.(type) {
+			case *vuln.VulnerabilityReport:
+				b.addVulnerabilityReport(*stageReport)
+			case *webscan.FuzzPathReport:
+				b.addFuzzPathReport(stageReport)
+			}
+		}
+	}
+	b.addErrors(report.Errors)
+}
+
+// severityLevel maps the severity of a nuclei template to a result level.
+func severityLevel(value severity.Severity) string {
+	switch value {
+	case severity.Critical, severity.High:
+		return LevelError
+	case severity.Medium:
+		return LevelWarning
+	default:
+		return LevelNote
+	}
+}
+
+// confidenceLevel lowers the level of a rule for findings that were not confirmed with high confidence.
+func confidenceLevel(level string, confidence webscan.Confidence) string {
+	switch confidence {
+	case webscan.ConfidenceLow:
+		return LevelNote
+	case webscan.ConfidenceMedium:
+		if level == LevelError {
+			return LevelWarning
+		}
+	}
+	return level
+}
+
+func withStatusCode(properties map[string]interface{}, response *webscan.GeneralResponseInfo) map[string]interface{} {
+	if response == nil {
+		return properties
+	}
+	withStatus := map[string]interface{}{"statusCode": response.StatusCode}
+	for key, value := range properties {
+		withStatus[key] = value
+	}
+	return withStatus
+}
+
+// joinURL appends a path to a base URL without doubling the slash between them.
+func joinURL(baseURL string, path string) string {
+	if path == "" {
+		return baseURL
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// splitRoute splits a route such as "GET /users/{id}" into its method and path.
+func splitRoute(route string) (string, string) {
+	if method, path, ok := strings.Cut(route, " "); ok {
+		return method, path
+	}
+	return "", route
+}
@@ -0,0 +1,261 @@
+// Package sarif converts webscan reports into SARIF 2.1.0 logs so that their findings can be ingested by GitHub code
+// scanning and other static analysis result consumers. Each kind of finding becomes a rule, and each finding a result
+// located at the URL it was found on.
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	// Version is the SARIF specification version of the logs.
+	Version = "2.1.0"
+	// Schema is the JSON schema of SARIF 2.1.0 logs.
+	Schema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	toolName           = "webscan"
+	toolInformationURI = "https://github.com/Method-Security/webscan"
+)
+
+// Result levels defined by SARIF.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+	LevelNone    = "none"
+)
+
+// Log is the top level object of a SARIF file.
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run holds the results of a single invocation of webscan.
+type Run struct {
+	Tool        Tool         `json:"tool"`
+	Invocations []Invocation `json:"invocations,omitempty"`
+	Results     []Result     `json:"results"`
+}
+
+// Tool describes webscan and the rules its results refer to.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the component of the tool that produced the results.
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules"`
+}
+
+// Rule describes a kind of finding.
+type Rule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     Message                `json:"shortDescription"`
+	FullDescription      *Message               `json:"fullDescription,omitempty"`
+	HelpURI              string                 `json:"helpUri,omitempty"`
+	DefaultConfiguration Configuration          `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Configuration holds the default level of a rule.
+type Configuration struct {
+	Level string `json:"level"`
+}
+
+// Message is a plain text message.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is a single finding.
+type Result struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             Message                `json:"message"`
+	Locations           []Location             `json:"locations,omitempty"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints,omitempty"`
+	Properties          map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Location is where a result was found.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation refers to the artifact a result was found in, for webscan the URL.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+// ArtifactLocation holds the URI of an artifact.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Invocation describes whether the run succeeded and the errors it reported.
+type Invocation struct {
+	ExecutionSuccessful        bool           `json:"executionSuccessful"`
+	ToolExecutionNotifications []Notification `json:"toolExecutionNotifications,omitempty"`
+}
+
+// Notification is an error reported by the run.
+type Notification struct {
+	Level   string  `json:"level"`
+	Message Message `json:"message"`
+}
+
+// ruleDefinition is a rule along with the level of its results when no other level is known.
+type ruleDefinition struct {
+	ID          string
+	Name        string
+	Description string
+	HelpURI     string
+	Level       string
+	Tags        []string
+}
+
+// finding is a result before it is attached to its rule. Key identifies the finding at its URI across runs, e.g. the
+// parameter it was found in, and an empty level defaults to the level of the rule.
+type finding struct {
+	Level      string
+	Message    string
+	URI        string
+	Key        string
+	Properties map[string]interface{}
+}
+
+// builder accumulates the rules and results of a run.
+type builder struct {
+	rules     []Rule
+	ruleIndex map[string]int
+	results   []Result
+	errors    []string
+}
+
+func newBuilder() *builder {
+	return &builder{ruleIndex: make(map[string]int)}
+}
+
+// addResult records a result for the rule, adding the rule to the run the first time it is used.
+func (b *builder) addResult(rule ruleDefinition, f finding) {
+	index, ok := b.ruleIndex[rule.ID]
+	if !ok {
+		index = len(b.rules)
+		b.ruleIndex[rule.ID] = index
+		sarifRule := Rule{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     Message{Text: rule.Name},
+			HelpURI:              rule.HelpURI,
+			DefaultConfiguration: Configuration{Level: rule.Level},
+		}
+		if rule.Description != "" {
+			sarifRule.FullDescription = &Message{Text: rule.Description}
+		}
+		if len(rule.Tags) > 0 {
+			sarifRule.Properties = map[string]interface{}{"tags": rule.Tags}
+		}
+		b.rules = append(b.rules, sarifRule)
+	}
+	level := f.Level
+	if level == "" {
+		level = rule.Level
+	}
+
+	result := Result{
+		RuleID:    rule.ID,
+		RuleIndex: index,
+		Level:     level,
+		Message:   Message{Text: f.Message},
+		PartialFingerprints: map[string]string{
+			"webscanFindingHash/v1": fingerprint(rule.ID, f.URI, f.Key),
+		},
+		Properties: cleanProperties(f.Properties),
+	}
+	if f.URI != "" {
+		result.Locations = []Location{{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: f.URI}}}}
+	}
+	b.results = append(b.results, result)
+}
+
+func (b *builder) addErrors(errors []string) {
+	b.errors = append(b.errors, errors...)
+}
+
+// log assembles the SARIF log of the run.
+func (b *builder) log(version string, successful bool) *Log {
+	invocation := Invocation{ExecutionSuccessful: successful && len(b.errors) == 0}
+	for _, err := range b.errors {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, Notification{
+			Level:   LevelError,
+			Message: Message{Text: err},
+		})
+	}
+	rules := b.rules
+	if rules == nil {
+		rules = []Rule{}
+	}
+	results := b.results
+	if results == nil {
+		results = []Result{}
+	}
+	return &Log{
+		Version: Version,
+		Schema:  Schema,
+		Runs: []Run{{
+			Tool: Tool{Driver: Driver{
+				Name:           toolName,
+				Version:        version,
+				InformationURI: toolInformationURI,
+				Rules:          rules,
+			}},
+			Invocations: []Invocation{invocation},
+			Results:     results,
+		}},
+	}
+}
+
+// fingerprint identifies a result across runs so that consumers can track it.
+func fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// cleanProperties drops empty values from the properties of a result.
+func cleanProperties(properties map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{}, len(properties))
+	for key, value := range properties {
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case string:
+			if typed == "" {
+				continue
+			}
+		case *string:
+			if typed == nil || *typed == "" {
+				continue
+			}
+			value = *typed
+		case *int:
+			if typed == nil {
+				continue
+			}
+			value = *typed
+		}
+		cleaned[key] = value
+	}
+	if len(cleaned) == 0 {
+		return nil
+	}
+	return cleaned
+}
@@ -0,0 +1,219 @@
+package scope
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// hopHeaders are the headers of a single connection, which a proxy must not forward.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Proxy is a local HTTP proxy that enforces the scope on the requests of third party engines that only accept a proxy
+// URL. Allowed requests are forwarded directly or through the upstream proxy. The paths of HTTPS requests cannot be
+// seen in the tunnels of CONNECT requests, so only their host and port are checked.
+type Proxy struct {
+	ctx       context.Context
+	scope     *Scope
+	upstream  *url.URL
+	transport *http.Transport
+	listener  net.Listener
+	server    *http.Server
+}
+
+// NewProxy starts a Proxy on a local port. Plain HTTP requests are forwarded with the transport, and tunnels are
+// opened through the upstream proxy when there is one.
+func NewProxy(ctx context.Context, scope *Scope, upstream *url.URL, transport *http.Transport) (*Proxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start scope proxy: %v", err)
+	}
+	p := &Proxy{
+		ctx:       ctx,
+		scope:     scope,
+		upstream:  upstream,
+		transport: transport,
+		listener:  listener,
+	}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		_ = p.server.Serve(listener)
+	}()
+	return p, nil
+}
+
+// URL returns the URL engines use as their proxy.
+func (p *Proxy) URL() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Close stops the Proxy and closes its connections.
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "the scope proxy only accepts proxy requests", http.StatusBadRequest)
+		return
+	}
+	if err := p.scope.Check(p.ctx, r.URL); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	removeHopHeaders(out.Header)
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	removeHopHeaders(resp.Header)
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel opens the tunnel of a CONNECT request to an allowed host and copies the data of both connections.
+func (p *Proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	if err := p.scope.CheckHost(p.ctx, &url.URL{Scheme: "https", Host: r.Host}); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "the scope proxy does not support tunnels", http.StatusInternalServerError)
+		return
+	}
+	target, err := p.dial(r.Context(), r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		_ = target.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		_ = client.Close()
+		_ = target.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(target, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(client, target)
+		done <- struct{}{}
+	}()
+	<-done
+	_ = client.Close()
+	_ = target.Close()
+}
+
+// dial connects to the address, through the upstream proxy when there is one.
+func (p *Proxy) dial(ctx context.Context, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if p.upstream == nil {
+		return dialer.DialContext(ctx, "tcp", address)
+	}
+
+	switch p.upstream.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if p.upstream.User != nil {
+			password, _ := p.upstream.User.Password()
+			auth = &proxy.Auth{User: p.upstream.User.Username(), Password: password}
+		}
+		socks, err := proxy.SOCKS5("tcp", p.upstream.Host, auth, dialer)
+		if err != nil {
+			return nil, err
+		}
+		return socks.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	}
+
+	proxyAddress := p.upstream.Host
+	if p.upstream.Port() == "" {
+		port := "80"
+		if p.upstream.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddress = net.JoinHostPort(p.upstream.Hostname(), port)
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+	if err != nil {
+		return nil, err
+	}
+	if p.upstream.Scheme == "https" {
+		tlsConfig := p.transport.TLSClientConfig.Clone()
+		tlsConfig.ServerName = p.upstream.Hostname()
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	connect := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if p.upstream.User != nil {
+		password, _ := p.upstream.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(p.upstream.User.Username() + ":" + password))
+		connect.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := connect.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), connect)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, fmt.Errorf("upstream proxy refused tunnel to %s: %s", address, resp.Status)
+	}
+	return conn, nil
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
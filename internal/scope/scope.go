@@ -0,0 +1,381 @@
+// Package scope implements the scope of engagement of webscan. A scope definition lists the hosts, wildcard domains,
+// CIDRs, ports and path prefixes requests may be sent to, and the hosts, CIDRs and paths they must never be sent to.
+// The scope is enforced by the HTTP clients of every command, the browsers and the third party engines, and the
+// requests it blocks are reported in the errors of the command's report.
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/reportfile"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// OutOfScopeError is returned for requests that are not allowed by the scope.
+type OutOfScopeError struct {
+	URL    string
+	Reason string
+}
+
+func (e *OutOfScopeError) Error() string {
+	return fmt.Sprintf("blocked out of scope request to %s: %s", e.URL, e.Reason)
+}
+
+// Scope checks URLs against a scope definition. A nil Scope allows every URL.
+type Scope struct {
+	hosts         []string
+	cidrs         []*net.IPNet
+	ports         map[int]bool
+	pathPrefixes  []string
+	excludedHosts []string
+	excludedCidrs []*net.IPNet
+	excludedPaths []string
+
+	mu       sync.Mutex
+	resolved map[string][]net.IP
+	blocked  map[string]string
+}
+
+// Load loads the scope definition file at path.
+func Load(path string) (*Scope, error) {
+	definition, err := LoadDefinition(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(*definition)
+}
+
+// LoadDefinition loads the scope definition file at path without compiling it.
+func LoadDefinition(path string) (*webscan.ScopeDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope file %s: %v", path, err)
+	}
+	content, err := reportfile.Content(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scope file %s: %v", path, err)
+	}
+	definition := webscan.ScopeDefinition{}
+	if err := json.Unmarshal(content, &definition); err != nil {
+		return nil, fmt.Errorf("failed to decode scope file %s: %v", path, err)
+	}
+	return &definition, nil
+}
+
+// New validates the scope definition and creates its Scope.
+func New(definition webscan.ScopeDefinition) (*Scope, error) {
+	s := &Scope{
+		ports:    map[int]bool{},
+		resolved: map[string][]net.IP{},
+		blocked:  map[string]string{},
+	}
+	var err error
+	if s.hosts, err = hostPatterns(definition.Hosts); err != nil {
+		return nil, err
+	}
+	if s.excludedHosts, err = hostPatterns(definition.ExcludedHosts); err != nil {
+		return nil, err
+	}
+	if s.cidrs, err = parseCIDRs(definition.Cidrs); err != nil {
+		return nil, err
+	}
+	if s.excludedCidrs, err = parseCIDRs(definition.ExcludedCidrs); err != nil {
+		return nil, err
+	}
+	for _, port := range definition.Ports {
+		if port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid scope port %d", port)
+		}
+		s.ports[port] = true
+	}
+	if s.pathPrefixes, err = pathPatterns(definition.PathPrefixes); err != nil {
+		return nil, err
+	}
+	if s.excludedPaths, err = pathPatterns(definition.ExcludedPaths); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func hostPatterns(hosts []string) ([]string, error) {
+	patterns := []string{}
+	for _, host := range hosts {
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		invalid := host == "" || strings.Contains(strings.TrimPrefix(host, "*."), "*")
+		if net.ParseIP(host) == nil && strings.ContainsAny(host, "/:") {
+			invalid = true
+		}
+		if invalid {
+			return nil, fmt.Errorf("invalid scope host %q, expected a host name, an IP address or a wildcard domain such as *.example.com", host)
+		}
+		patterns = append(patterns, host)
+	}
+	return patterns, nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid scope CIDR %q: %v", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func pathPatterns(paths []string) ([]string, error) {
+	patterns := []string{}
+	for _, p := range paths {
+		if !strings.HasPrefix(p, "/") {
+			return nil, fmt.Errorf("invalid scope path %q, paths must start with /", p)
+		}
+		patterns = append(patterns, normalizePath(p))
+	}
+	return patterns, nil
+}
+
+// CheckURL parses the URL and checks it against the scope.
+func (s *Scope) CheckURL(ctx context.Context, rawURL string) error {
+	if s == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %s: %v", rawURL, err)
+	}
+	return s.Check(ctx, u)
+}
+
+// Check returns an OutOfScopeError when the URL is not allowed by the scope. Blocked URLs are logged and recorded.
+func (s *Scope) Check(ctx context.Context, u *url.URL) error {
+	if s == nil {
+		return nil
+	}
+	reason := s.hostReason(u)
+	if reason == "" {
+		reason = s.pathReason(u.Path)
+	}
+	return s.result(ctx, u.String(), reason)
+}
+
+// CheckHost checks only the host and port of the URL against the scope, for requests whose path cannot be seen such
+// as those tunneled through a proxy with CONNECT.
+func (s *Scope) CheckHost(ctx context.Context, u *url.URL) error {
+	if s == nil {
+		return nil
+	}
+	return s.result(ctx, u.String(), s.hostReason(u))
+}
+
+func (s *Scope) result(ctx context.Context, target string, reason string) error {
+	if reason == "" {
+		return nil
+	}
+	s.mu.Lock()
+	_, seen := s.blocked[target]
+	s.blocked[target] = reason
+	s.mu.Unlock()
+	if !seen {
+		svc1log.FromContext(ctx).Warn("Blocked out of scope request", svc1log.SafeParam("url", target), svc1log.SafeParam("reason", reason))
+	}
+	return &OutOfScopeError{URL: target, Reason: reason}
+}
+
+// Blocked returns the errors of the URLs the scope blocked, sorted by URL.
+func (s *Scope) Blocked() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	errors := make([]string, 0, len(s.blocked))
+	for target, reason := range s.blocked {
+		errors = append(errors, (&OutOfScopeError{URL: target, Reason: reason}).Error())
+	}
+	sort.Strings(errors)
+	return errors
+}
+
+// hostReason returns why the host and port of the URL are out of scope, or an empty string when they are in scope.
+func (s *Scope) hostReason(u *url.URL) string {
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "" {
+		return "the URL has no host"
+	}
+	if len(s.ports) > 0 {
+		port, err := strconv.Atoi(u.Port())
+		if u.Port() == "" {
+			port, err = defaultPort(u.Scheme)
+		}
+		if err != nil || !s.ports[port] {
+			return fmt.Sprintf("port %s is not in scope", portOf(u))
+		}
+	}
+
+	if matchHost(s.excludedHosts, host) {
+		return fmt.Sprintf("host %s is excluded", host)
+	}
+	if len(s.excludedCidrs) > 0 {
+		for _, ip := range s.addresses(host) {
+			if containsIP(s.excludedCidrs, ip) {
+				return fmt.Sprintf("address %s of host %s is excluded", ip, host)
+			}
+		}
+	}
+
+	if len(s.hosts) == 0 && len(s.cidrs) == 0 {
+		return ""
+	}
+	if matchHost(s.hosts, host) {
+		return ""
+	}
+	if len(s.cidrs) > 0 {
+		// Host names are in scope when every address they resolve to is in scope
+		ips := s.addresses(host)
+		inScope := len(ips) > 0
+		for _, ip := range ips {
+			inScope = inScope && containsIP(s.cidrs, ip)
+		}
+		if inScope {
+			return ""
+		}
+	}
+	return fmt.Sprintf("host %s is not in scope", host)
+}
+
+// pathReason returns why the path is out of scope, or an empty string when it is in scope. The path is normalized
+// before it is matched, so that paths a server resolves to an excluded path, such as /LOGOUT, //logout, /./logout or
+// /logout;/, are excluded too.
+func (s *Scope) pathReason(rawPath string) string {
+	normalized := normalizePath(rawPath)
+	for _, excluded := range s.excludedPaths {
+		if matchPath(excluded, normalized) {
+			return fmt.Sprintf("path %s is excluded", rawPath)
+		}
+	}
+	if len(s.pathPrefixes) == 0 {
+		return ""
+	}
+	for _, prefix := range s.pathPrefixes {
+		if matchPath(prefix, normalized) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("path %s is not in scope", rawPath)
+}
+
+// addresses returns the IP addresses of the host, resolving host names once.
+func (s *Scope) addresses(host string) []net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+	s.mu.Lock()
+	ips, ok := s.resolved[host]
+	s.mu.Unlock()
+	if ok {
+		return ips
+	}
+	ips, _ = net.LookupIP(host)
+	s.mu.Lock()
+	s.resolved[host] = ips
+	s.mu.Unlock()
+	return ips
+}
+
+// PathRegexes returns regular expressions matching the URLs whose path is in scope and the URLs whose path is
+// excluded, for engines that only accept scopes as regular expressions.
+func (s *Scope) PathRegexes() ([]string, []string) {
+	if s == nil {
+		return nil, nil
+	}
+	pathRegex := func(path string) string {
+		return `(?i)^[^:]+://[^/]+` + regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + `([/?#;]|$)`
+	}
+	include := []string{}
+	for _, prefix := range s.pathPrefixes {
+		include = append(include, pathRegex(prefix))
+	}
+	exclude := []string{}
+	for _, path := range s.excludedPaths {
+		exclude = append(exclude, pathRegex(path))
+	}
+	return include, exclude
+}
+
+// matchHost reports whether the host matches one of the patterns. Wildcard domains match their subdomains only.
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether the normalized path is the normalized prefix or one of its subpaths. Paths match on whole
+// segments, so /admin matches /admin/users but not /administrator.
+func matchPath(prefix string, p string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// normalizePath returns the path as servers commonly resolve it: percent-encoded characters are decoded, the matrix
+// parameters following a ; are removed from every segment, duplicate slashes are collapsed, . and .. segments are
+// resolved and the case is folded.
+func normalizePath(p string) string {
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		p = unescaped
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i], _, _ = strings.Cut(segment, ";")
+	}
+	return strings.ToLower(path.Clean("/" + strings.Join(segments, "/")))
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func defaultPort(scheme string) (int, error) {
+	switch strings.ToLower(scheme) {
+	case "http", "ws":
+		return 80, nil
+	case "https", "wss":
+		return 443, nil
+	}
+	return 0, fmt.Errorf("unknown default port of scheme %s", scheme)
+}
+
+func portOf(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Port()
+	}
+	if port, err := defaultPort(u.Scheme); err == nil {
+		return strconv.Itoa(port)
+	}
+	return "unknown"
+}
@@ -0,0 +1,218 @@
+package scope
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"regexp"
+	"testing"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "", want: "/"},
+		{path: "/", want: "/"},
+		{path: "/logout", want: "/logout"},
+		{path: "/LOGOUT", want: "/logout"},
+		{path: "//logout", want: "/logout"},
+		{path: "/./logout", want: "/logout"},
+		{path: "/api/../logout", want: "/logout"},
+		{path: "/logout/", want: "/logout"},
+		{path: "/logout;/", want: "/logout"},
+		{path: "/logout;jsessionid=1", want: "/logout"},
+		{path: "/api;v=1/users", want: "/api/users"},
+		{path: "%2Flogout", want: "/logout"},
+		{path: "/%6Cogout", want: "/logout"},
+		{path: "/%zz", want: "/%zz"},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.want, normalizePath(test.path))
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		prefix string
+		path   string
+		want   bool
+	}{
+		{prefix: "/", path: "/", want: true},
+		{prefix: "/", path: "/anything", want: true},
+		{prefix: "/admin", path: "/admin", want: true},
+		{prefix: "/admin", path: "/admin/users", want: true},
+		{prefix: "/admin/", path: "/admin/users", want: true},
+		{prefix: "/admin", path: "/administrator", want: false},
+		{prefix: "/admin", path: "/", want: false},
+		{prefix: "/admin/users", path: "/admin", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.prefix+" "+test.path, func(t *testing.T) {
+			assert.Equal(t, test.want, matchPath(test.prefix, test.path))
+		})
+	}
+}
+
+func TestCheckExcludedPaths(t *testing.T) {
+	s, err := New(webscan.ScopeDefinition{ExcludedPaths: []string{"/logout", "/admin"}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		url     string
+		blocked bool
+	}{
+		{url: "https://example.com/logout", blocked: true},
+		{url: "https://example.com/LOGOUT", blocked: true},
+		{url: "https://example.com//logout", blocked: true},
+		{url: "https://example.com/./logout", blocked: true},
+		{url: "https://example.com/logout;/", blocked: true},
+		{url: "https://example.com/%2Flogout", blocked: true},
+		{url: "https://example.com/logout?next=/", blocked: true},
+		{url: "https://example.com/admin/users", blocked: true},
+		{url: "https://example.com/administrator", blocked: false},
+		{url: "https://example.com/logouts", blocked: false},
+		{url: "https://example.com/", blocked: false},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			err := s.CheckURL(context.Background(), test.url)
+			if test.blocked {
+				var outOfScope *OutOfScopeError
+				require.ErrorAs(t, err, &outOfScope)
+				assert.Contains(t, outOfScope.Reason, "is excluded")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckPathPrefixes(t *testing.T) {
+	s, err := New(webscan.ScopeDefinition{PathPrefixes: []string{"/admin"}})
+	require.NoError(t, err)
+
+	assert.NoError(t, s.CheckURL(context.Background(), "https://example.com/admin"))
+	assert.NoError(t, s.CheckURL(context.Background(), "https://example.com/Admin/users"))
+	assert.Error(t, s.CheckURL(context.Background(), "https://example.com/administrator"))
+	assert.Error(t, s.CheckURL(context.Background(), "https://example.com/"))
+}
+
+func TestHostReason(t *testing.T) {
+	s, err := New(webscan.ScopeDefinition{
+		Hosts:         []string{"*.example.com", "api.example.org."},
+		Cidrs:         []string{"10.0.0.0/8"},
+		ExcludedHosts: []string{"admin.example.com"},
+		ExcludedCidrs: []string{"10.0.0.13/32"},
+	})
+	require.NoError(t, err)
+	s.resolved = map[string][]net.IP{
+		"internal.test": {net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")},
+		"split.test":    {net.ParseIP("10.0.0.1"), net.ParseIP("192.168.0.1")},
+		"excluded.test": {net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.13")},
+		"unknown.test":  nil,
+	}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://www.example.com/", want: ""},
+		{url: "https://deep.www.example.com/", want: ""},
+		{url: "https://WWW.Example.com./", want: ""},
+		{url: "https://example.com/", want: "host example.com is not in scope"},
+		{url: "https://badexample.com/", want: "host badexample.com is not in scope"},
+		{url: "https://api.example.org/", want: ""},
+		{url: "https://admin.example.com/", want: "host admin.example.com is excluded"},
+		{url: "http://10.1.2.3/", want: ""},
+		{url: "http://10.0.0.13/", want: "address 10.0.0.13 of host 10.0.0.13 is excluded"},
+		{url: "http://192.168.0.1/", want: "host 192.168.0.1 is not in scope"},
+		{url: "http://internal.test/", want: ""},
+		{url: "http://split.test/", want: "host split.test is not in scope"},
+		{url: "http://excluded.test/", want: "address 10.0.0.13 of host excluded.test is excluded"},
+		{url: "http://unknown.test/", want: "host unknown.test is not in scope"},
+		{url: "/relative", want: "the URL has no host"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, s.hostReason(u))
+		})
+	}
+}
+
+func TestHostReasonPorts(t *testing.T) {
+	s, err := New(webscan.ScopeDefinition{Ports: []int{80, 443}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "http://example.com/", want: ""},
+		{url: "https://example.com/", want: ""},
+		{url: "HTTPS://example.com/", want: ""},
+		{url: "ws://example.com/", want: ""},
+		{url: "wss://example.com/", want: ""},
+		{url: "https://example.com:443/", want: ""},
+		{url: "http://example.com:443/", want: ""},
+		{url: "https://example.com:8443/", want: "port 8443 is not in scope"},
+		{url: "ftp://example.com/", want: "port unknown is not in scope"},
+	}
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			u, err := url.Parse(test.url)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, s.hostReason(u))
+		})
+	}
+
+	_, err = New(webscan.ScopeDefinition{Ports: []int{0}})
+	assert.Error(t, err)
+	_, err = New(webscan.ScopeDefinition{Ports: []int{65536}})
+	assert.Error(t, err)
+}
+
+func TestPathRegexes(t *testing.T) {
+	s, err := New(webscan.ScopeDefinition{PathPrefixes: []string{"/admin/"}, ExcludedPaths: []string{"/logout"}})
+	require.NoError(t, err)
+	include, exclude := s.PathRegexes()
+	require.Len(t, include, 1)
+	require.Len(t, exclude, 1)
+
+	tests := []struct {
+		regex string
+		url   string
+		want  bool
+	}{
+		{regex: include[0], url: "https://example.com/admin", want: true},
+		{regex: include[0], url: "https://example.com/admin/users", want: true},
+		{regex: include[0], url: "https://example.com/ADMIN?page=1", want: true},
+		{regex: include[0], url: "https://example.com/administrator", want: false},
+		{regex: include[0], url: "https://example.com/api/admin", want: false},
+		{regex: exclude[0], url: "https://example.com/logout", want: true},
+		{regex: exclude[0], url: "https://example.com/LOGOUT", want: true},
+		{regex: exclude[0], url: "https://example.com/logout;/", want: true},
+		{regex: exclude[0], url: "https://example.com/logout#top", want: true},
+		{regex: exclude[0], url: "https://example.com:8443/logout/all", want: true},
+		{regex: exclude[0], url: "https://example.com/logouts", want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.regex+" "+test.url, func(t *testing.T) {
+			assert.Equal(t, test.want, regexp.MustCompile(test.regex).MatchString(test.url))
+		})
+	}
+
+	var nilScope *Scope
+	include, exclude = nilScope.PathRegexes()
+	assert.Nil(t, include)
+	assert.Nil(t, exclude)
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+// maxRequestSize is the maximum size of the body of a job request.
+const maxRequestSize = 10 << 20
+
+// routes returns the handler of the API. The events endpoint streams JSON Lines, which the fern definition cannot
+// describe, so it is the only endpoint the generated client does not cover.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/jobs", s.handleSubmit)
+	mux.HandleFunc("GET /api/v1/jobs", s.handleList)
+	mux.HandleFunc("GET /api/v1/jobs/{jobId}", s.handleGet)
+	mux.HandleFunc("POST /api/v1/jobs/{jobId}/cancel", s.handleCancel)
+	mux.HandleFunc("GET /api/v1/jobs/{jobId}/events", s.handleEvents)
+	return mux
+}
+
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	request := &webscan.ScanJobRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(request); err != nil {
+		s.writeError(w, r, &invalidRequestError{err: fmt.Errorf("failed to decode the job request: %v", err)})
+		return
+	}
+	job, err := s.Submit(request)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	encode(w, http.StatusCreated, job)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	jobs, err := s.store.List()
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	encode(w, http.StatusOK, jobs)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	job, err := s.store.Get(r.PathValue("jobId"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	encode(w, http.StatusOK, job)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	job, err := s.Cancel(r.PathValue("jobId"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	encode(w, http.StatusOK, job)
+}
+
+// handleEvents streams the events of a job as JSON Lines, the records of the jsonl output format of the CLI. The
+// events the job emitted so far are written first, then the stream follows the job until it completes.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("jobId")
+	if _, err := s.store.Get(id); err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	var next uint64
+	write := func() bool {
+		events, n, err := s.store.Events(id, next)
+		if err != nil {
+			svc1log.FromContext(r.Context()).Error(err.Error())
+			return false
+		}
+		next = n
+		for _, event := range events {
+			if _, err := w.Write(append(event, '\n')); err != nil {
+				return false
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	for {
+		// The channel is taken before reading, so that changes made while reading wake the stream
+		updated := s.watch(id)
+		if !write() {
+			return
+		}
+		job, err := s.store.Get(id)
+		if err != nil {
+			return
+		}
+		if completed(job) {
+			// Events stored between the read and the completion of the job are written before the stream ends
+			write()
+			return
+		}
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeError writes an error response with the status matching the error.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalid *invalidRequestError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &invalid):
+		status = http.StatusBadRequest
+	case errors.Is(err, errJobNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errJobCompleted):
+		status = http.StatusConflict
+	case errors.Is(err, errQueueFull):
+		status = http.StatusServiceUnavailable
+	default:
+		svc1log.FromContext(r.Context()).Error(fmt.Sprintf("%s %s failed: %s", r.Method, r.URL.Path, err.Error()))
+	}
+	encode(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	lib "github.com/Method-Security/webscan/pkg/webscan"
+)
+
+// validate checks that a job request has the options its type requires, so that invalid jobs are rejected when they
+// are submitted rather than failing once they run.
+func validate(request *webscan.ScanJobRequest) error {
+	if request == nil {
+		return errors.New("the job request is required")
+	}
+	if _, err := webscan.NewScanJobTypeFromString(string(request.Type)); err != nil {
+		return fmt.Errorf("invalid job type %q", request.Type)
+	}
+	if len(request.Targets) == 0 {
+		return errors.New("targets is required")
+	}
+	if request.Type != webscan.ScanJobTypeWebserver && len(request.Targets) > 1 {
+		return fmt.Errorf("%s jobs take a single target", request.Type)
+	}
+	if request.TimeoutSeconds != nil && *request.TimeoutSeconds < 0 {
+		return errors.New("timeoutSeconds must not be negative")
+	}
+	switch request.Type {
+	case webscan.ScanJobTypeWebserver:
+		if request.Server == nil {
+			return errors.New("server is required by WEBSERVER jobs")
+		}
+		if _, err := webscan.NewServerTypeFromString(string(*request.Server)); err != nil {
+			return fmt.Errorf("invalid server type %q", *request.Server)
+		}
+		if request.Probe == nil {
+			return errors.New("probe is required by WEBSERVER jobs")
+		}
+		if _, err := webscan.NewProbeTypeFromString(string(*request.Probe)); err != nil {
+			return fmt.Errorf("invalid probe type %q", *request.Probe)
+		}
+	case webscan.ScanJobTypeFuzzPath:
+		if len(request.Paths) == 0 {
+			return errors.New("paths is required by FUZZ_PATH jobs")
+		}
+	case webscan.ScanJobTypeRouteCapture:
+		if request.CaptureMethod != nil && *request.CaptureMethod != webscan.PageCaptureMethodRequest &&
+			*request.CaptureMethod != webscan.PageCaptureMethodBrowser {
+			return errors.New("captureMethod must be REQUEST or BROWSER")
+		}
+	case webscan.ScanJobTypeRequests:
+		if len(request.Requests) == 0 {
+			return errors.New("requests is required by REQUESTS jobs")
+		}
+	}
+	return nil
+}
+
+// scan runs the scan of a job request and returns its report, passing the streamed results to onEvent. The error of a
+// scan whose report lists errors is a *lib.ScanError.
+func (s *Server) scan(ctx context.Context, request *webscan.ScanJobRequest, onEvent func(lib.Event)) (*webscan.ScanJobResult, error) {
+	if request.TimeoutSeconds != nil && *request.TimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*request.TimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+	target := request.Targets[0]
+	result := &webscan.ScanJobResult{}
+	var err error
+	switch request.Type {
+	case webscan.ScanJobTypeSwagger:
+		result.RoutesReport, err = s.scanner.Swagger(ctx, lib.SwaggerOptions{Target: target})
+	case webscan.ScanJobTypeGraphql:
+		result.RoutesReport, err = s.scanner.GraphQL(ctx, lib.GraphQLOptions{Target: target})
+	case webscan.ScanJobTypeGrpc:
+		result.RoutesReport, err = s.scanner.GRPC(ctx, lib.GRPCOptions{Target: target})
+	case webscan.ScanJobTypeFingerprint:
+		result.FingerprintReport, err = s.scanner.Fingerprint(ctx, lib.FingerprintOptions{Target: target})
+	case webscan.ScanJobTypeWebserver:
+		options := lib.WebServerOptions{
+			Targets: request.Targets,
+			Server:  *request.Server,
+			Probe:   *request.Probe,
+			Modules: request.Modules,
+			OnEvent: onEvent,
+		}
+		if request.SuccessfulOnly != nil {
+			options.SuccessfulOnly = *request.SuccessfulOnly
+		}
+		result.WebServerReport, err = s.scanner.WebServer(ctx, options)
+	case webscan.ScanJobTypeFuzzPath:
+		result.FuzzPathReport, err = s.fuzzPath(ctx, request, onEvent)
+	case webscan.ScanJobTypeRouteCapture:
+		method := webscan.PageCaptureMethodRequest
+		if request.CaptureMethod != nil {
+			method = *request.CaptureMethod
+		}
+		result.RouteCaptureReport, err = s.scanner.RouteCapture(ctx, lib.RouteCaptureOptions{Target: target, Method: method})
+	case webscan.ScanJobTypeRequests:
+		batch := make([]lib.RequestParams, 0, len(request.Requests))
+		for _, params := range request.Requests {
+			if params != nil {
+				batch = append(batch, *params)
+			}
+		}
+		result.RequestBatchReport, err = s.scanner.RequestBatch(ctx, lib.RequestBatchOptions{
+			Target:    target,
+			BaseURL:   target,
+			Requests:  batch,
+			VulnTypes: request.VulnTypes,
+			OnEvent:   onEvent,
+		})
+	default:
+		return nil, fmt.Errorf("invalid job type %q", request.Type)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("the job timed out after %d seconds", *request.TimeoutSeconds)
+	}
+	return result, err
+}
+
+// fuzzPath runs a FUZZ_PATH job, writing its paths to the temporary pathlist the fuzzer reads them from.
+func (s *Server) fuzzPath(ctx context.Context, request *webscan.ScanJobRequest, onEvent func(lib.Event)) (*webscan.FuzzPathReport, error) {
+	pathlist, err := os.CreateTemp("", "webscan-paths-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pathlist: %v", err)
+	}
+	defer os.Remove(pathlist.Name())
+	_, err = pathlist.WriteString(strings.Join(request.Paths, "\n") + "\n")
+	if closeErr := pathlist.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write pathlist: %v", err)
+	}
+	options := lib.PathFuzzOptions{Target: request.Targets[0], Pathlist: pathlist.Name(), OnEvent: onEvent}
+	if request.ResponseCodes != nil {
+		options.ResponseCodes = *request.ResponseCodes
+	}
+	if request.TimeoutSeconds != nil && *request.TimeoutSeconds > 0 {
+		options.MaxTime = time.Duration(*request.TimeoutSeconds) * time.Second
+	}
+	return s.scanner.PathFuzz(ctx, options)
+}
@@ -0,0 +1,345 @@
+// Package server implements the HTTP API of `webscan serve`, which runs scans as jobs. Submitted jobs are queued in a
+// persistent Store and run by a bounded pool of workers, and their results are streamed while they run and stored once
+// they complete. The request and response bodies are the types of the jobs fern definition, so the generated Go
+// client in generated/go/client talks to the API.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	lib "github.com/Method-Security/webscan/pkg/webscan"
+	"github.com/google/uuid"
+	"github.com/palantir/witchcraft-go-logging/wlog/svclog/svc1log"
+)
+
+var (
+	// errQueueFull is returned when a job is submitted while the queue holds the maximum number of queued jobs.
+	errQueueFull = errors.New("the job queue is full, retry once queued jobs started")
+	// errJobCompleted is returned when a job that already completed is canceled.
+	errJobCompleted = errors.New("the job already completed")
+	// errJobCanceled is the cause of the cancellation of the context of a running job canceled through the API.
+	errJobCanceled = errors.New("the job was canceled")
+)
+
+// invalidRequestError is returned when a submitted job request is invalid.
+type invalidRequestError struct {
+	err error
+}
+
+func (e *invalidRequestError) Error() string {
+	return e.err.Error()
+}
+
+// Options configure the job queue of a Server.
+type Options struct {
+	// Workers is the number of jobs run concurrently.
+	Workers int
+	// MaxQueued is the maximum number of jobs waiting for a worker, beyond which submissions are rejected.
+	MaxQueued int
+}
+
+// Server runs the submitted jobs with the scans of a Scanner and serves the API to submit, follow and cancel them.
+type Server struct {
+	scanner   *lib.Scanner
+	store     *Store
+	workers   int
+	maxQueued int
+	queue     chan string
+
+	// mu serializes the changes of the status of jobs, and guards the cancel functions of the running jobs and the
+	// channels that wake the event streams of jobs when they change.
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc
+	updates map[string]chan struct{}
+}
+
+// New creates a Server running jobs with the scanner and storing them in the store.
+func New(scanner *lib.Scanner, store *Store, options Options) *Server {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.MaxQueued < 1 {
+		options.MaxQueued = 1
+	}
+	return &Server{
+		scanner:   scanner,
+		store:     store,
+		workers:   options.Workers,
+		maxQueued: options.MaxQueued,
+		running:   map[string]context.CancelCauseFunc{},
+		updates:   map[string]chan struct{}{},
+	}
+}
+
+// Run serves the API on the listener until ctx is done. The jobs that were queued or running when the server last
+// stopped are queued again first. Once ctx is done the running jobs are stopped and queued again, to be run from the
+// start by the next server using the store.
+func (s *Server) Run(ctx context.Context, listener net.Listener) error {
+	log := svc1log.FromContext(ctx)
+	pending, err := s.requeue()
+	if err != nil {
+		return err
+	}
+	s.queue = make(chan string, max(s.maxQueued, len(pending)))
+	for _, id := range pending {
+		s.queue <- id
+	}
+	if len(pending) > 0 {
+		log.Info(fmt.Sprintf("Queued %d jobs interrupted by the last shutdown", len(pending)))
+	}
+
+	var workers sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case id := <-s.queue:
+					s.run(ctx, id)
+				}
+			}
+		}()
+	}
+
+	server := &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 30 * time.Second,
+		// Requests share the context of the server so that event streams end when it stops
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	log.Info(fmt.Sprintf("Serving the webscan API on http://%s", listener.Addr()))
+
+	select {
+	case err = <-served:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+	}
+	workers.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// requeue marks the jobs that were queued or running when the server last stopped as queued, and returns their IDs
+// in the order they were submitted.
+func (s *Server) requeue() ([]string, error) {
+	jobs, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+	pending := []string{}
+	for _, job := range jobs {
+		if job.Status != webscan.ScanJobStatusQueued && job.Status != webscan.ScanJobStatusRunning {
+			continue
+		}
+		job.Status = webscan.ScanJobStatusQueued
+		job.StartedAt = nil
+		if err := s.store.Put(job); err != nil {
+			return nil, err
+		}
+		pending = append(pending, job.Id)
+	}
+	return pending, nil
+}
+
+// Submit validates a job request and queues its job.
+func (s *Server) Submit(request *webscan.ScanJobRequest) (*webscan.ScanJob, error) {
+	if err := validate(request); err != nil {
+		return nil, &invalidRequestError{err: err}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.queue) >= s.maxQueued {
+		return nil, errQueueFull
+	}
+	job := &webscan.ScanJob{
+		Id:        uuid.Must(uuid.NewV7()).String(),
+		Request:   request,
+		Status:    webscan.ScanJobStatusQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.store.Put(job); err != nil {
+		return nil, err
+	}
+	// Jobs are only added to the queue under the lock, so the queue has room for the job
+	s.queue <- job.Id
+	return job, nil
+}
+
+// Cancel cancels a job. Queued jobs are canceled right away, while running jobs are stopped and marked as canceled
+// once their scan returns.
+func (s *Server) Cancel(id string) (*webscan.ScanJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	switch job.Status {
+	case webscan.ScanJobStatusQueued:
+		// The job stays in the queue, and is skipped by the worker that receives it
+		completedAt := time.Now().UTC()
+		job.Status = webscan.ScanJobStatusCanceled
+		job.CompletedAt = &completedAt
+		if err := s.store.Put(job); err != nil {
+			return nil, err
+		}
+		s.notify(id)
+	case webscan.ScanJobStatusRunning:
+		if cancel, ok := s.running[id]; ok {
+			cancel(errJobCanceled)
+		}
+	default:
+		return nil, errJobCompleted
+	}
+	return job, nil
+}
+
+// run runs a queued job and stores its result.
+func (s *Server) run(ctx context.Context, id string) {
+	log := svc1log.FromContext(ctx)
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	job, err := s.start(id, cancel)
+	if err != nil {
+		log.Error(fmt.Sprintf("Failed to start job %s: %s", id, err.Error()))
+		return
+	}
+	if job == nil {
+		return
+	}
+	log.Info(fmt.Sprintf("Running %s job %s", job.Request.Type, id))
+	result, err := s.scan(jobCtx, job.Request, func(event lib.Event) {
+		s.emit(ctx, id, event)
+	})
+	if err := s.finish(ctx, jobCtx, job, result, err); err != nil {
+		log.Error(fmt.Sprintf("Failed to store the result of job %s: %s", id, err.Error()))
+	}
+}
+
+// start marks a queued job as running, and returns nil when the job is no longer queued, e.g. because it was canceled
+// while it waited for a worker. The events of a previous run interrupted by a shutdown are discarded.
+func (s *Server) start(id string, cancel context.CancelCauseFunc) (*webscan.ScanJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != webscan.ScanJobStatusQueued {
+		return nil, nil
+	}
+	if err := s.store.ClearEvents(id); err != nil {
+		return nil, err
+	}
+	startedAt := time.Now().UTC()
+	job.Status = webscan.ScanJobStatusRunning
+	job.StartedAt = &startedAt
+	if err := s.store.Put(job); err != nil {
+		return nil, err
+	}
+	s.running[id] = cancel
+	s.notify(id)
+	return job, nil
+}
+
+// finish stores the result of a job once its scan returned. Jobs stopped because the server is shutting down are
+// queued again rather than failed.
+func (s *Server) finish(ctx context.Context, jobCtx context.Context, job *webscan.ScanJob, result *webscan.ScanJobResult, err error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, job.Id)
+	defer s.notify(job.Id)
+
+	completedAt := time.Now().UTC()
+	job.CompletedAt = &completedAt
+	job.Result = result
+	var scanErr *lib.ScanError
+	switch {
+	case errors.Is(context.Cause(jobCtx), errJobCanceled):
+		job.Status = webscan.ScanJobStatusCanceled
+	case ctx.Err() != nil:
+		job.Status = webscan.ScanJobStatusQueued
+		job.StartedAt = nil
+		job.CompletedAt = nil
+		job.Result = nil
+	case err == nil:
+		job.Status = webscan.ScanJobStatusSucceeded
+	case errors.As(err, &scanErr):
+		// The report lists the errors of the scan along with its results, as the CLI reports them
+		job.Status = webscan.ScanJobStatusSucceeded
+		job.Errors = scanErr.Errors
+	default:
+		job.Status = webscan.ScanJobStatusFailed
+		job.Errors = []string{err.Error()}
+	}
+	return s.store.Put(job)
+}
+
+// emit stores an event streamed by the scan of a job and wakes the streams following the job.
+func (s *Server) emit(ctx context.Context, id string, event lib.Event) {
+	err := s.store.AppendEvent(id, &webscan.ScanJobEvent{
+		Type:      string(event.Type),
+		Timestamp: event.Timestamp,
+		Data:      event.Data,
+	})
+	if err != nil {
+		svc1log.FromContext(ctx).Error(fmt.Sprintf("Failed to store an event of job %s: %s", id, err.Error()))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notify(id)
+}
+
+// watch returns a channel closed the next time the job changes. The caller must not hold mu.
+func (s *Server) watch(id string) <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	updated, ok := s.updates[id]
+	if !ok {
+		updated = make(chan struct{})
+		s.updates[id] = updated
+	}
+	return updated
+}
+
+// notify wakes the watchers of a job. The caller must hold mu.
+func (s *Server) notify(id string) {
+	if updated, ok := s.updates[id]; ok {
+		close(updated)
+		delete(s.updates, id)
+	}
+}
+
+// completed reports whether a job is done and will not change anymore.
+func completed(job *webscan.ScanJob) bool {
+	return job.Status == webscan.ScanJobStatusSucceeded || job.Status == webscan.ScanJobStatusFailed ||
+		job.Status == webscan.ScanJobStatusCanceled
+}
+
+// encode writes a JSON response body.
+func encode(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
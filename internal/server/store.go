@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	jobsBucket   = []byte("jobs")
+	eventsBucket = []byte("events")
+)
+
+// errJobNotFound is returned by the Store for the IDs of jobs it does not hold.
+var errJobNotFound = errors.New("job not found")
+
+// Store persists the scan jobs and their events in a bbolt database, so that jobs outlive the server. Jobs are stored
+// as JSON by ID, and the events of a job in a nested bucket keyed by their sequence number.
+type Store struct {
+	db *bolt.DB
+}
+
+// OpenStore opens the job database at path, creating it when it does not exist.
+func OpenStore(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job database %s: %v", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{jobsBucket, eventsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize job database %s: %v", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put creates or replaces a job.
+func (s *Store) Put(job *webscan.ScanJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %v", job.Id, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(job.Id), data)
+	})
+}
+
+// Get returns the job with the ID.
+func (s *Store) Get(id string) (*webscan.ScanJob, error) {
+	var job *webscan.ScanJob
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(jobsBucket).Get([]byte(id))
+		if data == nil {
+			return errJobNotFound
+		}
+		job = &webscan.ScanJob{}
+		return json.Unmarshal(data, job)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List returns every job, oldest first. Job IDs are time ordered UUIDs, so jobs created in the same second keep the
+// order they were submitted in.
+func (s *Store) List() ([]*webscan.ScanJob, error) {
+	jobs := []*webscan.ScanJob{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(_, data []byte) error {
+			job := &webscan.ScanJob{}
+			if err := json.Unmarshal(data, job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// AppendEvent adds an event to the events of a job.
+func (s *Store) AppendEvent(id string, event *webscan.ScanJobEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event of job %s: %v", id, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(eventsBucket).CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return err
+		}
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		return bucket.Put(sequenceKey(seq), data)
+	})
+}
+
+// Events returns the events of a job from the event with the sequence number from, along with the sequence number of
+// the next event.
+func (s *Store) Events(id string, from uint64) ([]json.RawMessage, uint64, error) {
+	events := []json.RawMessage{}
+	next := from
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(eventsBucket).Bucket([]byte(id))
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for key, data := cursor.Seek(sequenceKey(from)); key != nil; key, data = cursor.Next() {
+			events = append(events, append(json.RawMessage{}, data...))
+			next = binary.BigEndian.Uint64(key) + 1
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to read events of job %s: %v", id, err)
+	}
+	return events, next, nil
+}
+
+// ClearEvents deletes the events of a job, e.g. before it is run again after a restart of the server.
+func (s *Store) ClearEvents(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(eventsBucket).DeleteBucket([]byte(id))
+		if errors.Is(err, bolt.ErrBucketNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// sequenceKey encodes the sequence number of an event as a key that sorts in sequence order.
+func sequenceKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
@@ -7,6 +7,9 @@ import (
 	"math"
 	"strings"
 
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/stream"
 	"github.com/projectdiscovery/katana/pkg/engine/standard"
 	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/types"
@@ -26,10 +29,43 @@ type WebSpiderReport struct {
 	Errors  []string      `json:"errors" yaml:"errors"`
 }
 
-func performWebSpider(targets []string) ([]LinkDetails, []string, error) {
-	errors := []string{}
+// spiderParams identify the checkpoint of a web spider.
+type spiderParams struct {
+	Targets []string `json:"targets"`
+}
+
+// spiderState is the checkpointed progress of a web spider: the targets whose crawl completed, and the pages crawled
+// so far with the depth they were found at, which bound the crawl of an interrupted target when it is resumed.
+type spiderState struct {
+	Completed []string      `json:"completed"`
+	Pages     []crawledPage `json:"pages"`
+	Errors    []string      `json:"errors"`
+}
+
+// crawledPage is a page crawled from a target.
+type crawledPage struct {
+	Target string `json:"target"`
+	Link   string `json:"link"`
+	Status int    `json:"status"`
+	Depth  int    `json:"depth"`
+}
+
+func performWebSpider(ctx context.Context, targets []string) ([]LinkDetails, []string, error) {
 	links := []LinkDetails{}
+	state := spiderState{Completed: []string{}, Pages: []crawledPage{}, Errors: []string{}}
+	progress, err := checkpoint.Open(ctx, "spider", spiderParams{Targets: targets}, &state)
+	if err != nil {
+		return links, []string{}, err
+	}
+	// current is the target being crawled, and crawled the pages of the state by target and link
+	current := ""
+	crawled := map[string]bool{}
+	for _, page := range state.Pages {
+		crawled[page.Target+" "+page.Link] = true
+	}
 
+	// The crawler reports the raw traffic of its results, which is recorded in the HAR recorder of the HTTP clients
+	recorder := httpclient.FromContext(ctx).Recorder()
 	options := &types.Options{
 		MaxDepth:     3,             // Maximum depth to crawl
 		FieldScope:   "rdn",         // Crawling Scope Field
@@ -41,33 +77,130 @@ func performWebSpider(targets []string) ([]LinkDetails, []string, error) {
 		RateLimit:    150,           // Maximum requests to send per second
 		Strategy:     "depth-first", // Visit strategy (depth-first, breadth-first)
 		OnResult: func(result output.Result) { // Callback function to execute for result
-			linkDetails := LinkDetails{
-				Link:   result.Request.URL,
-				Status: result.Response.StatusCode,
+			// Results of crawls abandoned after an interrupt are left to the resumed crawl
+			if result.Request == nil || ctx.Err() != nil {
+				return
+			}
+			linkDetails := LinkDetails{Link: result.Request.URL}
+			rawResponse := ""
+			if result.Response != nil {
+				linkDetails.Status = result.Response.StatusCode
+				rawResponse = result.Response.Raw
+			}
+			// Pages crawled again to resume the crawl of a target were reported by an earlier run
+			added := false
+			progress.Update(func() {
+				if crawled[current+" "+linkDetails.Link] {
+					return
+				}
+				crawled[current+" "+linkDetails.Link] = true
+				state.Pages = append(state.Pages, crawledPage{
+					Target: current,
+					Link:   linkDetails.Link,
+					Status: linkDetails.Status,
+					Depth:  result.Request.Depth,
+				})
+				added = true
+			})
+			if !added {
+				return
 			}
-			links = append(links, linkDetails)
+			stream.Emit(ctx, stream.TypeLink, linkDetails)
+			recorder.AddRaw(ctx, result.Timestamp, 0, result.Request.URL, result.Request.Raw, rawResponse)
 		},
 	}
 
+	// Requests share the proxy, headers, authentication, scope and limits of the HTTP clients
+	factory := httpclient.FromContext(ctx)
+	headers, err := factory.HeaderLines()
+	if err != nil {
+		return links, state.Errors, err
+	}
+	options.CustomHeaders = append(options.CustomHeaders, headers...)
+	proxy, closeProxy, err := factory.EngineProxy(ctx)
+	if err != nil {
+		return links, state.Errors, err
+	}
+	defer closeProxy()
+	options.Proxy = proxy
+	// The scope proxy cannot see the paths of HTTPS requests, so the crawler is also given the scope's paths
+	options.Scope, options.OutOfScope = factory.Scope().PathRegexes()
+	limits := factory.Limiter().EngineLimits()
+	if limits.PerSecond() > 0 && limits.PerSecond() < options.RateLimit {
+		options.RateLimit = limits.PerSecond()
+	} else if limits.PerMinute() > 0 {
+		options.RateLimit, options.RateLimitMinute = 0, limits.PerMinute()
+	}
+	options.Concurrency = limits.Cap(options.Concurrency)
+	options.Parallelism = limits.Cap(options.Parallelism)
+
 	crawlerOptions, err := types.NewCrawlerOptions(options)
 	if err != nil {
-		return links, errors, err
+		return links, state.Errors, err
+	}
+	// The filter of the crawler skips the pages crawled by earlier runs that the crawl of the remaining targets does not
+	// need: those of completed targets and the pages at the maximum depth, whose links are not crawled. The other pages
+	// of an interrupted target are crawled again to find the links that were not crawled yet.
+	completed := map[string]bool{}
+	for _, target := range state.Completed {
+		completed[target] = true
+	}
+	for _, page := range state.Pages {
+		if completed[page.Target] || page.Depth >= options.MaxDepth {
+			crawlerOptions.UniqueFilter.UniqueURL(page.Link)
+		}
 	}
 
 	crawler, err := standard.New(crawlerOptions)
 	if err != nil {
-		return links, errors, err
+		return links, state.Errors, err
 	}
 
 	for _, target := range targets {
-		err := crawler.Crawl(target)
-		if err != nil {
-			errors = append(errors, err.Error())
+		if completed[target] || ctx.Err() != nil {
+			continue
+		}
+		progress.Update(func() {
+			current = target
+		})
+		err := crawl(ctx, crawler, target)
+		if ctx.Err() != nil {
+			break
 		}
+		progress.Update(func() {
+			if err != nil {
+				state.Errors = append(state.Errors, err.Error())
+			}
+			state.Completed = append(state.Completed, target)
+		})
 	}
 
+	errors := []string{}
+	progress.Update(func() {
+		for _, page := range state.Pages {
+			links = append(links, LinkDetails{Link: page.Link, Status: page.Status})
+		}
+		errors = append(errors, state.Errors...)
+	})
+	if err := progress.Finish(ctx.Err() == nil); err != nil {
+		errors = append(errors, err.Error())
+	}
 	return links, errors, nil
+}
 
+// crawl crawls from the URL until the crawl completes or the context is canceled, as the crawler does not stop when
+// it is.
+func crawl(ctx context.Context, crawler *standard.Crawler, u string) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- crawler.Crawl(u)
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
 }
 
 // PerformWebSpider performs a web spider operation against the provided targets, returning a WebSpiderReport with the
@@ -77,7 +210,7 @@ func PerformWebSpider(ctx context.Context, targets string) (WebSpiderReport, err
 	targetList := strings.Split(targets, ",")
 
 	// 2. Perform web spider
-	links, errors, err := performWebSpider(targetList)
+	links, errors, err := performWebSpider(ctx, targetList)
 	if err != nil {
 		errors = append(errors, err.Error())
 	}
@@ -0,0 +1,167 @@
+// Package stream implements the JSON Lines output of webscan. Instead of buffering a whole report until the command
+// completes, long-running commands emit each finding or result as a single JSON record as soon as it arrives, and the
+// stream is closed by a summary record. The Emitter is carried through the command's context so that the internal
+// packages can emit records without knowing about the selected output format.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Record types emitted by the commands.
+const (
+	TypeVulnerability = "vulnerability"
+	TypePath          = "path"
+	TypeLink          = "link"
+	TypeAttempt       = "attempt"
+	TypeRequest       = "request"
+	TypeChange        = "change"
+	TypeSummary       = "summary"
+)
+
+// Record is a single line of the stream.
+type Record struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+// Summary is the data of the record closing the stream. It includes the report of the command without the items that
+// were already emitted as records, so that the errors and counts of the report are kept and commands without
+// streaming support still produce their results.
+type Summary struct {
+	Status       int            `json:"status"`
+	ErrorMessage *string        `json:"errorMessage,omitempty"`
+	StartedAt    time.Time      `json:"startedAt"`
+	CompletedAt  time.Time      `json:"completedAt"`
+	Records      map[string]int `json:"records"`
+	Report       interface{}    `json:"report,omitempty"`
+}
+
+// streamedFields are the JSON fields of the reports holding the items emitted as records of each type.
+var streamedFields = map[string][]string{
+	TypeVulnerability: {"report"},
+	TypePath:          {"urls"},
+	TypeLink:          {"links"},
+	TypeAttempt:       {"webServers"},
+	TypeRequest:       {"results"},
+	TypeChange:        {"changes"},
+}
+
+// Emitter writes records to the underlying writer. It is safe for concurrent use, each record is written with a single
+// write so that records of concurrent workers are never interleaved.
+type Emitter struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	handler func(Record)
+	counts  map[string]int
+	emitted int
+	err     error
+}
+
+// NewEmitter creates an Emitter writing to the provided writer.
+func NewEmitter(writer io.Writer) *Emitter {
+	return &Emitter{writer: writer, counts: make(map[string]int)}
+}
+
+// NewHandlerEmitter creates an Emitter passing each record to the handler instead of writing it, for callers embedding
+// the scanners. The handler is never called concurrently.
+func NewHandlerEmitter(handler func(Record)) *Emitter {
+	return &Emitter{handler: handler, counts: make(map[string]int)}
+}
+
+// Emit writes a record of the provided type. Write errors are kept and returned by Close, later records are dropped.
+func (e *Emitter) Emit(recordType string, data interface{}) {
+	record := Record{Type: recordType, Timestamp: time.Now(), Data: data}
+	if e.handler != nil {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.handler(record)
+		e.counts[recordType]++
+		e.emitted++
+		return
+	}
+	line, err := json.Marshal(record)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return
+	}
+	if err != nil {
+		e.err = err
+		return
+	}
+	if _, err := e.writer.Write(append(line, '\n')); err != nil {
+		e.err = err
+		return
+	}
+	e.counts[recordType]++
+	e.emitted++
+}
+
+// Close writes the summary record that ends the stream, filling in the record counts, and returns the first error
+// encountered while writing the stream.
+func (e *Emitter) Close(summary Summary, report interface{}) error {
+	e.mu.Lock()
+	summary.Records = make(map[string]int, len(e.counts))
+	for recordType, count := range e.counts {
+		summary.Records[recordType] = count
+	}
+	summary.Report = report
+	if e.emitted > 0 {
+		summary.Report = withoutStreamed(report, e.counts)
+	}
+	e.mu.Unlock()
+
+	e.Emit(TypeSummary, summary)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.err
+}
+
+// withoutStreamed returns the JSON fields of the report except those holding the items of the emitted record types, or
+// the report itself when it is not a JSON object.
+func withoutStreamed(report interface{}, counts map[string]int) interface{} {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return report
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return report
+	}
+	for recordType := range counts {
+		for _, name := range streamedFields[recordType] {
+			delete(fields, name)
+		}
+	}
+	return fields
+}
+
+type emitterKey struct{}
+
+// WithEmitter returns a copy of the context carrying the Emitter.
+func WithEmitter(ctx context.Context, emitter *Emitter) context.Context {
+	return context.WithValue(ctx, emitterKey{}, emitter)
+}
+
+// FromContext returns the Emitter carried by the context, or nil when the output is not streamed.
+func FromContext(ctx context.Context) *Emitter {
+	if ctx == nil {
+		return nil
+	}
+	emitter, _ := ctx.Value(emitterKey{}).(*Emitter)
+	return emitter
+}
+
+// Emit writes a record to the Emitter carried by the context. It does nothing when the output is not streamed.
+func Emit(ctx context.Context, recordType string, data interface{}) {
+	if emitter := FromContext(ctx); emitter != nil {
+		emitter.Emit(recordType, data)
+	}
+}
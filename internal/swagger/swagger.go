@@ -14,6 +14,11 @@ import (
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -32,6 +37,7 @@ func PerformSwaggerScan(ctx context.Context, target string, noSandbox bool) webs
 	if noSandbox {
 		opts = append(opts, chromedp.Flag("no-sandbox", true))
 	}
+	opts = append(opts, browserOptions(httpclient.FromContext(ctx))...)
 
 	ctx, cancel := chromedp.NewExecAllocator(ctx, opts...)
 	defer cancel()
@@ -61,7 +67,7 @@ func PerformSwaggerScan(ctx context.Context, target string, noSandbox bool) webs
 	report.SchemaUrl = &swaggerURL
 
 	// Fetch the Swagger JSON
-	bodyBytes, err := fetchSwaggerJSON(swaggerURL)
+	bodyBytes, err := fetchSwaggerJSON(ctx, swaggerURL)
 	if err != nil {
 		errMsg := fmt.Sprintf("Error fetching Swagger JSON: %v", err)
 		report.Errors = append(report.Errors, errMsg)
@@ -110,17 +116,144 @@ func PerformSwaggerScan(ctx context.Context, target string, noSandbox bool) webs
 }
 
 func fetchHTMLContent(ctx context.Context, target string) (string, error) {
+	// The page is loaded with the headers of the authentication profile
+	factory := httpclient.FromContext(ctx)
+	authHeaders, err := factory.AuthHeaders()
+	if err != nil {
+		return "", err
+	}
+	headers := network.Headers{}
+	for name, values := range authHeaders {
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	actions := []chromedp.Action{network.SetExtraHTTPHeaders(headers)}
+	if factory.Replaying() {
+		// Every request of the page is paused and answered by the client, which enforces the scope, limits and
+		// recording. The browser follows redirects itself.
+		client := factory.Client(httpclient.WithoutRedirects())
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			paused, ok := ev.(*fetch.EventRequestPaused)
+			if !ok {
+				return
+			}
+			go func() {
+				executor := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+				_ = replayRequest(ctx, client, paused).Do(executor)
+			}()
+		})
+		actions = append(actions, fetch.Enable())
+	} else if s := factory.Scope(); s != nil {
+		// Every request of the page is paused until it is checked against the scope
+		chromedp.ListenTarget(ctx, func(ev interface{}) {
+			paused, ok := ev.(*fetch.EventRequestPaused)
+			if !ok {
+				return
+			}
+			go func() {
+				executor := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+				if err := s.CheckURL(ctx, paused.Request.URL); err != nil {
+					_ = fetch.FailRequest(paused.RequestID, network.ErrorReasonBlockedByClient).Do(executor)
+					return
+				}
+				_ = fetch.ContinueRequest(paused.RequestID).Do(executor)
+			}()
+		})
+		actions = append(actions, fetch.Enable())
+	}
+
+	if pageRecorder := factory.Recorder().Page(ctx); pageRecorder != nil && !factory.Replaying() {
+		recordNetwork(ctx, pageRecorder)
+		actions = append(actions, network.Enable())
+	}
+
 	var body string
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(target),
-		chromedp.OuterHTML("html", &body),
-	)
+	actions = append(actions, chromedp.Navigate(target), chromedp.OuterHTML("html", &body))
+	err = chromedp.Run(ctx, actions...)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch HTML: %v", err)
 	}
 	return body, nil
 }
 
+// replayRequest sends the paused request of the page with the client, and returns the action fulfilling it with the
+// response or failing it.
+func replayRequest(ctx context.Context, client *http.Client, paused *fetch.EventRequestPaused) chromedp.Action {
+	req, err := http.NewRequestWithContext(ctx, paused.Request.Method, paused.Request.URL, strings.NewReader(paused.Request.PostData))
+	if err != nil {
+		return fetch.FailRequest(paused.RequestID, network.ErrorReasonFailed)
+	}
+	for name, value := range paused.Request.Headers {
+		req.Header.Set(name, fmt.Sprint(value))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fetch.FailRequest(paused.RequestID, network.ErrorReasonFailed)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fetch.FailRequest(paused.RequestID, network.ErrorReasonFailed)
+	}
+	responseHeaders := []*fetch.HeaderEntry{}
+	for name, values := range resp.Header {
+		for _, value := range values {
+			responseHeaders = append(responseHeaders, &fetch.HeaderEntry{Name: name, Value: value})
+		}
+	}
+	return fetch.FulfillRequest(paused.RequestID, int64(resp.StatusCode)).
+		WithResponseHeaders(responseHeaders).
+		WithBody(base64.StdEncoding.EncodeToString(body))
+}
+
+// recordNetwork records the requests of the page in the HAR recorder from its network events.
+func recordNetwork(ctx context.Context, pageRecorder *har.PageRecorder) {
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			pageRecorder.RequestSent(string(e.RequestID), e.WallTime.Time(), e.Timestamp.Time(), e.Request.Method,
+				e.Request.URL+e.Request.URLFragment, networkHeaders(e.Request.Headers), []byte(e.Request.PostData),
+				pageResponse(e.RedirectResponse))
+		case *network.EventResponseReceived:
+			pageRecorder.ResponseReceived(string(e.RequestID), e.Timestamp.Time(), *pageResponse(e.Response))
+		case *network.EventLoadingFinished:
+			// Listeners must not block, and the body is fetched with a command of the page
+			go pageRecorder.Finished(string(e.RequestID), e.Timestamp.Time(), func() []byte {
+				executor := cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)
+				body, _ := network.GetResponseBody(e.RequestID).Do(executor)
+				return body
+			})
+		case *network.EventLoadingFailed:
+			pageRecorder.Failed(string(e.RequestID), e.Timestamp.Time(), e.ErrorText)
+		}
+	})
+}
+
+func pageResponse(response *network.Response) *har.PageResponse {
+	if response == nil {
+		return nil
+	}
+	return &har.PageResponse{
+		Status:          int(response.Status),
+		StatusText:      response.StatusText,
+		Protocol:        response.Protocol,
+		Headers:         networkHeaders(response.Headers),
+		RequestHeaders:  networkHeaders(response.RequestHeaders),
+		RemoteIPAddress: response.RemoteIPAddress,
+	}
+}
+
+func networkHeaders(headers network.Headers) http.Header {
+	converted := http.Header{}
+	for name, value := range headers {
+		// Headers with several values are reported joined by newlines
+		for _, v := range strings.Split(fmt.Sprint(value), "\n") {
+			converted.Add(name, v)
+		}
+	}
+	return converted
+}
+
 func findSwaggerURL(body, target string) (string, error) {
 	doc, err := html.Parse(strings.NewReader(body))
 	if err != nil {
@@ -157,7 +290,7 @@ func findSwaggerURL(body, target string) (string, error) {
 	for _, urlStr := range potentialURLs {
 		swaggerURL := constructSwaggerURL(urlStr, target)
 		if _, err := url.ParseRequestURI(swaggerURL); err == nil {
-			fmt.Printf("Valid docs link: %s\n", swaggerURL)
+			log.Printf("Valid docs link: %s\n", swaggerURL)
 			return swaggerURL, nil
 		}
 	}
@@ -170,7 +303,7 @@ func constructSwaggerURL(urlStr, target string) string {
 	if err != nil || !parsedURL.IsAbs() {
 		parsedTarget, err := url.Parse(target)
 		if err != nil {
-			fmt.Printf("Error parsing target URL: %v\n", err)
+			log.Printf("Error parsing target URL: %v\n", err)
 			return ""
 		}
 		baseURL := fmt.Sprintf("%s://%s", parsedTarget.Scheme, parsedTarget.Host)
@@ -182,8 +315,27 @@ func constructSwaggerURL(urlStr, target string) string {
 	return parsedURL.String()
 }
 
-func fetchSwaggerJSON(swaggerURL string) ([]byte, error) {
-	resp, err := http.Get(swaggerURL)
+// browserOptions configures the browser with the proxy, TLS verification and user agent of the HTTP clients.
+func browserOptions(factory *httpclient.Factory) []chromedp.ExecAllocatorOption {
+	options := []chromedp.ExecAllocatorOption{}
+	if proxy := factory.ProxyURL(); proxy != "" {
+		options = append(options, chromedp.ProxyServer(proxy))
+	}
+	if factory.TLSConfig().InsecureSkipVerify {
+		options = append(options, chromedp.Flag("ignore-certificate-errors", true))
+	}
+	if userAgent := factory.Options().UserAgent; userAgent != "" {
+		options = append(options, chromedp.UserAgent(userAgent))
+	}
+	return options
+}
+
+func fetchSwaggerJSON(ctx context.Context, swaggerURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, swaggerURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Swagger JSON request: %v", err)
+	}
+	resp, err := httpclient.FromContext(ctx).Client().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch Swagger JSON: %v", err)
 	}
@@ -636,12 +788,12 @@ func convertSchemaToRequestSchema(s *base.Schema, seenSchemas map[*base.Schema]b
 	}
 
 	if s.Default != nil {
-		defaultStr := fmt.Sprintf("%v", s.Default)
+		defaultStr := yamlNodeString(s.Default)
 		rs.Default = &defaultStr
 	}
 
 	if s.Example != nil {
-		rs.Example = s.Example
+		rs.Example = yamlNodeValue(s.Example)
 	}
 
 	convertEnumValues(s, rs, report)
@@ -729,6 +881,27 @@ func convertSchemaToRequestSchema(s *base.Schema, seenSchemas map[*base.Schema]b
 					Format:      strPtr(propSchema.Format),
 					Description: strPtr(propSchema.Description),
 					Required:    &required,
+					Minimum:     propSchema.Minimum,
+					Maximum:     propSchema.Maximum,
+				}
+				for _, v := range propSchema.Enum {
+					prop.Enum = append(prop.Enum, yamlNodeString(v))
+				}
+				if propSchema.Example != nil {
+					example := yamlNodeString(propSchema.Example)
+					prop.Example = &example
+				}
+				if propSchema.Default != nil {
+					defaultStr := yamlNodeString(propSchema.Default)
+					prop.Default = &defaultStr
+				}
+				if propSchema.MinLength != nil {
+					minLength := int(*propSchema.MinLength)
+					prop.MinLength = &minLength
+				}
+				if propSchema.MaxLength != nil {
+					maxLength := int(*propSchema.MaxLength)
+					prop.MaxLength = &maxLength
 				}
 				if propSchema.Items != nil && propSchema.Items.A != nil {
 					prop.Items = convertSchemaToRequestSchema(propSchema.Items.A.Schema(), seenSchemas, report)
@@ -781,6 +954,27 @@ func convertSchemaToRequestSchema(s *base.Schema, seenSchemas map[*base.Schema]b
 	return rs
 }
 
+// yamlNodeValue decodes a schema value such as an example into plain Go values that marshal cleanly to JSON.
+func yamlNodeValue(node *yaml.Node) interface{} {
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return node.Value
+	}
+	return value
+}
+
+// yamlNodeString renders a schema value as a string, encoding values that are not scalars as JSON.
+func yamlNodeString(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	encoded, err := json.Marshal(yamlNodeValue(node))
+	if err != nil {
+		return node.Value
+	}
+	return string(encoded)
+}
+
 func strPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -4,10 +4,16 @@ import (
 	"context"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/stream"
 	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
 	"github.com/projectdiscovery/nuclei/v3/pkg/model"
 	nucleiOutput "github.com/projectdiscovery/nuclei/v3/pkg/output"
+	nucleiTypes "github.com/projectdiscovery/nuclei/v3/pkg/types"
 )
 
 // VulnerabilityContext represents an instance of a vulnerability, with all of the information needed to contextualize
@@ -47,41 +53,208 @@ func parseResultIntoFinding(result nucleiOutput.ResultEvent) VulnerabilityFindin
 	return VulnerabilityFinding{ID: buildID(result), Info: result.Info, Context: parseResultIntoContext(result)}
 }
 
+// clientOptions configures the nuclei engine with the proxy, headers, authentication, scope and limits of the HTTP
+// clients. The returned function must be called once the scan is done.
+func clientOptions(ctx context.Context) ([]nuclei.NucleiSDKOptions, func(), error) {
+	factory := httpclient.FromContext(ctx)
+	engineOptions := []nuclei.NucleiSDKOptions{}
+	headers, err := factory.HeaderLines()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(headers) > 0 {
+		engineOptions = append(engineOptions, nuclei.WithHeaders(headers))
+	}
+	proxy, closeProxy, err := factory.EngineProxy(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proxy != "" {
+		engineOptions = append(engineOptions, nuclei.WithProxy([]string{proxy}, false))
+	}
+	limits := factory.Limiter().EngineLimits()
+	if limits.PerSecond() > 0 {
+		engineOptions = append(engineOptions, nuclei.WithGlobalRateLimit(limits.PerSecond(), time.Second))
+	} else if limits.PerMinute() > 0 {
+		engineOptions = append(engineOptions, nuclei.WithGlobalRateLimit(limits.PerMinute(), time.Minute))
+	}
+	if limits.Concurrency > 0 {
+		engineOptions = append(engineOptions, nuclei.WithConcurrency(nuclei.Concurrency{
+			TemplateConcurrency:           limits.Cap(25),
+			HostConcurrency:               limits.Cap(25),
+			HeadlessHostConcurrency:       limits.Cap(10),
+			HeadlessTemplateConcurrency:   limits.Cap(10),
+			JavascriptTemplateConcurrency: limits.Cap(120),
+			TemplatePayloadConcurrency:    limits.Cap(25),
+			ProbeConcurrency:              limits.Cap(50),
+		}))
+	}
+	return engineOptions, closeProxy, nil
+}
+
+// vulnParams identify the checkpoint of a vulnerability scan.
+type vulnParams struct {
+	Target                  string   `json:"target"`
+	Tags                    []string `json:"tags"`
+	Severity                string   `json:"severity"`
+	TemplateDirectory       string   `json:"templateDirectory"`
+	CustomTemplateDirectory string   `json:"customTemplateDirectory"`
+}
+
+// vulnState is the checkpointed progress of a vulnerability scan: the IDs of the templates that finished scanning the
+// target, and the findings found so far.
+type vulnState struct {
+	Templates []string               `json:"templates"`
+	Findings  []VulnerabilityFinding `json:"findings"`
+}
+
+// trackTemplates adds the templates nuclei completed to the state every second until the scan is done, keeping those
+// completed in earlier runs. nuclei also completes the templates it stops when the context is canceled, so those
+// completed after it are left out.
+func trackTemplates(ctx context.Context, progress *checkpoint.Checkpoint, state *vulnState, resumeCfg *nucleiTypes.ResumeCfg, done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		completed := []string{}
+		resumeCfg.RLock()
+		for id, info := range resumeCfg.Current {
+			info.RLock()
+			if info.Completed {
+				completed = append(completed, id)
+			}
+			info.RUnlock()
+		}
+		resumeCfg.RUnlock()
+		if ctx.Err() != nil {
+			return
+		}
+		progress.Update(func() {
+			known := map[string]bool{}
+			for _, id := range state.Templates {
+				known[id] = true
+			}
+			for _, id := range completed {
+				if !known[id] {
+					state.Templates = append(state.Templates, id)
+				}
+			}
+		})
+	}
+}
+
+// templatesMu guards the templates directory of the global nuclei configuration, so that concurrent scans load their
+// templates from their own directory.
+var templatesMu sync.Mutex
+
+// loadEngine creates a nuclei engine and loads its templates, from the templateDirectory instead of the default
+// directory when it is set.
+func loadEngine(templateDirectory string, options []nuclei.NucleiSDKOptions) (*nuclei.NucleiEngine, error) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	if templateDirectory != "" {
+		defaultDirectory := nuclei.DefaultConfig.TemplatesDirectory
+		nuclei.DefaultConfig.TemplatesDirectory = templateDirectory
+		defer func() {
+			nuclei.DefaultConfig.TemplatesDirectory = defaultDirectory
+		}()
+	}
+	ne, err := nuclei.NewNucleiEngine(options...)
+	if err != nil {
+		return nil, err
+	}
+	if err := ne.LoadAllTemplates(); err != nil {
+		ne.Close()
+		return nil, err
+	}
+	return ne, nil
+}
+
 // PerformVulnScan performs a vulnerability scan against a target URL, using the provided tags and severity to filter the
 // templates that are used in the scan. The scan uses the provided templateDirectory and customTemplateDirectory to load
-// the templates that are used in the scan.
+// the templates that are used in the scan. The templates that finished scanning the target and the findings are
+// checkpointed, so that a resumed scan only runs the other templates. The report holds the findings found before an
+// error.
 func PerformVulnScan(ctx context.Context, target string, tags []string, severity string, templateDirectory string, customTemplateDirectory string) (VulnerabilityReport, error) {
 	report := VulnerabilityReport{Target: target}
-	if templateDirectory != "" {
-		nuclei.DefaultConfig.TemplatesDirectory = templateDirectory
+	state := vulnState{Templates: []string{}, Findings: []VulnerabilityFinding{}}
+	params := vulnParams{
+		Target:                  target,
+		Tags:                    tags,
+		Severity:                severity,
+		TemplateDirectory:       templateDirectory,
+		CustomTemplateDirectory: customTemplateDirectory,
 	}
-	ne, err := nuclei.NewNucleiEngine(BuildTemplateFilters(ctx, tags, severity), LoadCustomTemplates(ctx, customTemplateDirectory))
+	progress, err := checkpoint.Open(ctx, "vuln", params, &state)
 	if err != nil {
-		return VulnerabilityReport{}, err
+		return report, err
+	}
+	if progress.Completed() {
+		report.Reports = state.Findings
+		return report, nil
 	}
-	err = ne.LoadAllTemplates()
+	httpOptions, closeProxy, err := clientOptions(ctx)
 	if err != nil {
-		return VulnerabilityReport{}, err
+		return report, err
 	}
+	defer closeProxy()
+	engineOptions := append([]nuclei.NucleiSDKOptions{BuildTemplateFilters(ctx, tags, severity), LoadCustomTemplates(ctx, customTemplateDirectory)}, httpOptions...)
+	ne, err := loadEngine(templateDirectory, engineOptions)
+	if err != nil {
+		return report, err
+	}
+	defer ne.Close()
 	// Parse the target URL to remove the protocol
 	parsedURL, err := url.Parse(target)
 	if err != nil {
-		return VulnerabilityReport{}, err
+		return report, err
 	}
 	address := strings.TrimPrefix(parsedURL.String(), parsedURL.Scheme+"://")
 
+	// nuclei skips the templates its resume configuration completed, those that finished in earlier runs
+	resumeCfg := ne.GetExecuterOptions().ResumeCfg
+	resumeCfg.Lock()
+	for _, id := range state.Templates {
+		resumeCfg.ResumeFrom[id] = &nucleiTypes.ResumeInfo{Completed: true}
+	}
+	resumeCfg.Unlock()
+	done := make(chan struct{})
+	go trackTemplates(ctx, progress, &state, resumeCfg, done)
+
+	// Templates interrupted before they were tracked as completed run again, so their findings of earlier runs are not
+	// added twice
+	resumed := map[string]bool{}
+	for _, finding := range state.Findings {
+		resumed[finding.ID] = true
+	}
+
 	ne.LoadTargets([]string{address}, true)
-	results := []nucleiOutput.ResultEvent{}
+	recorder := httpclient.FromContext(ctx).Recorder()
 	err = ne.ExecuteCallbackWithCtx(ctx, func(event *nucleiOutput.ResultEvent) {
-		results = append(results, *event)
+		finding := parseResultIntoFinding(*event)
+		if resumed[finding.ID] {
+			return
+		}
+		progress.Update(func() {
+			state.Findings = append(state.Findings, finding)
+		})
+		stream.Emit(ctx, stream.TypeVulnerability, finding)
+		// nuclei reports the raw traffic of its findings only, which is recorded as their evidence
+		recorder.AddRaw(ctx, event.Timestamp, 0, event.Matched, event.Request, event.Response)
+	})
+	close(done)
+	progress.Update(func() {
+		report.Reports = append(report.Reports, state.Findings...)
 	})
 	if err != nil {
-		return VulnerabilityReport{}, err
+		return report, err
 	}
-	defer ne.Close()
-	for _, result := range results {
-		vulnReport := parseResultIntoFinding(result)
-		report.Reports = append(report.Reports, vulnReport)
+	if err := progress.Finish(ctx.Err() == nil); err != nil {
+		return report, err
 	}
 	return report, nil
 }
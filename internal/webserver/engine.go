@@ -3,8 +3,12 @@ package webserver
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/checkpoint"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/stream"
 	apacheEnumerationModules "github.com/Method-Security/webscan/internal/webserver/enumerate/apache"
 	nginxEnumerationModules "github.com/Method-Security/webscan/internal/webserver/enumerate/nginx"
 	apacheValidationModules "github.com/Method-Security/webscan/internal/webserver/validate/apache"
@@ -12,7 +16,7 @@ import (
 )
 
 type Module interface {
-	ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string)
+	ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string)
 	AnalyzeResponse(response *webscan.ResponseUnion) bool
 }
 
@@ -77,11 +81,49 @@ func (e *Engine) GetModules() ([]Module, error) {
 	return moduleLibs, nil
 }
 
+// moduleName returns the name the module is registered with for the configured server.
+func (e *Engine) moduleName(module Module) webscan.ModuleName {
+	modules := e.ApacheModules[e.Config.Probe]
+	if e.Config.Server == webscan.ServerTypeNginx {
+		modules = e.NginxModules[e.Config.Probe]
+	}
+	for name, registered := range modules {
+		if registered == module {
+			return name
+		}
+	}
+	return ""
+}
+
 func (e *Engine) Run(ctx context.Context, target string) (*webscan.Attempt, []string) {
-	attempt, errs := e.Library.ModuleRun(target, e.Config)
+	attempt, errs := e.Library.ModuleRun(ctx, target, e.Config)
 	return attempt, errs
 }
 
+// launchParams identify the checkpoint of the enumeration or validation of web servers.
+type launchParams struct {
+	Server  webscan.ServerType   `json:"server"`
+	Probe   webscan.ProbeType    `json:"probe"`
+	Modules []webscan.ModuleName `json:"modules"`
+	Targets []string             `json:"targets"`
+}
+
+// launchState is the checkpointed progress of Launch: the attempts of the modules that ran against each target.
+type launchState struct {
+	Attempts []*moduleAttempt `json:"attempts"`
+}
+
+// moduleAttempt is the attempt of a module against a target, along with the errors of the module.
+type moduleAttempt struct {
+	Target  string             `json:"target"`
+	Module  webscan.ModuleName `json:"module"`
+	Attempt *webscan.Attempt   `json:"attempt,omitempty"`
+	Errors  []string           `json:"errors"`
+}
+
+// Launch runs the modules of the engine against every target. When the context carries a checkpoint store, the
+// attempts are checkpointed as the modules run, and the modules that ran against a target before the scan was
+// interrupted are not run again on resume.
 func (e *Engine) Launch(ctx context.Context) (*webscan.WebServerReport, error) {
 	resources := webscan.WebServerReport{Server: e.Config.Server, Probe: e.Config.Probe}
 	errors := []string{}
@@ -91,16 +133,54 @@ func (e *Engine) Launch(ctx context.Context) (*webscan.WebServerReport, error) {
 		return nil, err
 	}
 
+	state := launchState{}
+	progress, err := checkpoint.Open(ctx, "webserver-"+strings.ToLower(string(e.Config.Probe)), launchParams{
+		Server:  e.Config.Server,
+		Probe:   e.Config.Probe,
+		Modules: e.Config.Modules,
+		Targets: e.Config.Targets,
+	}, &state)
+	if err != nil {
+		return nil, err
+	}
+	ran := map[string]*moduleAttempt{}
+	for _, attempt := range state.Attempts {
+		ran[attempt.Target+" "+string(attempt.Module)] = attempt
+	}
+
+	interrupted := false
 	var WebServers []*webscan.WebServer
 	for _, target := range e.Config.Targets {
 		var attempts []*webscan.Attempt
 		for _, moduleLib := range moduleLibs {
+			name := e.moduleName(moduleLib)
+			if previous, ok := ran[target+" "+string(name)]; ok {
+				attempts = append(attempts, previous.Attempt)
+				errors = append(errors, previous.Errors...)
+				continue
+			}
+			if ctx.Err() != nil {
+				interrupted = true
+				continue
+			}
+
 			// Set current module library in the engine
 			e.Library = moduleLib
 
-			// Marshal Attempt results
-			attempt, errs := e.Run(ctx, target)
+			// Marshal Attempt results, recording the traffic of the module under its name
+			attempt, errs := e.Run(httpclient.WithModule(ctx, string(name)), target)
+			if ctx.Err() != nil {
+				// The module was stopped before it completed, so it runs again on resume
+				interrupted = true
+				continue
+			}
+			progress.Update(func() {
+				state.Attempts = append(state.Attempts, &moduleAttempt{Target: target, Module: name, Attempt: attempt, Errors: errs})
+			})
 			attempts = append(attempts, attempt)
+			if attempt != nil && (attempt.Finding || !e.Config.SuccessfulOnly) {
+				stream.Emit(ctx, stream.TypeAttempt, webscan.WebServer{Target: target, Attempts: []*webscan.Attempt{attempt}})
+			}
 			errors = append(errors, errs...)
 		}
 
@@ -117,6 +197,9 @@ func (e *Engine) Launch(ctx context.Context) (*webscan.WebServerReport, error) {
 		WebServer := webscan.WebServer{Target: target, Attempts: attempts}
 		WebServers = append(WebServers, &WebServer)
 	}
+	if err := progress.Finish(!interrupted); err != nil {
+		errors = append(errors, err.Error())
+	}
 
 	// Marshal Report
 	resources.WebServers = WebServers
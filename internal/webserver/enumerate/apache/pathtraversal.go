@@ -1,6 +1,7 @@
 package webserver
 
 import (
+	"context"
 	"net/http"
 	"time"
 
@@ -33,13 +34,13 @@ var commonExposedPaths = []string{
 	"/test.cgi",
 }
 
-func (PathTraversalLib *PathTraversalLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (PathTraversalLib *PathTraversalLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	//Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNamePathTraversal, Timestamp: time.Now()}
 	findingGlobal := false
 
 	// Enumerate paths
-	paths, errors := helpers.PathTraversal(target, config.Timeout, commonExposedPaths)
+	paths, errors := helpers.PathTraversal(ctx, target, config.Timeout, commonExposedPaths)
 	for _, path := range paths {
 		finding := path.Response != nil && PathTraversalLib.AnalyzeResponse(webscan.NewResponseUnionFromGeneralResponse(path.Response))
 		path.Finding = &finding
@@ -1,17 +1,17 @@
 package webserver
 
 import (
-	"crypto/tls"
-	"net/http"
+	"context"
 	"strings"
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type XPoweredByHeaderGrabLibrary struct{}
 
-func (XPoweredByHeaderGrabLib *XPoweredByHeaderGrabLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (XPoweredByHeaderGrabLib *XPoweredByHeaderGrabLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	//Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNameXPoweredByHeaderGrab, Timestamp: time.Now()}
 	errors := []string{}
@@ -22,12 +22,7 @@ func (XPoweredByHeaderGrabLib *XPoweredByHeaderGrabLibrary) ModuleRun(target str
 	response := webscan.VersionEnumerateResponseInfo{}
 
 	// Enumerate target
-	client := &http.Client{
-		Timeout: time.Duration(config.Timeout) * time.Millisecond,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
+	client := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(config.Timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
 	resp, err := client.Get(target)
 	if err != nil {
 		errorMessage := err.Error()
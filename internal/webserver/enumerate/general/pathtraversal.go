@@ -1,15 +1,15 @@
 package webserver
 
 import (
-	"crypto/tls"
+	"context"
 	"io/ioutil"
-	"net/http"
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
-func PathTraversal(target string, timeout int, commonExposedPaths []string) ([]*webscan.PathInfo, []string) {
+func PathTraversal(ctx context.Context, target string, timeout int, commonExposedPaths []string) ([]*webscan.PathInfo, []string) {
 	//Initialize structs
 	var paths []*webscan.PathInfo
 	errors := []string{}
@@ -23,12 +23,7 @@ func PathTraversal(target string, timeout int, commonExposedPaths []string) ([]*
 		}
 		path := webscan.PathInfo{Path: filepath, Request: &request}
 
-		client := &http.Client{
-			Timeout: time.Duration(timeout) * time.Millisecond,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
+		client := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
 		resp, err := client.Get(fullURL)
 		if err != nil {
 			errorMessage := err.Error()
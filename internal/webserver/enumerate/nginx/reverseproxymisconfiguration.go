@@ -1,7 +1,7 @@
 package webserver
 
 import (
-	"crypto/tls"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -9,18 +9,20 @@ import (
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type ReverseProxyCheckLibrary struct{}
 
-func (ReverseProxyCheckLib *ReverseProxyCheckLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (ReverseProxyCheckLib *ReverseProxyCheckLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	//Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNameReverseProxyMisconfiguration, Timestamp: time.Now()}
 	errors := []string{}
 
 	// Enumerate target
+	payloadURL := "http://127.0.0.1:80"
 	params := map[string]string{
-		"url": url.QueryEscape("http://127.0.0.1:80"),
+		"url": url.QueryEscape(payloadURL),
 	}
 	attackURL := target + "/?url=" + params["url"]
 	request := webscan.GeneralRequestInfo{
@@ -29,13 +31,14 @@ func (ReverseProxyCheckLib *ReverseProxyCheckLibrary) ModuleRun(target string, c
 		Params: params,
 	}
 
-	client := &http.Client{
-		Timeout: time.Duration(config.Timeout) * time.Millisecond,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	// The payload makes the target send a request to the payload URL, which must be in scope as well
+	factory := httpclient.FromContext(ctx)
+	var resp *http.Response
+	err := factory.Scope().CheckURL(ctx, payloadURL)
+	if err == nil {
+		client := factory.Client(httpclient.WithTimeout(time.Duration(config.Timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
+		resp, err = client.Get(attackURL)
 	}
-	resp, err := client.Get(attackURL)
 	if err != nil {
 		errorMessage := err.Error()
 		errors = append(errors, err.Error())
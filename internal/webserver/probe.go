@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Method-Security/webscan/internal/httpclient"
 	"github.com/projectdiscovery/httpx/runner"
 )
 
@@ -31,6 +32,7 @@ func performWebServerProbe(ctx context.Context, targets []string, timeout time.D
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	recorder := httpclient.FromContext(ctx).Recorder()
 	options := runner.Options{
 		Methods:         "GET",
 		InputTargetHost: targets,
@@ -45,8 +47,33 @@ func performWebServerProbe(ctx context.Context, targets []string, timeout time.D
 				Title:  r.Title,
 			}
 			urls = append(urls, urlDetails)
+			if elapsed, err := time.ParseDuration(r.ResponseTime); err == nil {
+				recorder.AddRaw(ctx, r.Timestamp.Add(-elapsed), elapsed, r.URL, r.Request, r.Raw)
+			}
 		},
 	}
+	// httpx only reports the raw traffic of its results when it is asked to include the responses in its output
+	options.ResponseInStdout = recorder != nil
+
+	// Requests share the proxy, headers, authentication, retries, scope and limits of the HTTP clients
+	factory := httpclient.FromContext(ctx)
+	options.Retries = factory.Options().Retries
+	headers, err := factory.HeaderLines()
+	if err != nil {
+		return urls, errors, err
+	}
+	options.CustomHeaders = append(options.CustomHeaders, headers...)
+	proxy, closeProxy, err := factory.EngineProxy(ctx)
+	if err != nil {
+		return urls, errors, err
+	}
+	defer closeProxy()
+	options.HTTPProxy = proxy
+	limits := factory.Limiter().EngineLimits()
+	options.RateLimit, options.RateLimitMinute = limits.PerSecond(), limits.PerMinute()
+	if limits.Concurrency > 0 {
+		options.Threads = limits.Cap(50)
+	}
 
 	if err := options.ValidateOptions(); err != nil {
 		return urls, errors, err
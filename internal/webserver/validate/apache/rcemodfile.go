@@ -1,7 +1,7 @@
 package webserver
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type RCEModFileLibrary struct{}
@@ -29,7 +30,7 @@ var commonVulnerablePaths = []string{
 	"/cgi-bin/printenv.cgi",
 }
 
-func (RCEModFileLib *RCEModFileLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (RCEModFileLib *RCEModFileLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	// Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNameRceModFile, Timestamp: time.Now()}
 	errors := []string{}
@@ -51,12 +52,7 @@ func (RCEModFileLib *RCEModFileLibrary) ModuleRun(target string, config *webscan
 		}
 		path := webscan.PathInfo{Path: filepath, Request: &request}
 
-		client := &http.Client{
-			Timeout: time.Duration(config.Timeout) * time.Millisecond,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
+		client := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(config.Timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
 		resp, err := client.Get(exploitURL)
 		if err != nil {
 			errorMessage := err.Error()
@@ -2,16 +2,17 @@ package webserver
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"net/http"
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type BufferOverflowContentHeaderLibrary struct{}
 
-func (BufferOverflowContentHeaderLib *BufferOverflowContentHeaderLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (BufferOverflowContentHeaderLib *BufferOverflowContentHeaderLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	// Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNameBufferOverflowContentHeader, Timestamp: time.Now()}
 	errors := []string{}
@@ -37,12 +38,7 @@ func (BufferOverflowContentHeaderLib *BufferOverflowContentHeaderLibrary) Module
 		req.Header.Set(k, v)
 	}
 
-	client := &http.Client{
-		Timeout: time.Duration(config.Timeout) * time.Millisecond,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
+	client := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(config.Timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
 	resp, err := client.Do(req)
 	if err != nil {
 		errorMessage := err.Error()
@@ -1,18 +1,19 @@
 package webserver
 
 import (
-	"crypto/tls"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 
 	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/httpclient"
 )
 
 type CRLFInjectionLibrary struct{}
 
-func (CRLFInjectionLib *CRLFInjectionLibrary) ModuleRun(target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
+func (CRLFInjectionLib *CRLFInjectionLibrary) ModuleRun(ctx context.Context, target string, config *webscan.WebServerTypeConfig) (*webscan.Attempt, []string) {
 	// Initialize structs
 	attempt := webscan.Attempt{Name: webscan.ModuleNameCrlfInjection, Timestamp: time.Now()}
 	errors := []string{}
@@ -24,12 +25,7 @@ func (CRLFInjectionLib *CRLFInjectionLibrary) ModuleRun(target string, config *w
 		Method: webscan.HttpMethodGet,
 		Url:    attackURL,
 	}
-	client := &http.Client{
-		Timeout: time.Duration(config.Timeout) * time.Millisecond,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
-	}
+	client := httpclient.FromContext(ctx).Client(httpclient.WithTimeout(time.Duration(config.Timeout) * time.Millisecond), httpclient.WithoutTLSVerification())
 	resp, err := client.Get(attackURL)
 	if err != nil {
 		errorMessage := err.Error()
@@ -22,6 +22,11 @@ func main() {
 	webscan.InitFingerprintCommand()
 	webscan.InitPagecaptureCommand()
 	webscan.InitRoutecaptureCommand()
+	webscan.InitReportCommand()
+	webscan.InitServeCommand()
+	webscan.InitPipelineCommand()
+	webscan.InitDiffCommand()
+	webscan.InitResultsCommand()
 
 	if err := webscan.RootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -0,0 +1,137 @@
+package webscan
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Method-Security/webscan/internal/graphql"
+	"github.com/Method-Security/webscan/internal/grpc"
+	"github.com/Method-Security/webscan/internal/requests"
+	"github.com/Method-Security/webscan/internal/swagger"
+)
+
+// defaultConcurrency is the number of requests sent concurrently by the batch scans when their options do not set it.
+const defaultConcurrency = 5
+
+// SwaggerOptions configure a Swagger enumeration, the equivalent of `webscan app enumerate swagger`.
+type SwaggerOptions struct {
+	// Target is the URL of the Swagger UI or of the OpenAPI document.
+	Target string
+	// NoSandbox disables the sandbox of the browser rendering the Swagger UI.
+	NoSandbox bool
+}
+
+// Swagger enumerates the routes of the API documented by a Swagger or OpenAPI document.
+func (s *Scanner) Swagger(ctx context.Context, options SwaggerOptions) (*RoutesReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	report := swagger.PerformSwaggerScan(s.context(ctx, nil), options.Target, options.NoSandbox)
+	return &report, result(ctx, "swagger", options.Target, report.Errors)
+}
+
+// GraphQLOptions configure a GraphQL enumeration, the equivalent of `webscan app enumerate graphql`.
+type GraphQLOptions struct {
+	// Target is the URL of the GraphQL endpoint.
+	Target string
+}
+
+// GraphQL enumerates the schema of a GraphQL endpoint with an introspection query.
+func (s *Scanner) GraphQL(ctx context.Context, options GraphQLOptions) (*RoutesReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	report := graphql.PerformGraphQLScan(s.context(ctx, nil), options.Target)
+	return &report, result(ctx, "graphql", options.Target, report.Errors)
+}
+
+// GRPCOptions configure a gRPC enumeration, the equivalent of `webscan app enumerate grpc`.
+type GRPCOptions struct {
+	// Target is the address of the gRPC server, as host:port.
+	Target string
+}
+
+// GRPC enumerates the services of a gRPC server with server reflection.
+func (s *Scanner) GRPC(ctx context.Context, options GRPCOptions) (*RoutesReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	report := grpc.PerformGRPCScan(s.context(ctx, nil), options.Target)
+	return &report, result(ctx, "grpc", options.Target, report.Errors)
+}
+
+// RequestOptions configure a single request, the equivalent of `webscan app requests`.
+type RequestOptions struct {
+	BaseURL string
+	Path    string
+	Method  string
+	// Params are the parameters of the request, as JSON strings.
+	Params RequestParams
+	// VulnTypes are the vulnerabilities checked by tampering with the parameters, such as SQL, XSS or AUTH.
+	VulnTypes []string
+}
+
+// Request sends a request to a route of an API and checks it for the vulnerability types.
+func (s *Scanner) Request(ctx context.Context, options RequestOptions) (*RequestReport, error) {
+	for _, option := range []struct{ name, value string }{{"BaseURL", options.BaseURL}, {"Path", options.Path}, {"Method", options.Method}} {
+		if err := required(option.name, option.value); err != nil {
+			return nil, err
+		}
+	}
+	report := requests.PerformRequestScan(s.context(ctx, nil), options.BaseURL, options.Path, options.Method, options.Params, options.VulnTypes)
+	return &report, result(ctx, "request", options.BaseURL+options.Path, report.Errors)
+}
+
+// RequestBatchOptions configure a batch of requests, the equivalent of `webscan app requests --input`.
+type RequestBatchOptions struct {
+	// Target names the batch in the report, such as the file the requests were loaded from.
+	Target string
+	// BaseURL is the base URL of the requests that do not set their own.
+	BaseURL  string
+	Requests []RequestParams
+	// VulnTypes are the vulnerabilities checked by tampering with the parameters, such as SQL, XSS or AUTH.
+	VulnTypes []string
+	// Concurrency is the number of requests sent concurrently, 5 when zero.
+	Concurrency int
+	// OnEvent receives an EventRequest for every request once it was sent.
+	OnEvent func(Event)
+}
+
+// RequestBatch sends a batch of requests concurrently and checks them for the vulnerability types.
+func (s *Scanner) RequestBatch(ctx context.Context, options RequestBatchOptions) (*RequestBatchReport, error) {
+	if len(options.Requests) == 0 {
+		return nil, &OptionError{Option: "Requests", Err: errors.New("required")}
+	}
+	if options.Concurrency == 0 {
+		options.Concurrency = defaultConcurrency
+	}
+	report := requests.PerformBatchScan(s.context(ctx, options.OnEvent), options.Target, options.BaseURL, "", options.Requests, options.VulnTypes, options.Concurrency)
+	return &report, result(ctx, "request batch", options.Target, report.Errors)
+}
+
+// AuthzOptions configure a test for broken object level authorization, the equivalent of `webscan app authz`.
+type AuthzOptions struct {
+	// Target names the tested requests in the report, such as the target of the routes they were generated from.
+	Target   string
+	Requests []RequestParams
+	// Identities are the identities the requests are sent and replayed as, at least two.
+	Identities []*AuthzIdentity
+	// Concurrency is the number of routes tested concurrently, 5 when zero.
+	Concurrency int
+}
+
+// Authz sends every request as each identity and replays it as the others, reporting the responses that return
+// another identity's data.
+func (s *Scanner) Authz(ctx context.Context, options AuthzOptions) (*AuthzReport, error) {
+	if len(options.Requests) == 0 {
+		return nil, &OptionError{Option: "Requests", Err: errors.New("required")}
+	}
+	if len(options.Identities) < 2 {
+		return nil, &OptionError{Option: "Identities", Err: errors.New("at least two identities are required")}
+	}
+	if options.Concurrency == 0 {
+		options.Concurrency = defaultConcurrency
+	}
+	report := requests.PerformAuthzScan(s.context(ctx, nil), options.Target, options.Requests, options.Identities, options.Concurrency)
+	return &report, result(ctx, "authz", options.Target, report.Errors)
+}
@@ -0,0 +1,46 @@
+package webscan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Method-Security/webscan/internal/scope"
+)
+
+// ErrInvalidOptions is matched by the errors returned for invalid options, see OptionError.
+var ErrInvalidOptions = errors.New("invalid options")
+
+// OptionError is returned when an option of a Scanner or of a scan is invalid.
+type OptionError struct {
+	Option string
+	Err    error
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("invalid option %s: %v", e.Option, e.Err)
+}
+
+func (e *OptionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrInvalidOptions.
+func (e *OptionError) Is(target error) bool {
+	return target == ErrInvalidOptions
+}
+
+// ScanError is returned along with the report of a scan that completed with errors, such as unreachable targets or
+// requests blocked by the scope. The errors are those listed in the report.
+type ScanError struct {
+	Scan   string
+	Target string
+	Errors []string
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("%s scan of %s completed with %d errors: %s", e.Scan, e.Target, len(e.Errors), strings.Join(e.Errors, "; "))
+}
+
+// OutOfScopeError is the error of a request that was blocked because it is not in scope.
+type OutOfScopeError = scope.OutOfScopeError
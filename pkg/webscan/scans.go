@@ -0,0 +1,191 @@
+package webscan
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/browserbase"
+	"github.com/Method-Security/webscan/internal/fingerprint"
+	"github.com/Method-Security/webscan/internal/fuzz"
+	"github.com/Method-Security/webscan/internal/routecapture"
+	"github.com/Method-Security/webscan/internal/spider"
+	"github.com/Method-Security/webscan/internal/vuln"
+)
+
+// FingerprintOptions configure a fingerprint, the equivalent of `webscan fingerprint`.
+type FingerprintOptions struct {
+	Target string
+}
+
+// Fingerprint reports the HTTP headers and TLS configuration of a target.
+func (s *Scanner) Fingerprint(ctx context.Context, options FingerprintOptions) (*FingerprintReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	report := fingerprint.PerformFingerprint(s.context(ctx, nil), options.Target)
+	return &report, result(ctx, "fingerprint", options.Target, report.Errors)
+}
+
+// VulnOptions configure a nuclei scan, the equivalent of `webscan vuln` and `webscan app fingerprint`.
+type VulnOptions struct {
+	Target string
+	// Tags and Severities filter the templates of the scan.
+	Tags       []string
+	Severities []string
+	// TemplateDirectory is the directory of the default templates, and CustomTemplateDirectory a directory of
+	// additional templates. The directory of the default templates is global to nuclei, so concurrent scans must use
+	// the same one.
+	TemplateDirectory       string
+	CustomTemplateDirectory string
+	// OnEvent receives an EventVulnerability for every finding as soon as it is found.
+	OnEvent func(Event)
+}
+
+// Vuln scans a target with the nuclei templates matching the tags and severities.
+func (s *Scanner) Vuln(ctx context.Context, options VulnOptions) (*VulnerabilityReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	report, err := vuln.PerformVulnScan(s.context(ctx, options.OnEvent), options.Target, options.Tags,
+		strings.Join(options.Severities, ","), options.TemplateDirectory, options.CustomTemplateDirectory)
+	if err != nil {
+		return &report, err
+	}
+	return &report, result(ctx, "vuln", options.Target, nil)
+}
+
+// AppFingerprint identifies the type of application served by a target, such as Swagger, GraphQL or Kubernetes, with a
+// nuclei scan whose tags default to those of the application types.
+func (s *Scanner) AppFingerprint(ctx context.Context, options VulnOptions) (*VulnerabilityReport, error) {
+	if len(options.Tags) == 0 {
+		options.Tags = []string{"swagger", "k8s", "graphql", "grpc", "bucket"}
+	}
+	return s.Vuln(ctx, options)
+}
+
+// SpiderOptions configure a crawl, the equivalent of `webscan spider`.
+type SpiderOptions struct {
+	Targets []string
+	// OnEvent receives an EventLink for every crawled link.
+	OnEvent func(Event)
+}
+
+// Spider crawls the targets and reports the links found on their pages.
+func (s *Scanner) Spider(ctx context.Context, options SpiderOptions) (*SpiderReport, error) {
+	if len(options.Targets) == 0 {
+		return nil, &OptionError{Option: "Targets", Err: errors.New("required")}
+	}
+	targets := strings.Join(options.Targets, ",")
+	report, err := spider.PerformWebSpider(s.context(ctx, options.OnEvent), targets)
+	if err != nil {
+		return &report, err
+	}
+	return &report, result(ctx, "spider", targets, report.Errors)
+}
+
+// PathFuzzOptions configure a path fuzz, the equivalent of `webscan fuzz path`.
+type PathFuzzOptions struct {
+	Target string
+	// Pathlist is a file of newline separated paths to fuzz.
+	Pathlist string
+	// ResponseCodes are the response codes of valid responses, 200-299 when empty.
+	ResponseCodes string
+	// IncludeBaseContentMatches reports the valid responses with the same size and word count as the base path, which
+	// typically signify a redirect of the web backend and are skipped by default.
+	IncludeBaseContentMatches bool
+	// MaxTime is the maximum duration of the fuzz, 300 seconds when zero.
+	MaxTime time.Duration
+	// OnEvent receives an EventPath for every discovered path.
+	OnEvent func(Event)
+}
+
+// PathFuzz requests the paths of the pathlist on the target and reports those answered with a valid response.
+func (s *Scanner) PathFuzz(ctx context.Context, options PathFuzzOptions) (*FuzzPathReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	if err := required("Pathlist", options.Pathlist); err != nil {
+		return nil, err
+	}
+	if options.ResponseCodes == "" {
+		options.ResponseCodes = "200-299"
+	}
+	if options.MaxTime == 0 {
+		options.MaxTime = 300 * time.Second
+	}
+	report := fuzz.PerformPathFuzz(s.context(ctx, options.OnEvent), options.Target, options.Pathlist,
+		!options.IncludeBaseContentMatches, options.ResponseCodes, int(options.MaxTime/time.Second))
+	return &report, result(ctx, "path fuzz", options.Target, report.Errors)
+}
+
+// BrowserbaseOptions configure the Browserbase sessions of a route capture.
+type BrowserbaseOptions struct {
+	Token   string
+	Project string
+	// Proxy routes the session through a Browserbase proxy, in one of the ProxyCountries when set.
+	Proxy          bool
+	ProxyCountries []string
+}
+
+// RouteCaptureOptions configure a route capture, the equivalent of `webscan routecapture`.
+type RouteCaptureOptions struct {
+	Target string
+	// Method is the way the page is loaded, with a request, a headless browser or Browserbase.
+	Method PageCaptureMethod
+	// IncludeOtherDomains also reports the routes and URLs that do not share the domain of the target, which are
+	// skipped by default.
+	IncludeOtherDomains bool
+	// CaptureStaticAssets also reports the routes and URLs of static assets such as images, css and js files.
+	CaptureStaticAssets bool
+	// Timeout is the timeout of the capture, 30 seconds when zero.
+	Timeout time.Duration
+	// MinDOMStabilizeTime is the minimum time to wait for the DOM of the page to stabilize, 5 seconds when zero.
+	MinDOMStabilizeTime time.Duration
+	// Insecure allows insecure connections to the target when the page is loaded with a request.
+	Insecure bool
+	// BrowserPath is the browser executable of the headless browser, downloaded when empty.
+	BrowserPath string
+	// Browserbase configures the sessions of the Browserbase method.
+	Browserbase *BrowserbaseOptions
+}
+
+// RouteCapture loads a page of the target and reports the routes and URLs it references.
+func (s *Scanner) RouteCapture(ctx context.Context, options RouteCaptureOptions) (*RouteCaptureReport, error) {
+	if err := required("Target", options.Target); err != nil {
+		return nil, err
+	}
+	if err := required("Method", string(options.Method)); err != nil {
+		return nil, err
+	}
+	if options.Timeout == 0 {
+		options.Timeout = 30 * time.Second
+	}
+	if options.MinDOMStabilizeTime == 0 {
+		options.MinDOMStabilizeTime = 5 * time.Second
+	}
+	var browserPath *string
+	if options.BrowserPath != "" {
+		browserPath = &options.BrowserPath
+	}
+	var token, project *string
+	var browserbaseOptions *[]browserbase.Option
+	if options.Method == PageCaptureMethodBrowserbase {
+		if options.Browserbase == nil {
+			return nil, &OptionError{Option: "Browserbase", Err: errors.New("required by the BROWSERBASE method")}
+		}
+		token, project = &options.Browserbase.Token, &options.Browserbase.Project
+		sessionOptions := []browserbase.Option{}
+		if options.Browserbase.Proxy && len(options.Browserbase.ProxyCountries) > 0 {
+			sessionOptions = append(sessionOptions, browserbase.WithProxyCountries(options.Browserbase.ProxyCountries))
+		} else if options.Browserbase.Proxy {
+			sessionOptions = append(sessionOptions, browserbase.WithProxy())
+		}
+		browserbaseOptions = &sessionOptions
+	}
+	report := routecapture.PerformRouteCapture(s.context(ctx, nil), options.Target, options.Method, !options.IncludeOtherDomains,
+		options.CaptureStaticAssets, int(options.Timeout/time.Second), int(options.MinDOMStabilizeTime/time.Second),
+		options.Insecure, browserPath, token, project, browserbaseOptions)
+	return &report, result(ctx, "route capture", options.Target, report.Errors)
+}
@@ -0,0 +1,75 @@
+package webscan
+
+import (
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/spider"
+	"github.com/Method-Security/webscan/internal/stream"
+	"github.com/Method-Security/webscan/internal/vuln"
+	"github.com/Method-Security/webscan/internal/webserver"
+)
+
+// The reports and options of the scans are the types of the CLI's output, most of them defined by the fern definition.
+type (
+	AuthProfile        = webscan.AuthProfile
+	ScopeDefinition    = webscan.ScopeDefinition
+	RoutesReport       = webscan.RoutesReport
+	FingerprintReport  = webscan.FingerprintReport
+	WebServerReport    = webscan.WebServerReport
+	FuzzPathReport     = webscan.FuzzPathReport
+	URLDetails         = webscan.UrlDetails
+	WebServer          = webscan.WebServer
+	RouteCaptureReport = webscan.RouteCaptureReport
+	RequestReport      = webscan.RequestReport
+	RequestBatchReport = webscan.RequestBatchReport
+	RequestResult      = webscan.RequestResult
+	RequestParams      = webscan.RequestParams
+	AuthzReport        = webscan.AuthzReport
+	AuthzIdentity      = webscan.AuthzIdentity
+	ServerType         = webscan.ServerType
+	ProbeType          = webscan.ProbeType
+	ModuleName         = webscan.ModuleName
+	PageCaptureMethod  = webscan.PageCaptureMethod
+
+	ProbeReport          = webserver.ProbeReport
+	SpiderReport         = spider.WebSpiderReport
+	SpiderLink           = spider.LinkDetails
+	VulnerabilityReport  = vuln.VulnerabilityReport
+	VulnerabilityFinding = vuln.VulnerabilityFinding
+)
+
+// The values of the enums of the scan options.
+const (
+	ServerTypeApache             = webscan.ServerTypeApache
+	ServerTypeNginx              = webscan.ServerTypeNginx
+	ProbeTypeEnumerate           = webscan.ProbeTypeEnumerate
+	ProbeTypeValidate            = webscan.ProbeTypeValidate
+	PageCaptureMethodRequest     = webscan.PageCaptureMethodRequest
+	PageCaptureMethodBrowser     = webscan.PageCaptureMethodBrowser
+	PageCaptureMethodBrowserbase = webscan.PageCaptureMethodBrowserbase
+)
+
+// EventType is the type of a streamed result.
+type EventType string
+
+// The types of the streamed results, and the type of their Data.
+const (
+	// EventVulnerability is a VulnerabilityFinding of Vuln and AppFingerprint.
+	EventVulnerability EventType = stream.TypeVulnerability
+	// EventPath is a *URLDetails discovered by PathFuzz.
+	EventPath EventType = stream.TypePath
+	// EventLink is a SpiderLink crawled by Spider.
+	EventLink EventType = stream.TypeLink
+	// EventAttempt is a WebServer with the single attempt of a module of WebServer.
+	EventAttempt EventType = stream.TypeAttempt
+	// EventRequest is a *RequestResult of RequestBatch.
+	EventRequest EventType = stream.TypeRequest
+)
+
+// Event is a result streamed while a scan runs. The callbacks receiving events are never called concurrently.
+type Event struct {
+	Type      EventType
+	Timestamp time.Time
+	Data      interface{}
+}
@@ -0,0 +1,138 @@
+// Package webscan is the public Go API of webscan, for services that embed its scanners instead of running the CLI.
+//
+// A Scanner holds the configuration shared by every scan, the equivalent of the CLI's global flags: the HTTP client
+// options, the authentication profile, the scope and the replayed traffic. Each scan is a method of the Scanner taking
+// a context and an options struct, and returns the same report as the matching CLI command. Scans that stream their
+// results also pass each result to the OnEvent callback of their options as soon as it arrives.
+//
+// Scans return their report even when it lists errors, along with a *ScanError carrying them, so that partial results
+// are never lost. Invalid options are reported with an *OptionError matching ErrInvalidOptions, and a canceled context
+// with the context's error.
+package webscan
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Method-Security/webscan/internal/auth"
+	"github.com/Method-Security/webscan/internal/har"
+	"github.com/Method-Security/webscan/internal/httpclient"
+	"github.com/Method-Security/webscan/internal/scope"
+	"github.com/Method-Security/webscan/internal/stream"
+)
+
+// HTTPOptions configure the HTTP requests of every scan of a Scanner.
+type HTTPOptions struct {
+	// Proxy is the URL of the proxy requests are sent through, with the http, https or socks5 scheme.
+	Proxy string
+	// CACertFile is a PEM file of CA certificates trusted in addition to the system roots.
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are the PEM files of the client certificate presented for mTLS.
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables the verification of the certificates of targets, which are verified by default.
+	// The webserver scans and the TLS inspection of fingerprints skip verification regardless.
+	InsecureSkipVerify bool
+	// Headers are added to every request that does not set them itself, as "Name: value".
+	Headers []string
+	// UserAgent replaces the user agent of every request that does not set one itself.
+	UserAgent string
+	// Retries is the number of times a request is retried after a network error or a 429, 502, 503 or 504 response.
+	Retries int
+	// Timeout is the timeout of requests for scans without a timeout of their own. Zero disables the timeout.
+	Timeout time.Duration
+	// RateLimit is the maximum number of requests per second across all hosts. Zero disables the limit.
+	RateLimit float64
+	// MaxConcurrencyPerHost is the maximum number of concurrent requests to a host. Zero disables the limit.
+	MaxConcurrencyPerHost int
+	// Delay is the minimum time between the start of consecutive requests to a host, and Jitter the maximum random
+	// time added to it.
+	Delay  time.Duration
+	Jitter time.Duration
+}
+
+// Options configure a Scanner.
+type Options struct {
+	HTTP HTTPOptions
+	// AuthProfile authenticates the requests of every scan. References to environment variables are not expanded.
+	AuthProfile *AuthProfile
+	// Scope blocks the requests that are not in scope.
+	Scope *ScopeDefinition
+	// Replay is the HAR file or fixture directory requests are answered from instead of the network.
+	Replay string
+}
+
+// Scanner runs scans sharing the configuration of its Options. It is safe for concurrent use.
+type Scanner struct {
+	factory *httpclient.Factory
+	scope   *scope.Scope
+}
+
+// New validates the options and creates a Scanner. OAuth2 tokens of the authentication profile are fetched with ctx.
+func New(ctx context.Context, options Options) (*Scanner, error) {
+	factory, err := httpclient.NewFactory(httpclient.Options(options.HTTP))
+	if err != nil {
+		return nil, &OptionError{Option: "HTTP", Err: err}
+	}
+	if options.AuthProfile != nil {
+		authenticator, err := auth.NewAuthenticator(ctx, options.AuthProfile, factory)
+		if err != nil {
+			return nil, &OptionError{Option: "AuthProfile", Err: err}
+		}
+		factory = factory.WithAuthenticator(authenticator)
+	}
+	s := &Scanner{}
+	if options.Scope != nil {
+		s.scope, err = scope.New(*options.Scope)
+		if err != nil {
+			return nil, &OptionError{Option: "Scope", Err: err}
+		}
+		factory = factory.WithScope(s.scope)
+	}
+	if options.Replay != "" {
+		replayer, err := har.LoadReplayer(options.Replay)
+		if err != nil {
+			return nil, &OptionError{Option: "Replay", Err: err}
+		}
+		factory = factory.WithReplayer(replayer)
+	}
+	s.factory = factory
+	return s, nil
+}
+
+// Blocked returns the URLs that were blocked because they are not in scope, with the reason they were blocked.
+func (s *Scanner) Blocked() []string {
+	return s.scope.Blocked()
+}
+
+// context returns the context the scanners are run with, carrying the HTTP configuration of the Scanner and emitting
+// the streamed results to onEvent.
+func (s *Scanner) context(ctx context.Context, onEvent func(Event)) context.Context {
+	ctx = httpclient.WithFactory(ctx, s.factory)
+	if onEvent != nil {
+		ctx = stream.WithEmitter(ctx, stream.NewHandlerEmitter(func(record stream.Record) {
+			onEvent(Event{Type: EventType(record.Type), Timestamp: record.Timestamp, Data: record.Data})
+		}))
+	}
+	return ctx
+}
+
+// result returns the error of a scan of the target that completed with the errors of its report.
+func result(ctx context.Context, scan string, target string, scanErrors []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(scanErrors) > 0 {
+		return &ScanError{Scan: scan, Target: target, Errors: scanErrors}
+	}
+	return nil
+}
+
+// required returns an OptionError when the value of the option is empty.
+func required(option string, value string) error {
+	if value == "" {
+		return &OptionError{Option: option, Err: errors.New("required")}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package webscan
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	webscan "github.com/Method-Security/webscan/generated/go"
+	"github.com/Method-Security/webscan/internal/webserver"
+)
+
+// ProbeOptions configure a web server probe, the equivalent of `webscan webserver probe`.
+type ProbeOptions struct {
+	// Targets are the addresses probed for web servers.
+	Targets []string
+	// Timeout is the timeout of the probe of a target, 30 seconds when zero.
+	Timeout time.Duration
+}
+
+// Probe probes the targets for web servers and reports the URLs they serve.
+func (s *Scanner) Probe(ctx context.Context, options ProbeOptions) (*ProbeReport, error) {
+	if len(options.Targets) == 0 {
+		return nil, &OptionError{Option: "Targets", Err: errors.New("required")}
+	}
+	if options.Timeout == 0 {
+		options.Timeout = 30 * time.Second
+	}
+	targets := strings.Join(options.Targets, ",")
+	report, err := webserver.PerformWebServerProbe(s.context(ctx, nil), targets, options.Timeout)
+	if err != nil {
+		return &report, err
+	}
+	return &report, result(ctx, "probe", targets, report.Errors)
+}
+
+// WebServerOptions configure the modules run against web servers, the equivalent of `webscan webserver enumerate`
+// and `webscan webserver validate`.
+type WebServerOptions struct {
+	// Targets are the URLs of the web servers. Targets without a scheme are scanned with both http and https.
+	Targets []string
+	Server  ServerType
+	// Probe selects the enumeration or validation modules.
+	Probe ProbeType
+	// Modules are the modules to run, every module of the server and probe type when empty.
+	Modules []ModuleName
+	// Timeout is the timeout of the requests of the modules, 5 seconds when zero.
+	Timeout time.Duration
+	// SuccessfulOnly only reports the successful attempts of the modules.
+	SuccessfulOnly bool
+	// OnEvent receives an EventAttempt for every attempt of a module once it completed.
+	OnEvent func(Event)
+}
+
+// WebServer runs the modules of the server and probe type against the targets.
+func (s *Scanner) WebServer(ctx context.Context, options WebServerOptions) (*WebServerReport, error) {
+	if len(options.Targets) == 0 {
+		return nil, &OptionError{Option: "Targets", Err: errors.New("required")}
+	}
+	if err := required("Server", string(options.Server)); err != nil {
+		return nil, err
+	}
+	if err := required("Probe", string(options.Probe)); err != nil {
+		return nil, err
+	}
+	if options.Timeout == 0 {
+		options.Timeout = 5 * time.Second
+	}
+	targets := []string{}
+	for _, target := range options.Targets {
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			targets = append(targets, target)
+		} else {
+			targets = append(targets, "http://"+target, "https://"+target)
+		}
+	}
+	engine := webserver.NewEngine(&webscan.WebServerTypeConfig{
+		Targets:        targets,
+		Probe:          options.Probe,
+		Server:         options.Server,
+		Modules:        options.Modules,
+		Timeout:        int(options.Timeout / time.Millisecond),
+		SuccessfulOnly: options.SuccessfulOnly,
+	})
+	report, err := engine.Launch(s.context(ctx, options.OnEvent))
+	if err != nil {
+		return report, err
+	}
+	return report, result(ctx, "webserver", strings.Join(targets, ","), report.Errors)
+}